@@ -5,39 +5,178 @@
 package main
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/JackMatanky/lithos/internal/adapters/api/cli"
+	"github.com/JackMatanky/lithos/internal/adapters/spi/config"
 	"github.com/JackMatanky/lithos/internal/adapters/spi/filesystem"
+	"github.com/JackMatanky/lithos/internal/adapters/spi/interactive"
+	"github.com/JackMatanky/lithos/internal/adapters/spi/schema"
 	templaterepo "github.com/JackMatanky/lithos/internal/adapters/spi/template"
+	"github.com/JackMatanky/lithos/internal/app/note"
+	schemadomain "github.com/JackMatanky/lithos/internal/app/schema"
 	templatedomain "github.com/JackMatanky/lithos/internal/app/template"
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+	"github.com/JackMatanky/lithos/internal/shared/clock"
 )
 
 func main() {
-	// Create filesystem adapter
-	fileSystemPort := filesystem.NewLocalFileSystemAdapter()
+	adapter, err := newCLIAdapter()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(adapter.Execute(os.Args[1:]))
+}
 
-	// Create template parser and executor from domain services
-	templateParser := templatedomain.NewStaticTemplateParser()
-	templateExecutor := templatedomain.NewGoTemplateExecutor()
+// newCLIAdapter wires the adapters and domain services behind the CLI.
+func newCLIAdapter() (*cli.CobraCLIAdapter, error) {
+	// Load configuration (lithos.yaml, LITHOS_* environment, defaults)
+	configAdapter, err := config.NewConfigViperAdapter()
+	if err != nil {
+		return nil, err
+	}
 
-	// Create template engine with injected dependencies
-	templateEngine := templatedomain.NewTemplateEngine(
-		templateParser,
-		templateExecutor,
+	// Create filesystem adapter; --dry-run switches it to recording writes
+	fileSystemPort := filesystem.NewDryRunAdapter(
+		filesystem.NewLocalFileSystemAdapter(),
+	)
+
+	renderClock, err := newRenderClock(configAdapter.Config())
+	if err != nil {
+		return nil, err
+	}
+
+	// Template prompts take answers from --answer and --answers first and
+	// ask on the terminal otherwise; without a terminal they fail instead
+	promptPort := interactive.NewScriptedAdapter(
+		interactive.NewTTYAdapter(os.Stdin, os.Stderr),
 	)
 
+	templateParser, templateEngine, err := newTemplateEngine(
+		configAdapter.Config(),
+		renderClock,
+		promptPort,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create template repository adapter
 	templateRepo := templaterepo.NewFSAdapter(
 		fileSystemPort,
 		templateParser,
+		configAdapter,
+	)
+
+	// Create note writer enforcing collision policies
+	noteWriter := note.NewWriterWithClock(fileSystemPort, renderClock)
+
+	templateLinter, schemaEngine := newSchemaServices(
+		fileSystemPort,
+		configAdapter,
+		templateRepo,
 	)
 
 	// Create CLI adapter with injected dependencies
-	adapter := cli.NewCobraCLIAdapter(
-		templateEngine,
+	return cli.NewCobraCLIAdapter(cli.AdapterOptions{
+		TemplateEngine: templateEngine,
+		TemplateRepo:   templateRepo,
+		FileSystemPort: fileSystemPort,
+		ConfigPort:     configAdapter,
+		NoteWriter:     noteWriter,
+		Clock:          renderClock,
+		Linter:         templateLinter,
+		SchemaEngine:   schemaEngine,
+		PromptPort:     promptPort,
+	}), nil
+}
+
+// newSchemaServices creates the template linter behind "templates check"
+// and the schema engine behind "templates scaffold" and note validation,
+// sharing one schema registry whose schemas are loaded on first use.
+func newSchemaServices(
+	fileSystemPort spi.FileSystemPort,
+	configAdapter *config.ConfigViperAdapter,
+	templateRepo spi.TemplateRepositoryPort,
+) (*templatedomain.Linter, *schemadomain.SchemaEngine) {
+	// Create schema registry; schemas are loaded on first use
+	schemaLoader := schema.NewSchemaLoaderAdapter(fileSystemPort, configAdapter)
+	schemaRegistry := schema.NewSchemaRegistryAdapter(
+		schemaLoader,
+		configAdapter,
+	)
+
+	// Create template linter for "templates check"
+	templateLinter := templatedomain.NewLinter(
 		templateRepo,
 		fileSystemPort,
+		schemaRegistry,
 	)
-	os.Exit(adapter.Execute(os.Args[1:]))
+
+	// Create schema engine for "templates scaffold"
+	schemaEngine := schemadomain.NewSchemaEngine(
+		schemaLoader,
+		schemaRegistry,
+		schemadomain.NewSchemaValidator(),
+	)
+	return templateLinter, schemaEngine
+}
+
+// newRenderClock returns the one clock serving template functions and note
+// timestamps. It is pinned by LITHOS_NOW here, or by the --now flag once the
+// CLI parses flags.
+func newRenderClock(cfg *config.Config) (*clock.Pinnable, error) {
+	renderClock := clock.NewPinnable(clock.System())
+	pinned, isPinned, err := cfg.PinnedTime()
+	if err != nil {
+		return nil, err
+	}
+	if isPinned {
+		renderClock.Pin(pinned)
+	}
+	return renderClock, nil
+}
+
+// newTemplateEngine creates the template parser and the engine rendering
+// its templates, sharing one set of template function options.
+func newTemplateEngine(
+	cfg *config.Config,
+	renderClock *clock.Pinnable,
+	promptPort spi.PromptPort,
+) (spi.TemplateParser, *templatedomain.TemplateEngine, error) {
+	// Template date functions work in the configured time zone
+	location, err := cfg.Location()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Create template parser and executor from domain services
+	funcMapOptions := templatedomain.FuncMapOptions{
+		Location: location,
+		Clock:    renderClock,
+		Prompter: templatedomain.NewPrompter(promptPort),
+	}
+	templateParser := templatedomain.NewStaticTemplateParserWithOptions(
+		funcMapOptions,
+	)
+	renderTimeout, err := cfg.RenderTimeoutDuration()
+	if err != nil {
+		return nil, nil, err
+	}
+	templateExecutor := templatedomain.NewGoTemplateExecutorWithOptions(
+		templatedomain.ExecutorOptions{
+			Timeout:       renderTimeout,
+			MaxOutputSize: cfg.MaxOutputSize,
+		},
+	)
+
+	// Create template engine with injected dependencies
+	templateEngine := templatedomain.NewTemplateEngineWithOptions(
+		templateParser,
+		templateExecutor,
+		funcMapOptions,
+	)
+	return templateParser, templateEngine, nil
 }
@@ -0,0 +1,96 @@
+// Package cli provides CLI command implementations for the Lithos application.
+// This file contains the --answer and --answers flags, which script the
+// answers to the questions templates ask with prompt and suggester.
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+	"github.com/spf13/cobra"
+)
+
+// applyAnswerFlags hands the answers given with --answers and --answer to the
+// prompt port before any template is rendered.
+func (a *CobraCLIAdapter) applyAnswerFlags(cmd *cobra.Command) error {
+	path, err := cmd.Flags().GetString("answers")
+	if err != nil {
+		return err
+	}
+	pairs, err := cmd.Flags().GetStringArray("answer")
+	if err != nil {
+		return err
+	}
+	if path == "" && len(pairs) == 0 {
+		return nil
+	}
+
+	scripted, ok := a.promptPort.(spi.ScriptedPromptPort)
+	if !ok {
+		return errors.New("--answer and --answers are not supported here")
+	}
+	answers, err := loadAnswers(
+		path,
+		pairs,
+		cmd.InOrStdin(),
+		a.fileSystemPort,
+	)
+	if err != nil {
+		return err
+	}
+	scripted.AddAnswers(answers)
+	return nil
+}
+
+// loadAnswers merges the answers in the JSON or YAML file at path, if any,
+// with the label=value pairs, which take precedence. Answers are keyed by the
+// label of their question.
+func loadAnswers(
+	path string,
+	pairs []string,
+	stdin io.Reader,
+	fileSystemPort spi.FileSystemPort,
+) (map[string]string, error) {
+	answers := make(map[string]string)
+
+	if path != "" {
+		values, err := readDataSource(path, stdin, fileSystemPort)
+		if err != nil {
+			return nil, fmt.Errorf("--answers: %w", err)
+		}
+		labels := make([]string, 0, len(values))
+		for label := range values {
+			labels = append(labels, label)
+		}
+		sort.Strings(labels)
+		for _, label := range labels {
+			switch value := values[label].(type) {
+			case map[string]interface{}, []interface{}:
+				return nil, fmt.Errorf(
+					"--answers: the answer for %q must be a single value",
+					label,
+				)
+			case nil:
+				answers[label] = ""
+			default:
+				answers[label] = fmt.Sprint(value)
+			}
+		}
+	}
+
+	for _, pair := range pairs {
+		label, answer, found := strings.Cut(pair, "=")
+		if !found || strings.TrimSpace(label) == "" {
+			return nil, fmt.Errorf(
+				"invalid --answer value %q: expected label=answer",
+				pair,
+			)
+		}
+		answers[label] = answer
+	}
+	return answers, nil
+}
@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JackMatanky/lithos/internal/adapters/spi/interactive"
+	templaterepo "github.com/JackMatanky/lithos/internal/adapters/spi/template"
+	"github.com/JackMatanky/lithos/internal/app/note"
+	templatedomain "github.com/JackMatanky/lithos/internal/app/template"
+	"github.com/JackMatanky/lithos/internal/shared/clock"
+)
+
+func TestLoadAnswers(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		files   map[string]string
+		pairs   []string
+		stdin   string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:  "pairs",
+			pairs: []string{"Title=Weekly Sync", "Note=a=b", "Empty="},
+			want: map[string]string{
+				"Title": "Weekly Sync",
+				"Note":  "a=b",
+				"Empty": "",
+			},
+		},
+		{
+			name: "yaml file with pairs taking precedence",
+			path: "answers.yaml",
+			files: map[string]string{
+				"answers.yaml": "Title: From file\nAttendees: 3\n" +
+					"Status: ~\n",
+			},
+			pairs: []string{"Title=From flag"},
+			want: map[string]string{
+				"Title":     "From flag",
+				"Attendees": "3",
+				"Status":    "",
+			},
+		},
+		{
+			name:  "json from stdin",
+			path:  "-",
+			stdin: `{"Pick a status": "open"}`,
+			want:  map[string]string{"Pick a status": "open"},
+		},
+		{
+			name:    "answers must be single values",
+			path:    "answers.yaml",
+			files:   map[string]string{"answers.yaml": "Title: [a, b]\n"},
+			wantErr: `--answers: the answer for "Title" must be a single value`,
+		},
+		{
+			name:    "missing file",
+			path:    "missing.json",
+			wantErr: `--answers: failed to read data file "missing.json"`,
+		},
+		{
+			name:    "pair without a label",
+			pairs:   []string{"=Sync"},
+			wantErr: `invalid --answer value "=Sync": expected label=answer`,
+		},
+		{
+			name:    "pair without an answer",
+			pairs:   []string{"Title"},
+			wantErr: `invalid --answer value "Title"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := newMockFileSystemPort()
+			for path, content := range tt.files {
+				mockFS.AddFile(path, []byte(content))
+			}
+
+			got, err := loadAnswers(
+				tt.path,
+				tt.pairs,
+				strings.NewReader(tt.stdin),
+				mockFS,
+			)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("loadAnswers() error = %v, want %q",
+						err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadAnswers() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadAnswers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCobraCLIAdapter_Execute_AnswerFlags(t *testing.T) {
+	const meeting = "{{- /* lithos\n" +
+		"output: meetings/{{ prompt \"Title\" | slug }}.md\n*/ -}}\n" +
+		"# {{ prompt \"Title\" }}\n" +
+		"Status: {{ suggester \"Status\" (list \"Open\" \"Done\") }}\n"
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{
+			name: "answers from flags",
+			args: []string{
+				"new", "meeting",
+				"--answer", "Title=Weekly Sync",
+				"--answer", "Status=Open",
+			},
+			want: "# Weekly Sync\nStatus: Open\n",
+		},
+		{
+			name: "unanswered question fails without a terminal",
+			args: []string{
+				"new", "meeting",
+				"--answer", "Title=Weekly Sync",
+			},
+			wantCode: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promptPort := interactive.NewScriptedAdapter(nil)
+			opts := templatedomain.FuncMapOptions{
+				Prompter: templatedomain.NewPrompter(promptPort),
+			}
+			parser := templatedomain.NewStaticTemplateParserWithOptions(opts)
+			engine := templatedomain.NewTemplateEngineWithOptions(
+				parser,
+				templatedomain.NewGoTemplateExecutor(),
+				opts,
+			)
+
+			mockFS := newMockFileSystemPort()
+			addVaultTemplate(mockFS, "meeting.md", meeting)
+			adapter := NewCobraCLIAdapter(AdapterOptions{
+				TemplateEngine: engine,
+				TemplateRepo: templaterepo.NewFSAdapter(
+					mockFS,
+					parser,
+					newMockConfigPort(),
+				),
+				FileSystemPort: mockFS,
+				ConfigPort:     newMockConfigPort(),
+				NoteWriter:     note.NewWriter(mockFS),
+				Clock:          clock.NewPinnable(nil),
+				PromptPort:     promptPort,
+			})
+
+			if code := adapter.Execute(tt.args); code != tt.wantCode {
+				t.Fatalf("Execute() exit code = %d, want %d",
+					code, tt.wantCode)
+			}
+			const path = "/vault/meetings/weekly-sync.md"
+			got, written := mockFS.GetWrittenFiles()[path]
+			if written != (tt.want != "") {
+				t.Fatalf("note written = %v, want %v",
+					written, tt.want != "")
+			}
+			if written && string(got) != tt.want {
+				t.Errorf("note = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
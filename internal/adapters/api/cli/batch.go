@@ -0,0 +1,336 @@
+// Package cli provides CLI command implementations for the Lithos application.
+// This file contains the --batch mode of the 'new' command, which creates one
+// note per row of a CSV, JSON, or JSONL file.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/JackMatanky/lithos/internal/app/note"
+	"github.com/JackMatanky/lithos/internal/app/template"
+	"github.com/JackMatanky/lithos/internal/domain"
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+)
+
+// batchSummary counts the outcome of the rows of a batch.
+type batchSummary struct {
+	created int
+	skipped int
+	failed  int
+}
+
+// String renders the summary line printed at the end of a batch.
+func (s batchSummary) String() string {
+	return fmt.Sprintf(
+		"Batch: %d created, %d skipped, %d failed",
+		s.created,
+		s.skipped,
+		s.failed,
+	)
+}
+
+// record counts the outcome err of the batch row at index i, reporting
+// skipped and failed rows to out. It returns an error when the batch has to
+// stop, which is at the first failed row with failFast.
+func (s *batchSummary) record(
+	out io.Writer,
+	i int,
+	err error,
+	failFast bool,
+) error {
+	switch {
+	case err == nil:
+		s.created++
+	case errors.Is(err, fs.ErrExist):
+		s.skipped++
+		fmt.Fprintf(out, "Skipped row %d: %v\n", i+1, err)
+	case failFast:
+		s.failed++
+		fmt.Fprintln(out, s)
+		return fmt.Errorf("batch stopped at row %d: %w", i+1, err)
+	default:
+		s.failed++
+		fmt.Fprintf(out, "Failed row %d: %v\n", i+1, err)
+	}
+	return nil
+}
+
+// err returns an error when any of the rows of a batch failed.
+func (s batchSummary) err(rows int) error {
+	if s.failed > 0 {
+		return fmt.Errorf("%d of %d batch rows failed", s.failed, rows)
+	}
+	return nil
+}
+
+// batchInput is everything a batch renders its rows from.
+type batchInput struct {
+	tmpl       *domain.Template
+	bundle     []bundleChild
+	rows       []map[string]interface{}
+	fileValues map[string]interface{} // --data values shared by every row
+	setValues  map[string]interface{} // --set values shared by every row
+}
+
+// renderContext returns the render context of row: the --data values, then
+// the row, then the --set values.
+func (in batchInput) renderContext(
+	row map[string]interface{},
+) domain.RenderContext {
+	rc := domain.NewRenderContext()
+	rc.Merge(in.fileValues)
+	rc.Merge(row)
+	rc.Merge(in.setValues)
+	return rc
+}
+
+// executeBatchCommand creates one note per row of the --batch file. A row
+// whose target already exists is skipped, and a row that fails is reported
+// and the batch carries on, unless --fail-fast is set. The summary is written
+// to out, and an error is returned when any row failed.
+func executeBatchCommand(
+	out io.Writer,
+	templateRef string,
+	opts newOptions,
+	stdin io.Reader,
+	templateEngine *template.TemplateEngine,
+	templateRepo spi.TemplateRepositoryPort,
+	fileSystemPort spi.FileSystemPort,
+	configPort spi.ConfigPort,
+	noteWriter *note.Writer,
+	validator *noteValidator,
+) error {
+	ctx := context.Background()
+
+	policy, err := opts.collisionPolicy()
+	if err != nil {
+		return err
+	}
+
+	input, err := loadBatchInput(
+		ctx,
+		templateRef,
+		opts,
+		stdin,
+		templateRepo,
+		fileSystemPort,
+	)
+	if err != nil {
+		return err
+	}
+
+	var summary batchSummary
+	for i, row := range input.rows {
+		rowErr := createNote(
+			ctx,
+			templateRef,
+			input.tmpl,
+			input.bundle,
+			input.renderContext(row),
+			"",
+			policy,
+			templateEngine,
+			fileSystemPort,
+			configPort,
+			noteWriter,
+			validator,
+			stampFor(opts, configPort),
+		)
+		if err := summary.record(out, i, rowErr, opts.failFast); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(out, summary)
+	return summary.err(len(input.rows))
+}
+
+// loadBatchInput reads the data shared by every row and the rows of the
+// --batch file, before loading the template and its bundle.
+func loadBatchInput(
+	ctx context.Context,
+	templateRef string,
+	opts newOptions,
+	stdin io.Reader,
+	templateRepo spi.TemplateRepositoryPort,
+	fileSystemPort spi.FileSystemPort,
+) (batchInput, error) {
+	var input batchInput
+	var err error
+
+	// Collect the data shared by every row before touching the template
+	input.fileValues, input.setValues, err = loadSharedData(
+		opts.data,
+		stdin,
+		fileSystemPort,
+	)
+	if err != nil {
+		return batchInput{}, err
+	}
+
+	input.rows, err = readBatchRows(opts.batch, fileSystemPort)
+	if err != nil {
+		return batchInput{}, err
+	}
+
+	input.tmpl, err = loadTemplate(ctx, templateRef, templateRepo)
+	if err != nil {
+		return batchInput{}, fmt.Errorf(
+			"failed to load template %q: %w",
+			templateRef,
+			err,
+		)
+	}
+
+	input.bundle, err = loadBundle(ctx, input.tmpl, templateRepo)
+	if err != nil {
+		return batchInput{}, err
+	}
+	return input, nil
+}
+
+// loadSharedData reads the --data and --set values that every row of a batch
+// is merged with. They are kept apart so that rows override the data file
+// and --set overrides rows.
+func loadSharedData(
+	opts dataOptions,
+	stdin io.Reader,
+	fileSystemPort spi.FileSystemPort,
+) (map[string]interface{}, map[string]interface{}, error) {
+	var fileValues map[string]interface{}
+	if opts.dataPath != "" {
+		values, err := readDataSource(opts.dataPath, stdin, fileSystemPort)
+		if err != nil {
+			return nil, nil, err
+		}
+		fileValues = values
+	}
+
+	setValues, err := parseSetPairs(opts.setPairs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fileValues, setValues, nil
+}
+
+// readBatchRows reads the rows of the batch file at path. The format is
+// chosen by extension: .csv, .json, or .jsonl.
+func readBatchRows(
+	path string,
+	fileSystemPort spi.FileSystemPort,
+) ([]map[string]interface{}, error) {
+	content, err := fileSystemPort.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch file %q: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return decodeCSVRows(path, content)
+	case ".json":
+		return decodeJSONRows(path, content)
+	case ".jsonl":
+		return decodeJSONLRows(path, content)
+	default:
+		return nil, fmt.Errorf(
+			"unsupported batch file %q: expected .csv, .json, or .jsonl",
+			path,
+		)
+	}
+}
+
+// decodeCSVRows decodes CSV with a header row into one data map per record,
+// keyed by column name. Empty cells are left out so that they count as
+// missing values.
+func decodeCSVRows(
+	source string,
+	content []byte,
+) ([]map[string]interface{}, error) {
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV data in %s: %w", source, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf(
+			"invalid CSV data in %s: missing header row",
+			source,
+		)
+	}
+
+	header := records[0]
+	seen := make(map[string]bool, len(header))
+	for i, column := range header {
+		column = strings.TrimSpace(column)
+		if column == "" || seen[column] {
+			return nil, fmt.Errorf(
+				"invalid CSV data in %s: column %d: empty or duplicate name %q",
+				source,
+				i+1,
+				column,
+			)
+		}
+		seen[column] = true
+		header[i] = column
+	}
+
+	rows := make([]map[string]interface{}, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]interface{}, len(header))
+		for i, value := range record {
+			if value != "" {
+				row[header[i]] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// decodeJSONRows decodes a JSON array of objects into one data map per
+// object.
+func decodeJSONRows(
+	source string,
+	content []byte,
+) ([]map[string]interface{}, error) {
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(content, &rows); err != nil {
+		return nil, fmt.Errorf(
+			"invalid JSON data in %s: expected an array of objects: %w",
+			source,
+			err,
+		)
+	}
+	return rows, nil
+}
+
+// decodeJSONLRows decodes one JSON object per line into one data map per
+// line. Blank lines are ignored.
+func decodeJSONLRows(
+	source string,
+	content []byte,
+) ([]map[string]interface{}, error) {
+	var rows []map[string]interface{}
+	for i, line := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		row, err := decodeJSONData(
+			fmt.Sprintf("%s line %d", source, i+1),
+			[]byte(line),
+		)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	templaterepo "github.com/JackMatanky/lithos/internal/adapters/spi/template"
+	"github.com/JackMatanky/lithos/internal/app/note"
+)
+
+func TestReadBatchRows(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    []map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:    "csv with header row",
+			path:    "rows.csv",
+			content: "name, email\nAda,ada@example.com\nGrace,\n",
+			want: []map[string]interface{}{
+				{"name": "Ada", "email": "ada@example.com"},
+				{"name": "Grace"},
+			},
+		},
+		{
+			name:    "json array",
+			path:    "rows.json",
+			content: `[{"name": "Ada", "year": 1815}, {"name": "Grace"}]`,
+			want: []map[string]interface{}{
+				{"name": "Ada", "year": float64(1815)},
+				{"name": "Grace"},
+			},
+		},
+		{
+			name:    "jsonl skips blank lines",
+			path:    "rows.jsonl",
+			content: "{\"name\": \"Ada\"}\n\n{\"name\": \"Grace\"}\n",
+			want: []map[string]interface{}{
+				{"name": "Ada"},
+				{"name": "Grace"},
+			},
+		},
+		{
+			name:    "csv with ragged row",
+			path:    "rows.csv",
+			content: "name,email\nAda\n",
+			wantErr: "wrong number of fields",
+		},
+		{
+			name:    "csv with duplicate column",
+			path:    "rows.csv",
+			content: "name,name\nAda,Grace\n",
+			wantErr: `column 2: empty or duplicate name "name"`,
+		},
+		{
+			name:    "empty csv",
+			path:    "rows.csv",
+			wantErr: "missing header row",
+		},
+		{
+			name:    "json object instead of array",
+			path:    "rows.json",
+			content: `{"name": "Ada"}`,
+			wantErr: "expected an array of objects",
+		},
+		{
+			name:    "invalid jsonl line",
+			path:    "rows.jsonl",
+			content: "{\"name\": \"Ada\"}\n[1, 2]\n",
+			wantErr: "rows.jsonl line 2",
+		},
+		{
+			name:    "unsupported extension",
+			path:    "rows.txt",
+			wantErr: "expected .csv, .json, or .jsonl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := newMockFileSystemPort()
+			mockFS.AddFile(tt.path, []byte(tt.content))
+
+			rows, err := readBatchRows(tt.path, mockFS)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readBatchRows() error = %v, want %q",
+						err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readBatchRows() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("readBatchRows() = %v, want %v", rows, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteBatchCommand(t *testing.T) {
+	const contactTemplate = "{{- /* lithos\n" +
+		"output: people/{{ .name | slug }}.md\n" +
+		"params:\n" +
+		"  - name: name\n" +
+		"    required: true\n" +
+		"  - name: role\n" +
+		"    default: contact\n" +
+		"*/ -}}\n" +
+		"# {{.name}} ({{.role}}, {{.source}})\n"
+
+	tests := []struct {
+		name     string
+		rows     string
+		opts     newOptions
+		existing map[string]string
+		want     map[string]string
+		summary  string
+		wantErr  string
+	}{
+		{
+			name: "one note per row",
+			rows: "name,role\nAda,author\nGrace,\n",
+			want: map[string]string{
+				"/vault/people/ada.md":   "# Ada (author, csv)\n",
+				"/vault/people/grace.md": "# Grace (contact, csv)\n",
+			},
+			summary: "Batch: 2 created, 0 skipped, 0 failed\n",
+		},
+		{
+			name:     "existing targets are skipped",
+			rows:     "name\nAda\nGrace\n",
+			existing: map[string]string{"/vault/people/ada.md": "old"},
+			want: map[string]string{
+				"/vault/people/ada.md":   "old",
+				"/vault/people/grace.md": "# Grace (contact, csv)\n",
+			},
+			summary: "Skipped row 1: ",
+		},
+		{
+			name:     "force overwrites existing targets",
+			rows:     "name\nAda\n",
+			opts:     newOptions{force: true},
+			existing: map[string]string{"/vault/people/ada.md": "old"},
+			want: map[string]string{
+				"/vault/people/ada.md": "# Ada (contact, csv)\n",
+			},
+			summary: "Batch: 1 created, 0 skipped, 0 failed\n",
+		},
+		{
+			name: "failed rows do not stop the batch",
+			rows: "name,role\n,author\nGrace,\n",
+			want: map[string]string{
+				"/vault/people/grace.md": "# Grace (contact, csv)\n",
+			},
+			summary: "Batch: 1 created, 0 skipped, 1 failed\n",
+			wantErr: "1 of 2 batch rows failed",
+		},
+		{
+			name:    "fail-fast stops at the first failed row",
+			rows:    "name,role\n,author\nGrace,\n",
+			opts:    newOptions{failFast: true},
+			want:    map[string]string{},
+			summary: "Batch: 0 created, 0 skipped, 1 failed\n",
+			wantErr: "batch stopped at row 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := newMockFileSystemPort()
+			addVaultTemplate(mockFS, "contact.md", contactTemplate)
+			mockFS.AddFile("rows.csv", []byte(tt.rows))
+			for path, content := range tt.existing {
+				mockFS.AddFile(path, []byte(content))
+			}
+
+			opts := tt.opts
+			opts.batch = "rows.csv"
+			opts.data.setPairs = []string{"source=csv"}
+
+			var out bytes.Buffer
+			err := executeBatchCommand(
+				&out,
+				"contact",
+				opts,
+				strings.NewReader(""),
+				createTemplateEngine(),
+				templaterepo.NewFSAdapter(
+					mockFS,
+					createTemplateParser(),
+					newMockConfigPort(),
+				),
+				mockFS,
+				newMockConfigPort(),
+				note.NewWriter(mockFS),
+				nil,
+			)
+
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("executeBatchCommand() unexpected error = %v", err)
+			}
+			if tt.wantErr != "" &&
+				(err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("executeBatchCommand() error = %v, want %q",
+					err, tt.wantErr)
+			}
+			if !strings.Contains(out.String(), tt.summary) {
+				t.Errorf("output = %q, want it to contain %q",
+					out.String(), tt.summary)
+			}
+
+			written := make(map[string]string)
+			for path, content := range mockFS.GetWrittenFiles() {
+				if strings.HasPrefix(path, "/vault/people/") {
+					written[path] = string(content)
+				}
+			}
+			if !reflect.DeepEqual(written, tt.want) {
+				t.Errorf("notes = %v, want %v", written, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+// Package cli provides CLI command implementations for the Lithos application.
+// This file contains template bundles: further notes a template declares to
+// be created, and written, together with its own note.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/JackMatanky/lithos/internal/app/note"
+	"github.com/JackMatanky/lithos/internal/app/template"
+	"github.com/JackMatanky/lithos/internal/domain"
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+)
+
+// bundleParentKey is the template data key under which the notes of a bundle
+// find the note of the template that declares the bundle.
+const bundleParentKey = "parent"
+
+// bundleChild is a bundle entry with its template loaded.
+type bundleChild struct {
+	entry domain.BundleEntry
+	tmpl  *domain.Template
+}
+
+// plannedNote is a rendered and validated note with the path it is written
+// to, once every note of a bundle is known to be good.
+type plannedNote struct {
+	path    string
+	content string
+}
+
+// loadBundle loads the templates of the bundle that tmpl declares. Bundles
+// do not nest, so a child template that declares its own bundle is an error
+// rather than being silently ignored.
+func loadBundle(
+	ctx context.Context,
+	tmpl *domain.Template,
+	templateRepo spi.TemplateRepositoryPort,
+) ([]bundleChild, error) {
+	children := make([]bundleChild, 0, len(tmpl.Header.Bundle))
+	for _, entry := range tmpl.Header.Bundle {
+		child, err := loadTemplate(ctx, entry.Template, templateRepo)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to load bundle template %q: %w",
+				entry.Template,
+				err,
+			)
+		}
+		if len(child.Header.Bundle) > 0 {
+			return nil, fmt.Errorf(
+				"bundle template %q declares its own bundle; "+
+					"bundles do not nest",
+				entry.Template,
+			)
+		}
+		children = append(children, bundleChild{entry: entry, tmpl: child})
+	}
+	return children, nil
+}
+
+// planNote resolves the path tmpl's note is written to under policy, renders
+// tmpl for that target, and validates the result, without writing anything.
+// With stamp, the note's frontmatter records tmpl after validation, so the
+// stamp never has to be declared by the note's schema.
+func planNote(
+	ctx context.Context,
+	templateRef string,
+	tmpl *domain.Template,
+	rc domain.RenderContext,
+	output string,
+	policy note.CollisionPolicy,
+	templateEngine *template.TemplateEngine,
+	configPort spi.ConfigPort,
+	noteWriter *note.Writer,
+	validator *noteValidator,
+	stamp bool,
+) (plannedNote, error) {
+	vault, err := vaultInfo(configPort)
+	if err != nil {
+		return plannedNote{}, err
+	}
+	rc.Vault = vault
+
+	outputPath, err := resolveOutputPath(
+		ctx,
+		output,
+		tmpl,
+		rc,
+		templateEngine,
+		configPort,
+	)
+	if err != nil {
+		return plannedNote{}, err
+	}
+
+	target, err := noteWriter.ResolveTarget(outputPath, policy)
+	if err != nil {
+		return plannedNote{}, writeError(outputPath, err)
+	}
+	rc.Target = targetInfo(target, configPort)
+
+	renderedContent, err := templateEngine.ExecuteParsedTemplate(ctx, tmpl, rc)
+	if err != nil {
+		return plannedNote{}, fmt.Errorf(
+			"failed to execute template %q: %w",
+			templateRef,
+			err,
+		)
+	}
+
+	// Refuse notes that do not match their schema
+	if err := validator.Validate(ctx, renderedContent); err != nil {
+		return plannedNote{}, err
+	}
+
+	if stamp {
+		renderedContent, err = stampNote(renderedContent, target, tmpl)
+		if err != nil {
+			return plannedNote{}, err
+		}
+	}
+
+	return plannedNote{path: target, content: renderedContent}, nil
+}
+
+// stampNote records tmpl in the frontmatter of content, the note rendered
+// for target.
+func stampNote(
+	content string,
+	target string,
+	tmpl *domain.Template,
+) (string, error) {
+	stamped, err := note.StampProvenance(content, provenance(tmpl))
+	if err != nil {
+		return "", fmt.Errorf("failed to stamp note %q: %w", target, err)
+	}
+	return stamped, nil
+}
+
+// planBundle renders and validates every child of a bundle for the parent
+// note at parentPath. Each child sees the parent's data, then its entry's
+// data, then a "parent" map with the parent note's name and vault-relative
+// path for linking back to it.
+func planBundle(
+	ctx context.Context,
+	children []bundleChild,
+	rc domain.RenderContext,
+	parentPath string,
+	policy note.CollisionPolicy,
+	templateEngine *template.TemplateEngine,
+	configPort spi.ConfigPort,
+	noteWriter *note.Writer,
+	validator *noteValidator,
+	stamp bool,
+) ([]plannedNote, error) {
+	parent := parentData(parentPath, configPort)
+
+	notes := make([]plannedNote, 0, len(children))
+	for _, child := range children {
+		childRC := domain.NewRenderContext()
+		childRC.Merge(rc.Data)
+		childRC.Merge(child.entry.Data)
+		childRC.Merge(map[string]interface{}{bundleParentKey: parent})
+
+		tmpl := child.tmpl
+		if child.entry.Output != "" {
+			override := *tmpl
+			override.Header.Output = child.entry.Output
+			tmpl = &override
+		}
+
+		planned, err := planNote(
+			ctx,
+			child.entry.Template,
+			tmpl,
+			childRC,
+			"",
+			policy,
+			templateEngine,
+			configPort,
+			noteWriter,
+			validator,
+			stamp,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"bundle note %q: %w",
+				child.entry.Template,
+				err,
+			)
+		}
+		notes = append(notes, planned)
+	}
+	return notes, nil
+}
+
+// provenance is the stamp of the notes generated from tmpl, matching what
+// their renders see as {{.Template}}.
+func provenance(tmpl *domain.Template) note.Provenance {
+	info := template.DescribeTemplate(tmpl)
+	return note.Provenance{
+		Template: info.ID,
+		Hash:     info.Hash,
+		Version:  lithosVersion,
+	}
+}
+
+// parentData describes the note at path to the children of its bundle: its
+// name, and its path relative to the vault without extension, as wikilinks
+// expect.
+func parentData(
+	path string,
+	configPort spi.ConfigPort,
+) map[string]interface{} {
+	target := targetInfo(path, configPort)
+	return map[string]interface{}{
+		"name": target.Basename,
+		"path": strings.TrimSuffix(target.Path, filepath.Ext(target.Path)),
+	}
+}
+
+// checkDistinctTargets rejects a set of notes in which two notes would be
+// written to the same path.
+func checkDistinctTargets(notes []plannedNote) error {
+	seen := make(map[string]bool, len(notes))
+	for _, planned := range notes {
+		if seen[planned.path] {
+			return fmt.Errorf(
+				"bundle writes %q more than once; "+
+					"give its notes distinct output paths",
+				planned.path,
+			)
+		}
+		seen[planned.path] = true
+	}
+	return nil
+}
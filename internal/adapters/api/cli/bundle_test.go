@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	templaterepo "github.com/JackMatanky/lithos/internal/adapters/spi/template"
+	"github.com/JackMatanky/lithos/internal/app/note"
+)
+
+func TestExecuteNewCommand_Bundle(t *testing.T) {
+	const projectTemplate = "{{- /* lithos\n" +
+		"output: projects/{{ .title | slug }}.md\n" +
+		"bundle:\n" +
+		"  - template: meeting\n" +
+		"    data:\n" +
+		"      kind: kickoff\n" +
+		"  - template: tasks\n" +
+		"    output: projects/{{ .title | slug }}-tasks.md\n" +
+		"*/ -}}\n" +
+		"# {{.title}}\n"
+	const meetingTemplate = "{{- /* lithos\n" +
+		"output: meetings/{{ .title | slug }}-{{ .kind }}.md\n" +
+		"params:\n" +
+		"  - name: kind\n" +
+		"    required: true\n" +
+		"*/ -}}\n" +
+		"# {{.title}} {{.kind}}\nProject: {{ wikilink .parent.path }}\n"
+	const tasksTemplate = "# Tasks for {{ wikilink .parent.path " +
+		".parent.name }}\n"
+
+	tests := []struct {
+		name      string
+		templates map[string]string
+		existing  map[string]string
+		want      map[string]string
+		wantErr   string
+	}{
+		{
+			name: "children link back to the parent",
+			templates: map[string]string{
+				"meeting.md": meetingTemplate,
+				"tasks.md":   tasksTemplate,
+			},
+			want: map[string]string{
+				"/vault/projects/launch.md": "# Launch\n",
+				"/vault/meetings/launch-kickoff.md": "# Launch kickoff\n" +
+					"Project: [[projects/launch]]\n",
+				"/vault/projects/launch-tasks.md": "# Tasks for " +
+					"[[projects/launch|launch]]\n",
+			},
+		},
+		{
+			name: "failed child writes nothing",
+			templates: map[string]string{
+				"meeting.md": meetingTemplate,
+				"tasks.md": "{{- /* lithos\n" +
+					"params:\n" +
+					"  - name: owner\n" +
+					"    required: true\n" +
+					"*/ -}}\n",
+			},
+			want:    map[string]string{},
+			wantErr: "bundle note \"tasks\"",
+		},
+		{
+			name: "existing child target writes nothing",
+			templates: map[string]string{
+				"meeting.md": meetingTemplate,
+				"tasks.md":   tasksTemplate,
+			},
+			existing: map[string]string{
+				"/vault/projects/launch-tasks.md": "old",
+			},
+			want: map[string]string{
+				"/vault/projects/launch-tasks.md": "old",
+			},
+			wantErr: "use --force to overwrite",
+		},
+		{
+			name: "notes with the same target",
+			templates: map[string]string{
+				"meeting.md": "{{- /* lithos\n" +
+					"output: projects/{{ .title | slug }}.md\n" +
+					"*/ -}}\n",
+				"tasks.md": tasksTemplate,
+			},
+			want:    map[string]string{},
+			wantErr: "more than once",
+		},
+		{
+			name: "nested bundle",
+			templates: map[string]string{
+				"meeting.md": meetingTemplate,
+				"tasks.md": "{{- /* lithos\n" +
+					"bundle:\n" +
+					"  - template: meeting\n" +
+					"*/ -}}\n",
+			},
+			want:    map[string]string{},
+			wantErr: "bundles do not nest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := newMockFileSystemPort()
+			addVaultTemplate(mockFS, "project.md", projectTemplate)
+			for rel, content := range tt.templates {
+				addVaultTemplate(mockFS, rel, content)
+			}
+			for path, content := range tt.existing {
+				mockFS.AddFile(path, []byte(content))
+			}
+
+			err := executeNewCommand(
+				"project",
+				newOptions{
+					data: dataOptions{setPairs: []string{"title=Launch"}},
+				},
+				strings.NewReader(""),
+				createTemplateEngine(),
+				templaterepo.NewFSAdapter(
+					mockFS,
+					createTemplateParser(),
+					newMockConfigPort(),
+				),
+				mockFS,
+				newMockConfigPort(),
+				note.NewWriter(mockFS),
+				nil,
+				nil,
+			)
+
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("executeNewCommand() unexpected error = %v", err)
+			}
+			if tt.wantErr != "" &&
+				(err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("executeNewCommand() error = %v, want %q",
+					err, tt.wantErr)
+			}
+
+			written := make(map[string]string)
+			for path, content := range mockFS.GetWrittenFiles() {
+				if !strings.HasPrefix(path, "/vault/templates/") {
+					written[path] = string(content)
+				}
+			}
+			if !reflect.DeepEqual(written, tt.want) {
+				t.Errorf("notes = %v, want %v", written, tt.want)
+			}
+		})
+	}
+}
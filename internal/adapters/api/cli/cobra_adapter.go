@@ -5,11 +5,17 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 
+	"github.com/JackMatanky/lithos/internal/app/note"
+	"github.com/JackMatanky/lithos/internal/app/schema"
 	"github.com/JackMatanky/lithos/internal/app/template"
 	"github.com/JackMatanky/lithos/internal/ports/spi"
+	"github.com/JackMatanky/lithos/internal/shared/clock"
+	sharederrors "github.com/JackMatanky/lithos/internal/shared/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -20,20 +26,55 @@ type CobraCLIAdapter struct {
 	templateEngine *template.TemplateEngine
 	templateRepo   spi.TemplateRepositoryPort
 	fileSystemPort spi.FileSystemPort
+	configPort     spi.ConfigPort
+	noteWriter     *note.Writer
+	clock          *clock.Pinnable
+	linter         *template.Linter
+	schemaEngine   *schema.SchemaEngine
+	promptPort     spi.PromptPort
+}
+
+// AdapterOptions holds the dependencies of a CobraCLIAdapter. Fields left
+// nil leave out the features that need them, where noted.
+type AdapterOptions struct {
+	TemplateEngine *template.TemplateEngine
+	TemplateRepo   spi.TemplateRepositoryPort
+	FileSystemPort spi.FileSystemPort
+	ConfigPort     spi.ConfigPort
+	NoteWriter     *note.Writer
+
+	// Clock must be the clock shared by the template functions and note
+	// writer; the --now flag pins it.
+	Clock *clock.Pinnable
+
+	// Linter backs "templates check". When nil, the command is left out.
+	Linter *template.Linter
+
+	// SchemaEngine backs "templates scaffold" and the validation of notes
+	// created by "new". When nil, the command is left out and notes are not
+	// validated.
+	SchemaEngine *schema.SchemaEngine
+
+	// PromptPort must be the port the template prompts ask through; --answer
+	// and --answers script its answers when it is a ScriptedPromptPort. It
+	// may be nil when templates cannot ask questions.
+	PromptPort spi.PromptPort
 }
 
 // NewCobraCLIAdapter creates a new CobraCLIAdapter instance with
 // the root command and subcommands configured.
-func NewCobraCLIAdapter(
-	templateEngine *template.TemplateEngine,
-	templateRepo spi.TemplateRepositoryPort,
-	fileSystemPort spi.FileSystemPort,
-) *CobraCLIAdapter {
+func NewCobraCLIAdapter(opts AdapterOptions) *CobraCLIAdapter {
 	adapter := &CobraCLIAdapter{
 		rootCmd:        &cobra.Command{},
-		templateEngine: templateEngine,
-		templateRepo:   templateRepo,
-		fileSystemPort: fileSystemPort,
+		templateEngine: opts.TemplateEngine,
+		templateRepo:   opts.TemplateRepo,
+		fileSystemPort: opts.FileSystemPort,
+		configPort:     opts.ConfigPort,
+		noteWriter:     opts.NoteWriter,
+		clock:          opts.Clock,
+		linter:         opts.Linter,
+		schemaEngine:   opts.SchemaEngine,
+		promptPort:     opts.PromptPort,
 	}
 	adapter.setupCommands()
 	return adapter
@@ -46,28 +87,126 @@ func (a *CobraCLIAdapter) Execute(args []string) int {
 
 	if err := a.rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		writeTemplateExcerpt(os.Stderr, err)
 		return 1
 	}
 
 	return 0
 }
 
+// writeTemplateExcerpt writes the caret-annotated source excerpt of a
+// TemplateError in err's chain, if one with a known position exists.
+func writeTemplateExcerpt(out io.Writer, err error) {
+	var templateErr sharederrors.TemplateError
+	if !errors.As(err, &templateErr) {
+		return
+	}
+	if excerpt := templateErr.Excerpt(); excerpt != "" {
+		fmt.Fprintf(out, "\n%s\n", excerpt)
+	}
+}
+
 // setupCommands initializes the root command and registers all subcommands.
 func (a *CobraCLIAdapter) setupCommands() {
 	a.setupRootCommand()
 	a.registerCommands()
 }
 
-// setupRootCommand configures the root command with basic metadata.
+// setupRootCommand configures the root command with basic metadata and the
+// flags shared by every subcommand.
 func (a *CobraCLIAdapter) setupRootCommand() {
 	a.rootCmd = &cobra.Command{
 		Use:   "lithos",
 		Short: "Lithos - Obsidian vault management tool",
 		Long: `Lithos is a command-line tool for managing Obsidian vaults with
 schema-driven lookups, template rendering, and interactive input capabilities.`,
+		PersistentPreRunE:  a.applyGlobalFlags,
+		PersistentPostRunE: a.reportDryRun,
 	}
+
+	a.rootCmd.PersistentFlags().String(
+		"now",
+		"",
+		"pin the render time, e.g. 2025-03-14 or 2025-03-14T09:30:00Z "+
+			"(overrides LITHOS_NOW)",
+	)
+	a.rootCmd.PersistentFlags().Bool(
+		"dry-run",
+		false,
+		"show what would be written, with a diff for existing files, "+
+			"without writing anything",
+	)
+	a.rootCmd.PersistentFlags().StringArray(
+		"answer",
+		nil,
+		`answer a template prompt or suggester, as label=answer `+
+			`(e.g. --answer "Title=Weekly Sync")`,
+	)
+	a.rootCmd.PersistentFlags().String(
+		"answers",
+		"",
+		"JSON or YAML file of answers to template prompts, keyed by label",
+	)
 }
 
+// applyGlobalFlags applies the root flags shared by every subcommand.
+func (a *CobraCLIAdapter) applyGlobalFlags(
+	cmd *cobra.Command,
+	args []string,
+) error {
+	if err := a.applyNowFlag(cmd, args); err != nil {
+		return err
+	}
+	if err := a.applyAnswerFlags(cmd); err != nil {
+		return err
+	}
+	return a.applyDryRunFlag(cmd)
+}
+
+// applyNowFlag pins the render clock to the --now flag value when given.
+// Times without an offset are interpreted in the configured time zone.
+func (a *CobraCLIAdapter) applyNowFlag(cmd *cobra.Command, _ []string) error {
+	value, err := cmd.Flags().GetString("now")
+	if err != nil || value == "" {
+		return err
+	}
+
+	loc, err := a.configPort.Config().Location()
+	if err != nil {
+		return err
+	}
+
+	pinned, err := clock.Parse(value, loc)
+	if err != nil {
+		return fmt.Errorf("--now: %w", err)
+	}
+	a.clock.Pin(pinned)
+	return nil
+}
+
+// applyDryRunFlag switches the filesystem into dry-run mode when --dry-run is
+// given, so writing commands record their writes instead of performing them.
+func (a *CobraCLIAdapter) applyDryRunFlag(cmd *cobra.Command) error {
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil || !dryRun {
+		return err
+	}
+	return enableDryRun(a.fileSystemPort)
+}
+
+// reportDryRun prints the writes recorded during a dry run.
+func (a *CobraCLIAdapter) reportDryRun(cmd *cobra.Command, _ []string) error {
+	if !dryRunEnabled(a.fileSystemPort) {
+		return nil
+	}
+	recorder, _ := a.fileSystemPort.(spi.DryRunFileSystemPort)
+	return writeDryRunReport(cmd.OutOrStdout(), recorder.PendingWrites())
+}
+
+// lithosVersion is the release of lithos, as printed by "lithos version" and
+// stamped into the notes it generates.
+const lithosVersion = "0.1.0"
+
 // setupVersionCommand creates and returns the version command.
 func (a *CobraCLIAdapter) setupVersionCommand() *cobra.Command {
 	return &cobra.Command{
@@ -75,18 +214,61 @@ func (a *CobraCLIAdapter) setupVersionCommand() *cobra.Command {
 		Short: "Print the version number of Lithos",
 		Long:  `Print the version number of Lithos and exit.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Println("Lithos version 0.1.0")
+			fmt.Println("Lithos version " + lithosVersion)
 		},
 	}
 }
 
 // setupNewCommand creates and returns the new command.
 func (a *CobraCLIAdapter) setupNewCommand() *cobra.Command {
-	return NewCommand(a.templateEngine, a.templateRepo, a.fileSystemPort)
+	return NewCommand(
+		a.templateEngine,
+		a.templateRepo,
+		a.fileSystemPort,
+		a.configPort,
+		a.noteWriter,
+		a.schemaEngine,
+		a.promptPort,
+	)
+}
+
+// setupRenderCommand creates and returns the render command.
+func (a *CobraCLIAdapter) setupRenderCommand() *cobra.Command {
+	return NewRenderCommand(
+		a.templateEngine,
+		a.templateRepo,
+		a.fileSystemPort,
+		a.configPort,
+	)
+}
+
+// setupInsertCommand creates and returns the insert command.
+func (a *CobraCLIAdapter) setupInsertCommand() *cobra.Command {
+	return NewInsertCommand(
+		a.templateEngine,
+		a.templateRepo,
+		a.fileSystemPort,
+		a.configPort,
+	)
+}
+
+// setupTemplatesCommand creates and returns the templates command group.
+func (a *CobraCLIAdapter) setupTemplatesCommand() *cobra.Command {
+	return NewTemplatesCommand(
+		a.templateEngine,
+		a.templateRepo,
+		a.fileSystemPort,
+		a.configPort,
+		a.linter,
+		a.schemaEngine,
+	)
 }
 
 // registerCommands adds all subcommands to the root command.
 func (a *CobraCLIAdapter) registerCommands() {
 	a.rootCmd.AddCommand(a.setupVersionCommand())
 	a.rootCmd.AddCommand(a.setupNewCommand())
+	a.rootCmd.AddCommand(a.setupRenderCommand())
+	a.rootCmd.AddCommand(a.setupInsertCommand())
+	a.rootCmd.AddCommand(a.setupTemplatesCommand())
 }
@@ -6,10 +6,15 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/JackMatanky/lithos/internal/adapters/spi/filesystem"
 	templaterepo "github.com/JackMatanky/lithos/internal/adapters/spi/template"
+	"github.com/JackMatanky/lithos/internal/app/note"
 	templatedomain "github.com/JackMatanky/lithos/internal/app/template"
 	"github.com/JackMatanky/lithos/internal/ports/spi"
+	"github.com/JackMatanky/lithos/internal/shared/clock"
+	sharederrors "github.com/JackMatanky/lithos/internal/shared/errors"
 	testutils "github.com/JackMatanky/lithos/tests/utils"
 )
 
@@ -35,6 +40,14 @@ func newMockFileSystemPort() *mockFileSystemPort {
 	return testutils.NewMockFileSystemPort()
 }
 
+// testVaultPath is the vault root used by the mock config port.
+const testVaultPath = "/vault"
+
+// newMockConfigPort creates a config port rooted at testVaultPath.
+func newMockConfigPort() *testutils.MockConfigPort {
+	return testutils.NewMockConfigPort(testVaultPath)
+}
+
 // Methods are implemented by the shared mock
 
 func TestCobraCLIAdapter_Execute_VersionCommand(t *testing.T) {
@@ -48,8 +61,16 @@ func TestCobraCLIAdapter_Execute_VersionCommand(t *testing.T) {
 	templateRepo := templaterepo.NewFSAdapter(
 		mockFS,
 		createTemplateParser(),
+		newMockConfigPort(),
 	)
-	adapter := NewCobraCLIAdapter(templateEngine, templateRepo, mockFS)
+	adapter := NewCobraCLIAdapter(AdapterOptions{
+		TemplateEngine: templateEngine,
+		TemplateRepo:   templateRepo,
+		FileSystemPort: mockFS,
+		ConfigPort:     newMockConfigPort(),
+		NoteWriter:     note.NewWriter(mockFS),
+		Clock:          clock.NewPinnable(nil),
+	})
 
 	// Capture stdout
 	oldStdout := os.Stdout
@@ -85,8 +106,16 @@ func TestCobraCLIAdapter_Execute_HelpCommand(t *testing.T) {
 	templateRepo := templaterepo.NewFSAdapter(
 		mockFS,
 		createTemplateParser(),
+		newMockConfigPort(),
 	)
-	adapter := NewCobraCLIAdapter(templateEngine, templateRepo, mockFS)
+	adapter := NewCobraCLIAdapter(AdapterOptions{
+		TemplateEngine: templateEngine,
+		TemplateRepo:   templateRepo,
+		FileSystemPort: mockFS,
+		ConfigPort:     newMockConfigPort(),
+		NoteWriter:     note.NewWriter(mockFS),
+		Clock:          clock.NewPinnable(nil),
+	})
 
 	// Capture stdout
 	oldStdout := os.Stdout
@@ -122,8 +151,16 @@ func TestCobraCLIAdapter_Execute_InvalidCommand(t *testing.T) {
 	templateRepo := templaterepo.NewFSAdapter(
 		mockFS,
 		createTemplateParser(),
+		newMockConfigPort(),
 	)
-	adapter := NewCobraCLIAdapter(templateEngine, templateRepo, mockFS)
+	adapter := NewCobraCLIAdapter(AdapterOptions{
+		TemplateEngine: templateEngine,
+		TemplateRepo:   templateRepo,
+		FileSystemPort: mockFS,
+		ConfigPort:     newMockConfigPort(),
+		NoteWriter:     note.NewWriter(mockFS),
+		Clock:          clock.NewPinnable(nil),
+	})
 
 	// Execute invalid command
 	exitCode := adapter.Execute([]string{"invalid-command"})
@@ -141,8 +178,16 @@ func TestCobraCLIAdapter_Execute_NoArgs(t *testing.T) {
 	templateRepo := templaterepo.NewFSAdapter(
 		mockFS,
 		createTemplateParser(),
+		newMockConfigPort(),
 	)
-	adapter := NewCobraCLIAdapter(templateEngine, templateRepo, mockFS)
+	adapter := NewCobraCLIAdapter(AdapterOptions{
+		TemplateEngine: templateEngine,
+		TemplateRepo:   templateRepo,
+		FileSystemPort: mockFS,
+		ConfigPort:     newMockConfigPort(),
+		NoteWriter:     note.NewWriter(mockFS),
+		Clock:          clock.NewPinnable(nil),
+	})
 
 	// Capture stdout
 	oldStdout := os.Stdout
@@ -185,8 +230,16 @@ func TestCobraCLIAdapter_Execute_NewCommand_Success(t *testing.T) {
 	templateRepo := templaterepo.NewFSAdapter(
 		mockFS,
 		createTemplateParser(),
+		newMockConfigPort(),
 	)
-	adapter := NewCobraCLIAdapter(templateEngine, templateRepo, mockFS)
+	adapter := NewCobraCLIAdapter(AdapterOptions{
+		TemplateEngine: templateEngine,
+		TemplateRepo:   templateRepo,
+		FileSystemPort: mockFS,
+		ConfigPort:     newMockConfigPort(),
+		NoteWriter:     note.NewWriter(mockFS),
+		Clock:          clock.NewPinnable(nil),
+	})
 
 	// Capture stdout
 	oldStdout := os.Stdout
@@ -238,8 +291,16 @@ func TestCobraCLIAdapter_Execute_NewCommand_FileNotFound(t *testing.T) {
 	templateRepo := templaterepo.NewFSAdapter(
 		mockFS,
 		createTemplateParser(),
+		newMockConfigPort(),
 	)
-	adapter := NewCobraCLIAdapter(templateEngine, templateRepo, mockFS)
+	adapter := NewCobraCLIAdapter(AdapterOptions{
+		TemplateEngine: templateEngine,
+		TemplateRepo:   templateRepo,
+		FileSystemPort: mockFS,
+		ConfigPort:     newMockConfigPort(),
+		NoteWriter:     note.NewWriter(mockFS),
+		Clock:          clock.NewPinnable(nil),
+	})
 
 	// Execute new command with non-existent file
 	exitCode := adapter.Execute([]string{"new", "nonexistent.txt"})
@@ -256,8 +317,16 @@ func TestCobraCLIAdapter_Execute_NewCommand_NoArgs(t *testing.T) {
 	templateRepo := templaterepo.NewFSAdapter(
 		mockFS,
 		createTemplateParser(),
+		newMockConfigPort(),
 	)
-	adapter := NewCobraCLIAdapter(templateEngine, templateRepo, mockFS)
+	adapter := NewCobraCLIAdapter(AdapterOptions{
+		TemplateEngine: templateEngine,
+		TemplateRepo:   templateRepo,
+		FileSystemPort: mockFS,
+		ConfigPort:     newMockConfigPort(),
+		NoteWriter:     note.NewWriter(mockFS),
+		Clock:          clock.NewPinnable(nil),
+	})
 
 	// Execute new command without args
 	exitCode := adapter.Execute([]string{"new"})
@@ -287,8 +356,16 @@ func TestCobraCLIAdapter_Execute_NewCommand_WriteFailure(t *testing.T) {
 	templateRepo := templaterepo.NewFSAdapter(
 		mockFS,
 		createTemplateParser(),
+		newMockConfigPort(),
 	)
-	adapter := NewCobraCLIAdapter(templateEngine, templateRepo, mockFS)
+	adapter := NewCobraCLIAdapter(AdapterOptions{
+		TemplateEngine: templateEngine,
+		TemplateRepo:   templateRepo,
+		FileSystemPort: mockFS,
+		ConfigPort:     newMockConfigPort(),
+		NoteWriter:     note.NewWriter(mockFS),
+		Clock:          clock.NewPinnable(nil),
+	})
 
 	// Execute new command
 	exitCode := adapter.Execute([]string{"new", testTemplateFile})
@@ -313,8 +390,16 @@ func TestCobraCLIAdapter_Execute_NewCommand_WithFunctions(t *testing.T) {
 	templateRepo := templaterepo.NewFSAdapter(
 		mockFS,
 		createTemplateParser(),
+		newMockConfigPort(),
 	)
-	adapter := NewCobraCLIAdapter(templateEngine, templateRepo, mockFS)
+	adapter := NewCobraCLIAdapter(AdapterOptions{
+		TemplateEngine: templateEngine,
+		TemplateRepo:   templateRepo,
+		FileSystemPort: mockFS,
+		ConfigPort:     newMockConfigPort(),
+		NoteWriter:     note.NewWriter(mockFS),
+		Clock:          clock.NewPinnable(nil),
+	})
 
 	// Capture stdout
 	oldStdout := os.Stdout
@@ -399,8 +484,16 @@ func TestCobraCLIAdapter_Execute_NewCommand_FilenameGeneration(t *testing.T) {
 			templateRepo := templaterepo.NewFSAdapter(
 				mockFS,
 				createTemplateParser(),
+				newMockConfigPort(),
 			)
-			adapter := NewCobraCLIAdapter(templateEngine, templateRepo, mockFS)
+			adapter := NewCobraCLIAdapter(AdapterOptions{
+				TemplateEngine: templateEngine,
+				TemplateRepo:   templateRepo,
+				FileSystemPort: mockFS,
+				ConfigPort:     newMockConfigPort(),
+				NoteWriter:     note.NewWriter(mockFS),
+				Clock:          clock.NewPinnable(nil),
+			})
 
 			// Execute new command
 			exitCode := adapter.Execute([]string{"new", tt.templatePath})
@@ -417,3 +510,566 @@ func TestCobraCLIAdapter_Execute_NewCommand_FilenameGeneration(t *testing.T) {
 		})
 	}
 }
+
+func TestCobraCLIAdapter_Execute_NewCommand_WithData(t *testing.T) {
+	mockFS := newMockFileSystemPort()
+	mockFS.AddFile(testTemplateFile, []byte("# {{.title}} by {{.author}}"))
+	mockFS.AddFile("data.json", []byte(`{"title": "Plan", "author": "Ada"}`))
+
+	templateEngine := createTemplateEngine()
+	templateRepo := templaterepo.NewFSAdapter(
+		mockFS,
+		createTemplateParser(),
+		newMockConfigPort(),
+	)
+	adapter := NewCobraCLIAdapter(AdapterOptions{
+		TemplateEngine: templateEngine,
+		TemplateRepo:   templateRepo,
+		FileSystemPort: mockFS,
+		ConfigPort:     newMockConfigPort(),
+		NoteWriter:     note.NewWriter(mockFS),
+		Clock:          clock.NewPinnable(nil),
+	})
+
+	exitCode := adapter.Execute([]string{
+		"new", testTemplateFile,
+		"--data", "data.json",
+		"--set", "author=Grace",
+	})
+	if exitCode != 0 {
+		t.Fatalf("Execute() exit code = %v, want 0", exitCode)
+	}
+
+	writtenData, exists := mockFS.GetWrittenFiles()["template.md"]
+	if !exists {
+		t.Fatal("Expected template.md to be written")
+	}
+	if got, want := string(writtenData), "# Plan by Grace"; got != want {
+		t.Errorf("Written file content = %q, want %q", got, want)
+	}
+}
+
+func TestCobraCLIAdapter_Execute_NewCommand_InvalidSet(t *testing.T) {
+	mockFS := newMockFileSystemPort()
+	mockFS.AddFile(testTemplateFile, []byte("# {{.title}}"))
+
+	templateEngine := createTemplateEngine()
+	templateRepo := templaterepo.NewFSAdapter(
+		mockFS,
+		createTemplateParser(),
+		newMockConfigPort(),
+	)
+	adapter := NewCobraCLIAdapter(AdapterOptions{
+		TemplateEngine: templateEngine,
+		TemplateRepo:   templateRepo,
+		FileSystemPort: mockFS,
+		ConfigPort:     newMockConfigPort(),
+		NoteWriter:     note.NewWriter(mockFS),
+		Clock:          clock.NewPinnable(nil),
+	})
+
+	exitCode := adapter.Execute([]string{
+		"new", testTemplateFile, "--set", "title",
+	})
+	if exitCode == 0 {
+		t.Error("Execute() should return non-zero exit code for invalid --set")
+	}
+	if _, exists := mockFS.GetWrittenFiles()["template.md"]; exists {
+		t.Error("template.md should not be written when data is invalid")
+	}
+}
+
+func TestCobraCLIAdapter_Execute_NewCommand_OutputPath(t *testing.T) {
+	const headerTemplate = "{{- /* lithos\n" +
+		"output: projects/{{ .title | slug }}.md\n" +
+		"*/ -}}\n# {{.title}}"
+
+	tests := []struct {
+		name         string
+		template     string
+		args         []string
+		expectedFile string
+		wantErr      bool
+	}{
+		{
+			name:         "output flag",
+			template:     "# {{.title}}",
+			args:         []string{"--output", "notes/custom.md"},
+			expectedFile: "notes/custom.md",
+		},
+		{
+			name:         "output shorthand overrides header pattern",
+			template:     headerTemplate,
+			args:         []string{"-o", "elsewhere.md"},
+			expectedFile: "elsewhere.md",
+		},
+		{
+			name:         "header pattern resolved against vault",
+			template:     headerTemplate,
+			args:         []string{"--set", "title=Q3 Planning"},
+			expectedFile: testVaultPath + "/projects/q3-planning.md",
+		},
+		{
+			name:     "header pattern escaping the vault",
+			template: "{{/* lithos\noutput: ../{{.title}}.md\n*/}}",
+			args:     []string{"--set", "title=escape"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := newMockFileSystemPort()
+			mockFS.AddFile(testTemplateFile, []byte(tt.template))
+
+			templateEngine := createTemplateEngine()
+			templateRepo := templaterepo.NewFSAdapter(
+				mockFS,
+				createTemplateParser(),
+				newMockConfigPort(),
+			)
+			adapter := NewCobraCLIAdapter(AdapterOptions{
+				TemplateEngine: templateEngine,
+				TemplateRepo:   templateRepo,
+				FileSystemPort: mockFS,
+				ConfigPort:     newMockConfigPort(),
+				NoteWriter:     note.NewWriter(mockFS),
+				Clock:          clock.NewPinnable(nil),
+			})
+
+			args := append([]string{"new", testTemplateFile}, tt.args...)
+			exitCode := adapter.Execute(args)
+
+			if tt.wantErr {
+				if exitCode == 0 {
+					t.Error("Execute() should return non-zero exit code")
+				}
+				return
+			}
+
+			if exitCode != 0 {
+				t.Fatalf("Execute() exit code = %v, want 0", exitCode)
+			}
+			if _, exists := mockFS.GetWrittenFiles()[tt.expectedFile]; !exists {
+				t.Errorf(
+					"Expected %s to be written, got %v",
+					tt.expectedFile,
+					mockFS.GetWrittenFiles(),
+				)
+			}
+		})
+	}
+}
+
+func TestCobraCLIAdapter_Execute_NewCommand_Collision(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		expectedFile string
+		wantErr      bool
+	}{
+		{
+			name:    "fails by default",
+			wantErr: true,
+		},
+		{
+			name:         "force overwrites",
+			args:         []string{"--force"},
+			expectedFile: "template.md",
+		},
+		{
+			name:         "unique appends counter",
+			args:         []string{"--unique"},
+			expectedFile: "template-1.md",
+		},
+		{
+			name:    "force and unique are exclusive",
+			args:    []string{"--force", "--unique"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid unique strategy",
+			args:    []string{"--unique=random"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := newMockFileSystemPort()
+			mockFS.AddFile(testTemplateFile, []byte("new content"))
+			mockFS.AddFile("template.md", []byte("original"))
+
+			adapter := NewCobraCLIAdapter(AdapterOptions{
+				TemplateEngine: createTemplateEngine(),
+				TemplateRepo: templaterepo.NewFSAdapter(
+					mockFS,
+					createTemplateParser(),
+					newMockConfigPort(),
+				),
+				FileSystemPort: mockFS,
+				ConfigPort:     newMockConfigPort(),
+				NoteWriter:     note.NewWriter(mockFS),
+				Clock:          clock.NewPinnable(nil),
+			})
+
+			args := append([]string{"new", testTemplateFile}, tt.args...)
+			exitCode := adapter.Execute(args)
+
+			if tt.wantErr {
+				if exitCode == 0 {
+					t.Error("Execute() should return non-zero exit code")
+				}
+				got := string(mockFS.GetWrittenFiles()["template.md"])
+				if got != "original" {
+					t.Errorf("existing note content = %q, want unchanged", got)
+				}
+				return
+			}
+
+			if exitCode != 0 {
+				t.Fatalf("Execute() exit code = %v, want 0", exitCode)
+			}
+			got := string(mockFS.GetWrittenFiles()[tt.expectedFile])
+			if got != "new content" {
+				t.Errorf(
+					"%s content = %q, want %q",
+					tt.expectedFile,
+					got,
+					"new content",
+				)
+			}
+		})
+	}
+}
+
+func TestCobraCLIAdapter_Execute_NewCommand_ByTemplateID(t *testing.T) {
+	tests := []struct {
+		name         string
+		ref          string
+		expectedFile string
+	}{
+		{
+			name:         "top-level ID",
+			ref:          "meeting",
+			expectedFile: "meeting.md",
+		},
+		{
+			name:         "nested ID",
+			ref:          "projects/kickoff",
+			expectedFile: "kickoff.md",
+		},
+		{
+			name:         "unique base name",
+			ref:          "kickoff",
+			expectedFile: "kickoff.md",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := newMockFileSystemPort()
+			addVaultTemplate(mockFS, "meeting.md", "# Meeting")
+			addVaultTemplate(mockFS, "projects/kickoff.md", "# Kickoff")
+
+			adapter := NewCobraCLIAdapter(AdapterOptions{
+				TemplateEngine: createTemplateEngine(),
+				TemplateRepo: templaterepo.NewFSAdapter(
+					mockFS,
+					createTemplateParser(),
+					newMockConfigPort(),
+				),
+				FileSystemPort: mockFS,
+				ConfigPort:     newMockConfigPort(),
+				NoteWriter:     note.NewWriter(mockFS),
+				Clock:          clock.NewPinnable(nil),
+			})
+
+			if exitCode := adapter.Execute(
+				[]string{"new", tt.ref},
+			); exitCode != 0 {
+				t.Fatalf("Execute() exit code = %v, want 0", exitCode)
+			}
+			if _, exists := mockFS.GetWrittenFiles()[tt.expectedFile]; !exists {
+				t.Errorf(
+					"Expected %s to be written, got %v",
+					tt.expectedFile,
+					mockFS.GetWrittenFiles(),
+				)
+			}
+		})
+	}
+}
+
+func TestCobraCLIAdapter_Execute_NewCommand_Params(t *testing.T) {
+	const paramTemplate = "{{/* lithos\n" +
+		"params:\n" +
+		"  - name: title\n" +
+		"    required: true\n" +
+		"  - name: duration\n" +
+		"    type: integer\n" +
+		"    default: 30\n" +
+		"*/}}\n{{.title}} ({{.duration}} min)"
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "default applied",
+			args: []string{"--set", "title=Sync"},
+			want: "\nSync (30 min)",
+		},
+		{
+			name: "typed value from set",
+			args: []string{"--set", "title=Sync", "--set", "duration=45"},
+			want: "\nSync (45 min)",
+		},
+		{
+			name:    "missing required param",
+			args:    []string{"--set", "duration=45"},
+			wantErr: true,
+		},
+		{
+			name:    "ill-typed param",
+			args:    []string{"--set", "title=Sync", "--set", "duration=long"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := newMockFileSystemPort()
+			mockFS.AddFile(testTemplateFile, []byte(paramTemplate))
+
+			adapter := NewCobraCLIAdapter(AdapterOptions{
+				TemplateEngine: createTemplateEngine(),
+				TemplateRepo: templaterepo.NewFSAdapter(
+					mockFS,
+					createTemplateParser(),
+					newMockConfigPort(),
+				),
+				FileSystemPort: mockFS,
+				ConfigPort:     newMockConfigPort(),
+				NoteWriter:     note.NewWriter(mockFS),
+				Clock:          clock.NewPinnable(nil),
+			})
+
+			args := append([]string{"new", testTemplateFile}, tt.args...)
+			exitCode := adapter.Execute(args)
+
+			written, exists := mockFS.GetWrittenFiles()["template.md"]
+			if tt.wantErr {
+				if exitCode == 0 {
+					t.Error("Execute() should return non-zero exit code")
+				}
+				if exists {
+					t.Error("template.md should not be written")
+				}
+				return
+			}
+
+			if exitCode != 0 {
+				t.Fatalf("Execute() exit code = %v, want 0", exitCode)
+			}
+			if string(written) != tt.want {
+				t.Errorf("written = %q, want %q", written, tt.want)
+			}
+		})
+	}
+}
+
+func TestCobraCLIAdapter_Execute_NewCommand_NowFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "pinned date",
+			args: []string{"--now", "2024-01-15"},
+			want: "2024-01-15 00:00 Monday",
+		},
+		{
+			name: "pinned RFC 3339 time",
+			args: []string{"--now", "2024-01-15T09:30:00Z"},
+			want: "2024-01-15 09:30 Monday",
+		},
+		{
+			name:    "invalid time",
+			args:    []string{"--now", "yesterday"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := newMockFileSystemPort()
+			mockFS.AddFile(
+				testTemplateFile,
+				[]byte(`{{now "2006-01-02 15:04"}} {{today | weekday}}`),
+			)
+
+			renderClock := clock.NewPinnable(nil)
+			parser := templatedomain.NewStaticTemplateParserWithOptions(
+				templatedomain.FuncMapOptions{
+					Location: time.UTC,
+					Clock:    renderClock,
+				},
+			)
+			configPort := testutils.NewMockConfigPort(testVaultPath)
+			configPort.Config().Timezone = "UTC"
+
+			adapter := NewCobraCLIAdapter(AdapterOptions{
+				TemplateEngine: templatedomain.NewTemplateEngine(
+					parser,
+					templatedomain.NewGoTemplateExecutor(),
+				),
+				TemplateRepo: templaterepo.NewFSAdapter(
+					mockFS,
+					parser,
+					configPort,
+				),
+				FileSystemPort: mockFS,
+				ConfigPort:     configPort,
+				NoteWriter:     note.NewWriterWithClock(mockFS, renderClock),
+				Clock:          renderClock,
+			})
+
+			args := append([]string{"new", testTemplateFile}, tt.args...)
+			exitCode := adapter.Execute(args)
+
+			written, exists := mockFS.GetWrittenFiles()["template.md"]
+			if tt.wantErr {
+				if exitCode == 0 || exists {
+					t.Errorf("Execute() exit code = %v, written = %v; "+
+						"want failure without output", exitCode, exists)
+				}
+				return
+			}
+
+			if exitCode != 0 {
+				t.Fatalf("Execute() exit code = %v, want 0", exitCode)
+			}
+			if string(written) != tt.want {
+				t.Errorf("written = %q, want %q", written, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteTemplateExcerpt(t *testing.T) {
+	positioned := sharederrors.NewTemplateErrorAt(
+		"meeting",
+		2,
+		4,
+		"x {{nope}}",
+		`function "nope" not defined`,
+		nil,
+	)
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "wrapped positioned error",
+			err:  sharederrors.Wrap(positioned, "failed to load template"),
+			want: "\n   2 | x {{nope}}\n     |    ^\n",
+		},
+		{
+			name: "template error without position",
+			err:  sharederrors.NewTemplateError("meeting", 0, "boom", nil),
+			want: "",
+		},
+		{name: "other error", err: errors.New("boom"), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			writeTemplateExcerpt(&out, tt.err)
+			if out.String() != tt.want {
+				t.Errorf("writeTemplateExcerpt() = %q, want %q",
+					out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCobraCLIAdapter_Execute_NewCommand_DryRun(t *testing.T) {
+	mockFS := newMockFileSystemPort()
+	mockFS.AddFile(testTemplateFile, []byte("title: {{.title}}\n"))
+	mockFS.AddFile("template.md", []byte("title: old\n"))
+	dryRunFS := filesystem.NewDryRunAdapter(mockFS)
+
+	adapter := NewCobraCLIAdapter(AdapterOptions{
+		TemplateEngine: createTemplateEngine(),
+		TemplateRepo: templaterepo.NewFSAdapter(
+			dryRunFS,
+			createTemplateParser(),
+			newMockConfigPort(),
+		),
+		FileSystemPort: dryRunFS,
+		ConfigPort:     newMockConfigPort(),
+		NoteWriter:     note.NewWriter(dryRunFS),
+		Clock:          clock.NewPinnable(nil),
+	})
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	exitCode := adapter.Execute([]string{
+		"new", testTemplateFile, "--set", "title=new", "--force", "--dry-run",
+	})
+
+	_ = w.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	_, _ = buf.ReadFrom(r)
+
+	if exitCode != 0 {
+		t.Fatalf("Execute() exit code = %v, want 0", exitCode)
+	}
+	want := "Dry run: no files were written. Pending writes:\n\n" +
+		"Would update template.md:\n" +
+		"--- template.md\n+++ template.md (dry run)\n" +
+		"@@ -1 +1 @@\n-title: old\n+title: new\n"
+	if buf.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", buf.String(), want)
+	}
+	if written := mockFS.GetWrittenFiles()["template.md"]; string(written) !=
+		"title: old\n" {
+		t.Errorf("template.md = %q, want it untouched", written)
+	}
+}
+
+func TestCobraCLIAdapter_Execute_DryRunUnsupported(t *testing.T) {
+	mockFS := newMockFileSystemPort()
+	mockFS.AddFile(testTemplateFile, []byte("body"))
+	adapter := NewCobraCLIAdapter(AdapterOptions{
+		TemplateEngine: createTemplateEngine(),
+		TemplateRepo: templaterepo.NewFSAdapter(
+			mockFS,
+			createTemplateParser(),
+			newMockConfigPort(),
+		),
+		FileSystemPort: mockFS,
+		ConfigPort:     newMockConfigPort(),
+		NoteWriter:     note.NewWriter(mockFS),
+		Clock:          clock.NewPinnable(nil),
+	})
+
+	exitCode := adapter.Execute([]string{"new", testTemplateFile, "--dry-run"})
+	if exitCode == 0 {
+		t.Fatal("Execute() should fail when the filesystem cannot dry-run")
+	}
+	if _, exists := mockFS.GetWrittenFiles()["template.md"]; exists {
+		t.Error("nothing should be written when --dry-run is rejected")
+	}
+}
@@ -0,0 +1,152 @@
+// Package cli provides CLI command implementations for the Lithos application.
+// This file contains helpers that collect template data from command-line
+// flags, data files, and stdin into a domain.RenderContext.
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/JackMatanky/lithos/internal/domain"
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+// stdinDataPath is the --data value that requests JSON data from stdin.
+const stdinDataPath = "-"
+
+// dataOptions holds the template data sources supplied on the command line.
+type dataOptions struct {
+	dataPath string   // --data file path, or "-" for stdin
+	setPairs []string // --set key=value pairs
+}
+
+// addDataFlags registers the template data flags on cmd.
+func addDataFlags(cmd *cobra.Command, opts *dataOptions) {
+	cmd.Flags().StringVar(
+		&opts.dataPath,
+		"data",
+		"",
+		`JSON or YAML file with template data ("-" reads JSON from stdin)`,
+	)
+	cmd.Flags().StringArrayVar(
+		&opts.setPairs,
+		"set",
+		nil,
+		"set a template data value (key=value, repeatable)",
+	)
+}
+
+// loadRenderContext merges template data from every configured source into a
+// RenderContext. Sources are applied in order of increasing precedence: the
+// --data file (or stdin), then --set pairs.
+func loadRenderContext(
+	opts dataOptions,
+	stdin io.Reader,
+	fileSystemPort spi.FileSystemPort,
+) (domain.RenderContext, error) {
+	rc := domain.NewRenderContext()
+
+	if opts.dataPath != "" {
+		values, err := readDataSource(opts.dataPath, stdin, fileSystemPort)
+		if err != nil {
+			return rc, err
+		}
+		rc.Merge(values)
+	}
+
+	values, err := parseSetPairs(opts.setPairs)
+	if err != nil {
+		return rc, err
+	}
+	rc.Merge(values)
+
+	return rc, nil
+}
+
+// readDataSource reads and decodes the data file at path, or JSON from stdin
+// when path is "-".
+func readDataSource(
+	path string,
+	stdin io.Reader,
+	fileSystemPort spi.FileSystemPort,
+) (map[string]interface{}, error) {
+	if path == stdinDataPath {
+		content, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read data from stdin: %w", err)
+		}
+		return decodeJSONData("stdin", content)
+	}
+
+	content, err := fileSystemPort.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data file %q: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return decodeYAMLData(path, content)
+	case ".json":
+		return decodeJSONData(path, content)
+	default:
+		return nil, fmt.Errorf(
+			"unsupported data file %q: expected .json, .yaml, or .yml",
+			path,
+		)
+	}
+}
+
+// decodeJSONData decodes a JSON object into a data map.
+func decodeJSONData(
+	source string,
+	content []byte,
+) (map[string]interface{}, error) {
+	var values map[string]interface{}
+	if err := json.Unmarshal(content, &values); err != nil {
+		return nil, fmt.Errorf(
+			"invalid JSON data in %s: expected an object: %w",
+			source,
+			err,
+		)
+	}
+	return values, nil
+}
+
+// decodeYAMLData decodes a YAML mapping into a data map.
+func decodeYAMLData(
+	source string,
+	content []byte,
+) (map[string]interface{}, error) {
+	var values map[string]interface{}
+	if err := yaml.Unmarshal(content, &values); err != nil {
+		return nil, fmt.Errorf(
+			"invalid YAML data in %s: expected a mapping: %w",
+			source,
+			err,
+		)
+	}
+	return values, nil
+}
+
+// parseSetPairs converts key=value pairs into a data map. Values are kept as
+// strings; the first "=" separates key from value.
+func parseSetPairs(pairs []string) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(pairs))
+	for _, pair := range pairs {
+		key, value, found := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf(
+				"invalid --set value %q: expected key=value",
+				pair,
+			)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
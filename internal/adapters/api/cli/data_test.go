@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadRenderContext(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        dataOptions
+		files       map[string]string
+		stdin       string
+		want        map[string]interface{}
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "no sources yields empty data",
+			opts: dataOptions{},
+			want: map[string]interface{}{},
+		},
+		{
+			name: "set pairs",
+			opts: dataOptions{
+				setPairs: []string{"title=Weekly Sync", "query=a=b"},
+			},
+			want: map[string]interface{}{
+				"title": "Weekly Sync",
+				"query": "a=b",
+			},
+		},
+		{
+			name:  "json data file",
+			opts:  dataOptions{dataPath: "data.json"},
+			files: map[string]string{"data.json": `{"title": "From JSON"}`},
+			want:  map[string]interface{}{"title": "From JSON"},
+		},
+		{
+			name:  "yaml data file",
+			opts:  dataOptions{dataPath: "data.yaml"},
+			files: map[string]string{"data.yaml": "title: From YAML\n"},
+			want:  map[string]interface{}{"title": "From YAML"},
+		},
+		{
+			name:  "json from stdin",
+			opts:  dataOptions{dataPath: "-"},
+			stdin: `{"title": "From stdin"}`,
+			want:  map[string]interface{}{"title": "From stdin"},
+		},
+		{
+			name: "set pairs override data file",
+			opts: dataOptions{
+				dataPath: "data.yml",
+				setPairs: []string{"title=From Flag"},
+			},
+			files: map[string]string{
+				"data.yml": "title: From YAML\nauthor: Ada\n",
+			},
+			want: map[string]interface{}{
+				"title":  "From Flag",
+				"author": "Ada",
+			},
+		},
+		{
+			name:        "set pair without separator",
+			opts:        dataOptions{setPairs: []string{"title"}},
+			wantErr:     true,
+			errContains: "expected key=value",
+		},
+		{
+			name:        "set pair with empty key",
+			opts:        dataOptions{setPairs: []string{"=value"}},
+			wantErr:     true,
+			errContains: "expected key=value",
+		},
+		{
+			name:        "unsupported data file extension",
+			opts:        dataOptions{dataPath: "data.txt"},
+			files:       map[string]string{"data.txt": "title=x"},
+			wantErr:     true,
+			errContains: "unsupported data file",
+		},
+		{
+			name:        "missing data file",
+			opts:        dataOptions{dataPath: "missing.json"},
+			wantErr:     true,
+			errContains: "failed to read data file",
+		},
+		{
+			name:        "non-object json",
+			opts:        dataOptions{dataPath: "-"},
+			stdin:       `["not", "an", "object"]`,
+			wantErr:     true,
+			errContains: "invalid JSON data in stdin",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := newMockFileSystemPort()
+			for path, content := range tt.files {
+				mockFS.AddFile(path, []byte(content))
+			}
+
+			rc, err := loadRenderContext(
+				tt.opts,
+				strings.NewReader(tt.stdin),
+				mockFS,
+			)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("loadRenderContext() expected error, got nil")
+				}
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf(
+						"loadRenderContext() error = %v, want to contain %q",
+						err,
+						tt.errContains,
+					)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("loadRenderContext() unexpected error = %v", err)
+			}
+			if len(rc.Data) != len(tt.want) {
+				t.Fatalf("Data = %v, want %v", rc.Data, tt.want)
+			}
+			for key, want := range tt.want {
+				if rc.Data[key] != want {
+					t.Errorf(
+						"Data[%q] = %v, want %v",
+						key,
+						rc.Data[key],
+						want,
+					)
+				}
+			}
+		})
+	}
+}
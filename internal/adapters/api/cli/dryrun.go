@@ -0,0 +1,98 @@
+// Package cli provides CLI command implementations for the Lithos application.
+// This file contains the --dry-run support shared by every command that
+// writes files.
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+	"github.com/JackMatanky/lithos/internal/shared/diff"
+)
+
+// dryRunLabel marks the proposed side of a dry-run diff.
+const dryRunLabel = " (dry run)"
+
+// enableDryRun switches fileSystemPort into dry-run mode. It fails when the
+// port cannot record writes.
+func enableDryRun(fileSystemPort spi.FileSystemPort) error {
+	recorder, ok := fileSystemPort.(spi.DryRunFileSystemPort)
+	if !ok {
+		return errors.New("--dry-run is not supported by this filesystem")
+	}
+	recorder.EnableDryRun()
+	return nil
+}
+
+// dryRunEnabled reports whether fileSystemPort is recording writes instead of
+// performing them.
+func dryRunEnabled(fileSystemPort spi.FileSystemPort) bool {
+	recorder, ok := fileSystemPort.(spi.DryRunFileSystemPort)
+	return ok && recorder.DryRunEnabled()
+}
+
+// reportWritten tells the user that a file was written. In dry-run mode the
+// report of pending writes takes its place.
+func reportWritten(
+	out io.Writer,
+	fileSystemPort spi.FileSystemPort,
+	path string,
+) {
+	if dryRunEnabled(fileSystemPort) {
+		return
+	}
+	fmt.Fprintf(out, "Created %s\n", path)
+}
+
+// reportUpdated tells the user that an existing file was changed. In dry-run
+// mode the report of pending writes takes its place.
+func reportUpdated(
+	out io.Writer,
+	fileSystemPort spi.FileSystemPort,
+	path string,
+) {
+	if dryRunEnabled(fileSystemPort) {
+		return
+	}
+	fmt.Fprintf(out, "Updated %s\n", path)
+}
+
+// writeDryRunReport writes what the pending writes of a dry-run would do:
+// the content of files that would be created and a unified diff for files
+// that would be changed.
+func writeDryRunReport(out io.Writer, writes []spi.PendingWrite) error {
+	if len(writes) == 0 {
+		_, err := fmt.Fprintln(out, "Dry run: nothing would be written")
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("Dry run: no files were written. Pending writes:\n")
+	for _, write := range writes {
+		b.WriteString("\n")
+		switch {
+		case !write.Existed:
+			fmt.Fprintf(&b, "Would create %s:\n", write.Path)
+			b.Write(write.After)
+			if len(write.After) > 0 && write.After[len(write.After)-1] != '\n' {
+				b.WriteString("\n")
+			}
+		case string(write.Before) == string(write.After):
+			fmt.Fprintf(&b, "Would leave %s unchanged\n", write.Path)
+		default:
+			fmt.Fprintf(&b, "Would update %s:\n", write.Path)
+			b.WriteString(diff.Unified(
+				write.Path,
+				write.Path+dryRunLabel,
+				string(write.Before),
+				string(write.After),
+			))
+		}
+	}
+
+	_, err := io.WriteString(out, b.String())
+	return err
+}
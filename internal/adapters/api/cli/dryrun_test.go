@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+)
+
+func TestWriteDryRunReport(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []spi.PendingWrite
+		want   string
+	}{
+		{
+			name: "no writes",
+			want: "Dry run: nothing would be written\n",
+		},
+		{
+			name: "new, changed, and unchanged files",
+			writes: []spi.PendingWrite{
+				{Path: "new.md", After: []byte("# New")},
+				{
+					Path:    "old.md",
+					Existed: true,
+					Before:  []byte("title: a\nbody\n"),
+					After:   []byte("title: b\nbody\n"),
+				},
+				{
+					Path:    "same.md",
+					Existed: true,
+					Before:  []byte("x\n"),
+					After:   []byte("x\n"),
+				},
+			},
+			want: "Dry run: no files were written. Pending writes:\n" +
+				"\nWould create new.md:\n# New\n" +
+				"\nWould update old.md:\n" +
+				"--- old.md\n+++ old.md (dry run)\n" +
+				"@@ -1,2 +1,2 @@\n-title: a\n+title: b\n body\n" +
+				"\nWould leave same.md unchanged\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := writeDryRunReport(&out, tt.writes); err != nil {
+				t.Fatalf("writeDryRunReport() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("report =\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,409 @@
+// Package cli provides CLI command implementations for the Lithos application.
+// This file contains the form of "lithos new --interactive", which asks for
+// the properties of the template's schema before the note is rendered.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/JackMatanky/lithos/internal/app/note"
+	"github.com/JackMatanky/lithos/internal/app/schema"
+	"github.com/JackMatanky/lithos/internal/app/template"
+	"github.com/JackMatanky/lithos/internal/domain"
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+	sharederrors "github.com/JackMatanky/lithos/internal/shared/errors"
+)
+
+// fileClassKey is the frontmatter key naming the schema a note follows. The
+// template sets it, so the form never asks for it.
+const fileClassKey = "fileClass"
+
+// noOption is the suggester option that leaves an optional property unset.
+var noOption = spi.SuggesterOption{Label: "(none)", Value: ""}
+
+// schemaForm asks for the properties of a schema, each with a widget suited
+// to its type: a choice for enums and notes, a yes/no question for booleans,
+// and text for the rest. Every answer is checked against its property with
+// the schema validator before it is accepted.
+type schemaForm struct {
+	schemaEngine   *schema.SchemaEngine
+	validator      *schema.SchemaValidator
+	promptPort     spi.PromptPort
+	fileSystemPort spi.FileSystemPort
+	configPort     spi.ConfigPort
+}
+
+// formFor returns the form for notes created with opts, or nil unless
+// --interactive is given.
+func formFor(
+	opts newOptions,
+	schemaEngine *schema.SchemaEngine,
+	promptPort spi.PromptPort,
+	fileSystemPort spi.FileSystemPort,
+	configPort spi.ConfigPort,
+) *schemaForm {
+	if !opts.interactive {
+		return nil
+	}
+	return &schemaForm{
+		schemaEngine:   schemaEngine,
+		validator:      schema.NewSchemaValidator(),
+		promptPort:     promptPort,
+		fileSystemPort: fileSystemPort,
+		configPort:     configPort,
+	}
+}
+
+// Fill asks for every property of the schema of tmpl (see
+// template.TemplateSchema) that rc does not already have a value for,
+// required ones first and each group by name, and adds the answers to rc
+// under the property names. Optional properties left empty stay unset. A nil
+// form asks nothing.
+func (f *schemaForm) Fill(
+	ctx context.Context,
+	tmpl *domain.Template,
+	rc *domain.RenderContext,
+) error {
+	if f == nil {
+		return nil
+	}
+
+	name := template.TemplateSchema(tmpl)
+	if name == "" {
+		return fmt.Errorf(
+			"--interactive needs a schema to ask for, but template %q "+
+				`declares none (add "schema: <name>" to its header or `+
+				"set fileClass in its frontmatter)",
+			tmpl.Name,
+		)
+	}
+	if f.schemaEngine == nil || f.promptPort == nil {
+		return errors.New("--interactive is not supported here")
+	}
+
+	if result := f.schemaEngine.LoadRegistry(ctx); result.IsErr() {
+		return fmt.Errorf("failed to load schemas: %w", result.Error())
+	}
+	result := f.schemaEngine.GetSchema(ctx, name)
+	if result.IsErr() {
+		return fmt.Errorf(
+			"failed to load schema %q of template %q: %w",
+			name,
+			tmpl.Name,
+			result.Error(),
+		)
+	}
+	s := result.Value()
+
+	answers := make(map[string]interface{})
+	for _, property := range s.OrderedProperties() {
+		if property.Name == fileClassKey {
+			continue
+		}
+		if _, given := rc.Data[property.Name]; given {
+			continue
+		}
+		property.Spec = specValue(property.Spec)
+		value, answered, err := f.ask(ctx, property)
+		if err != nil {
+			return err
+		}
+		if answered {
+			answers[property.Name] = value
+		}
+	}
+	rc.Merge(answers)
+	return nil
+}
+
+// ask asks for the value of property with the widget for its type. It
+// reports false when an optional property is left unset.
+func (f *schemaForm) ask(
+	ctx context.Context,
+	property domain.Property,
+) (interface{}, bool, error) {
+	if !property.Array {
+		switch spec := property.Spec.(type) {
+		case domain.BoolPropertySpec:
+			yes, err := f.promptPort.Confirm(
+				ctx,
+				spi.ConfirmConfig{Label: property.Name},
+			)
+			return yes, err == nil, err
+		case domain.StringPropertySpec:
+			if len(spec.Enum) > 0 {
+				options := make([]spi.SuggesterOption, len(spec.Enum))
+				for i, value := range spec.Enum {
+					options[i] = spi.SuggesterOption{Label: value, Value: value}
+				}
+				return f.choose(ctx, property, options)
+			}
+		case domain.FilePropertySpec:
+			options, err := f.noteOptions(spec)
+			if err != nil {
+				return nil, false, err
+			}
+			if len(options) > 0 {
+				return f.choose(ctx, property, options)
+			}
+		}
+	}
+
+	cfg := spi.PromptConfig{
+		Label: property.Name,
+		Help:  propertyHelp(property),
+		Validate: func(answer string) error {
+			_, _, err := f.parse(ctx, property, answer)
+			return err
+		},
+	}
+	answer, err := f.promptPort.Prompt(ctx, cfg)
+	if err != nil {
+		return nil, false, err
+	}
+	return f.parse(ctx, property, answer)
+}
+
+// choose asks for the value of property as a choice between options, with
+// an extra option to leave an optional property unset.
+func (f *schemaForm) choose(
+	ctx context.Context,
+	property domain.Property,
+	options []spi.SuggesterOption,
+) (interface{}, bool, error) {
+	if !property.Required {
+		options = append([]spi.SuggesterOption{noOption}, options...)
+	}
+	answer, err := f.promptPort.Suggester(
+		ctx,
+		spi.SuggesterConfig{Label: property.Name, Options: options},
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	return f.parse(ctx, property, answer)
+}
+
+// parse converts answer into a value of property and checks it. Lists are
+// answered with comma-separated items. An empty answer leaves an optional
+// property unset and is an error for a required one.
+func (f *schemaForm) parse(
+	ctx context.Context,
+	property domain.Property,
+	answer string,
+) (interface{}, bool, error) {
+	if strings.TrimSpace(answer) == "" {
+		if property.Required {
+			return nil, false, errors.New("a value is required")
+		}
+		return nil, false, nil
+	}
+
+	var value interface{}
+	if property.Array {
+		var items []interface{}
+		for i, text := range strings.Split(answer, ",") {
+			item, err := answerValue(property.Spec, strings.TrimSpace(text))
+			if err != nil {
+				return nil, false, fmt.Errorf("item %d %w", i+1, err)
+			}
+			items = append(items, item)
+		}
+		value = items
+	} else {
+		item, err := answerValue(property.Spec, answer)
+		if err != nil {
+			return nil, false, err
+		}
+		value = item
+	}
+
+	result := f.validator.ValidatePropertyValue(ctx, property, value)
+	if result.IsErr() {
+		return nil, false, result.Error()
+	}
+	if invalid := result.Value(); !invalid.IsValid() {
+		return nil, false, answerError(invalid.Errors[0])
+	}
+	return value, true, nil
+}
+
+// specValue dereferences a pointer to a property spec, since widgets and the
+// schema validator work on spec values.
+func specValue(spec domain.PropertySpec) domain.PropertySpec {
+	switch typed := spec.(type) {
+	case *domain.StringPropertySpec:
+		return *typed
+	case *domain.NumberPropertySpec:
+		return *typed
+	case *domain.DatePropertySpec:
+		return *typed
+	case *domain.FilePropertySpec:
+		return *typed
+	case *domain.BoolPropertySpec:
+		return *typed
+	default:
+		return spec
+	}
+}
+
+// answerValue converts text into a value of the type spec validates.
+func answerValue(spec domain.PropertySpec, text string) (interface{}, error) {
+	switch spec.(type) {
+	case domain.NumberPropertySpec:
+		number, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, errors.New("must be a number")
+		}
+		return number, nil
+	case domain.BoolPropertySpec:
+		yes, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, errors.New("must be true or false")
+		}
+		return yes, nil
+	default:
+		return text, nil
+	}
+}
+
+// answerError states why an answer is invalid in the words of the property
+// validation that rejected it, e.g. "must be >= 1", naming the item at fault
+// in a list.
+func answerError(err error) error {
+	var invalid sharederrors.ValidationError
+	if !errors.As(err, &invalid) {
+		return err
+	}
+	var index int
+	if _, scanErr := fmt.Sscanf(
+		invalid.Property(),
+		"value[%d]",
+		&index,
+	); scanErr == nil {
+		return fmt.Errorf("item %d %s", index+1, invalid.Reason())
+	}
+	return errors.New(invalid.Reason())
+}
+
+// propertyHelp describes the answer property expects, such as the range of a
+// number or the format of a date.
+func propertyHelp(property domain.Property) string {
+	var parts []string
+	if property.Array {
+		parts = append(parts, "comma-separated")
+	}
+	switch spec := property.Spec.(type) {
+	case domain.StringPropertySpec:
+		if len(spec.Enum) > 0 {
+			parts = append(parts, "one of: "+strings.Join(spec.Enum, ", "))
+		} else if spec.Pattern != "" {
+			parts = append(parts, "matching "+spec.Pattern)
+		}
+	case domain.NumberPropertySpec:
+		parts = append(parts, numberHelp(spec))
+	case domain.DatePropertySpec:
+		format := spec.Format
+		if format == "" {
+			format = "2006-01-02T15:04:05Z07:00"
+		}
+		parts = append(parts, "a date formatted like "+format)
+	case domain.FilePropertySpec:
+		parts = append(parts, "a note, e.g. [[Name]]")
+	case domain.BoolPropertySpec:
+		parts = append(parts, "true or false")
+	}
+	if !property.Required {
+		parts = append(parts, "optional")
+	}
+	return strings.Join(parts, "; ")
+}
+
+// numberHelp describes the numbers spec accepts, e.g. "a number from 1 to 8
+// in steps of 0.5".
+func numberHelp(spec domain.NumberPropertySpec) string {
+	format := func(n float64) string {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+
+	help := "a number"
+	if spec.Step != nil && *spec.Step == 1 {
+		help = "a whole number"
+	}
+	if spec.Min != nil {
+		help += " from " + format(*spec.Min)
+	}
+	if spec.Max != nil {
+		help += " to " + format(*spec.Max)
+	}
+	if spec.Step != nil && *spec.Step != 1 {
+		help += " in steps of " + format(*spec.Step)
+	}
+	return help
+}
+
+// noteOptions lists the notes of the vault a file property may refer to, as
+// [[basename]] links labelled with their vault-relative path. The directory
+// and fileClass constraints of spec apply, each negated by a leading ^.
+func (f *schemaForm) noteOptions(
+	spec domain.FilePropertySpec,
+) ([]spi.SuggesterOption, error) {
+	vaultPath := f.configPort.Config().VaultPath
+	paths, err := vaultNotes(nil, f.fileSystemPort, f.configPort)
+	if err != nil {
+		return nil, err
+	}
+
+	var options []spi.SuggesterOption
+	for _, path := range paths {
+		rel, err := filepath.Rel(vaultPath, path)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		if !matchesConstraint(spec.Directory, func(dir string) bool {
+			dir = strings.Trim(filepath.ToSlash(dir), "/")
+			return strings.HasPrefix(rel, dir+"/")
+		}) {
+			continue
+		}
+		if spec.FileClass != "" {
+			content, err := f.fileSystemPort.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read note %q: %w", path, err)
+			}
+			fm, _, err := note.ParseFrontmatter(string(content))
+			if err != nil || !matchesConstraint(
+				spec.FileClass,
+				func(class string) bool { return fm.SchemaName() == class },
+			) {
+				continue
+			}
+		}
+
+		name := strings.TrimSuffix(rel, filepath.Ext(rel))
+		options = append(options, spi.SuggesterOption{
+			Label: name,
+			Value: "[[" + filepath.Base(name) + "]]",
+		})
+	}
+	return options, nil
+}
+
+// matchesConstraint reports whether a FilePropertySpec constraint holds,
+// given a test of its value: an empty constraint always holds, and a leading
+// ^ negates the test.
+func matchesConstraint(constraint string, test func(string) bool) bool {
+	if constraint == "" {
+		return true
+	}
+	if negated, found := strings.CutPrefix(constraint, "^"); found {
+		return !test(negated)
+	}
+	return test(constraint)
+}
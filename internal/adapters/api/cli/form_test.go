@@ -0,0 +1,407 @@
+package cli
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JackMatanky/lithos/internal/adapters/spi/interactive"
+	templaterepo "github.com/JackMatanky/lithos/internal/adapters/spi/template"
+	"github.com/JackMatanky/lithos/internal/app/note"
+	"github.com/JackMatanky/lithos/internal/app/schema"
+	"github.com/JackMatanky/lithos/internal/domain"
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+)
+
+// newTaskSchemaEngine returns a schema engine serving a "task" schema with a
+// property of every type.
+func newTaskSchemaEngine() *schema.SchemaEngine {
+	minEstimate, maxEstimate, step := 1.0, 8.0, 0.5
+	return schema.NewSchemaEngine(
+		nil,
+		staticSchemas{
+			"task": domain.NewSchema("task", []domain.Property{
+				domain.NewProperty(
+					"fileClass", true, false, domain.StringPropertySpec{},
+				),
+				domain.NewProperty(
+					"title", true, false, domain.StringPropertySpec{},
+				),
+				domain.NewProperty(
+					"status", true, false, domain.StringPropertySpec{
+						Enum: []string{"open", "done"},
+					},
+				),
+				domain.NewProperty(
+					"estimate", false, false, domain.NumberPropertySpec{
+						Min:  &minEstimate,
+						Max:  &maxEstimate,
+						Step: &step,
+					},
+				),
+				domain.NewProperty(
+					"due", false, false, &domain.DatePropertySpec{
+						Format: "2006-01-02",
+					},
+				),
+				domain.NewProperty(
+					"urgent", false, false, domain.BoolPropertySpec{},
+				),
+				domain.NewProperty(
+					"project", false, false, domain.FilePropertySpec{
+						FileClass: "project",
+					},
+				),
+				domain.NewProperty(
+					"tags", false, true, domain.StringPropertySpec{},
+				),
+			}),
+		},
+		schema.NewSchemaValidator(),
+	)
+}
+
+// addTaskVault adds a project note and another note to the mock vault.
+func addTaskVault(mockFS *mockFileSystemPort) {
+	for path, content := range map[string]string{
+		"/vault/projects/apollo.md": "---\nfileClass: project\n---\n",
+		"/vault/notes/misc.md":      "---\nfileClass: note\n---\n",
+	} {
+		mockFS.AddFile(path, []byte(content))
+		mockFS.AddWalkPath(path)
+	}
+}
+
+func TestSchemaForm_Fill(t *testing.T) {
+	answered := map[string]string{
+		"title":    "Launch",
+		"status":   "open",
+		"estimate": "2.5",
+		"due":      "2025-03-01",
+		"urgent":   "yes",
+		"project":  "projects/apollo",
+		"tags":     "a, b",
+	}
+	with := func(label, answer string) map[string]string {
+		answers := make(map[string]string, len(answered))
+		for key, value := range answered {
+			answers[key] = value
+		}
+		answers[label] = answer
+		return answers
+	}
+
+	tests := []struct {
+		name    string
+		schema  string
+		content string
+		data    map[string]interface{}
+		answers map[string]string
+		want    map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:    "every type",
+			answers: answered,
+			want: map[string]interface{}{
+				"title":    "Launch",
+				"status":   "open",
+				"estimate": 2.5,
+				"due":      "2025-03-01",
+				"urgent":   true,
+				"project":  "[[apollo]]",
+				"tags":     []interface{}{"a", "b"},
+			},
+		},
+		{
+			name: "given values are not asked",
+			data: map[string]interface{}{"title": "Given"},
+			answers: map[string]string{
+				"status": "done", "estimate": "", "due": "",
+				"urgent": "no", "project": "", "tags": "",
+			},
+			want: map[string]interface{}{
+				"title":  "Given",
+				"status": "done",
+				"urgent": false,
+			},
+		},
+		{
+			name:    "number above max",
+			answers: with("estimate", "9"),
+			wantErr: `answer "9" for "estimate" is invalid: must be <= 8`,
+		},
+		{
+			name:    "number off step",
+			answers: with("estimate", "2.25"),
+			wantErr: "must be multiple of 0.5",
+		},
+		{
+			name:    "not a number",
+			answers: with("estimate", "two"),
+			wantErr: "must be a number",
+		},
+		{
+			name:    "date in another format",
+			answers: with("due", "01/03/2025"),
+			wantErr: "must be valid date in format: 2006-01-02",
+		},
+		{
+			name:    "required value left empty",
+			answers: with("title", ""),
+			wantErr: `answer "" for "title" is invalid: a value is required`,
+		},
+		{
+			name:    "not an enum value",
+			answers: with("status", "later"),
+			wantErr: `answer "later" for "status" is not one of the ` +
+				"options: open, done",
+		},
+		{
+			name:    "note of another fileClass",
+			answers: with("project", "notes/misc"),
+			wantErr: "is not one of the options: (none), projects/apollo",
+		},
+		{
+			name:    "not yes or no",
+			answers: with("urgent", "maybe"),
+			wantErr: `answer "maybe" for "urgent" is not yes or no`,
+		},
+		{
+			name:    "unanswered",
+			answers: map[string]string{},
+			wantErr: `no answer was given for "status"`,
+		},
+		{
+			name:    "template without a schema",
+			schema:  "-",
+			wantErr: `template "task" declares none`,
+		},
+		{
+			name:    "fileClass of the template frontmatter",
+			schema:  "-",
+			content: "---\nfileClass: task\ntitle: {{ .title }}\n---\n",
+			answers: with("status", "done"),
+			want: map[string]interface{}{
+				"title":    "Launch",
+				"status":   "done",
+				"estimate": 2.5,
+				"due":      "2025-03-01",
+				"urgent":   true,
+				"project":  "[[apollo]]",
+				"tags":     []interface{}{"a", "b"},
+			},
+		},
+		{
+			name:    "fileClass set by an action",
+			schema:  "-",
+			content: "---\nfileClass: {{ .kind }}\n---\n",
+			wantErr: `template "task" declares none`,
+		},
+		{
+			name:    "unknown schema",
+			schema:  "person",
+			wantErr: `failed to load schema "person" of template "task"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := newMockFileSystemPort()
+			addTaskVault(mockFS)
+			promptPort := interactive.NewScriptedAdapter(nil)
+			promptPort.AddAnswers(tt.answers)
+			form := formFor(
+				newOptions{interactive: true},
+				newTaskSchemaEngine(),
+				promptPort,
+				mockFS,
+				newMockConfigPort(),
+			)
+
+			tmpl := &domain.Template{Name: "task", Content: tt.content}
+			switch tt.schema {
+			case "":
+				tmpl.Header.Schema = "task"
+			case "-":
+			default:
+				tmpl.Header.Schema = tt.schema
+			}
+			rc := domain.NewRenderContext()
+			rc.Merge(tt.data)
+
+			err := form.Fill(t.Context(), tmpl, &rc)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Fill() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Fill() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(rc.Data, tt.want) {
+				t.Errorf("data = %#v, want %#v", rc.Data, tt.want)
+			}
+		})
+	}
+}
+
+// labelRecorder records the label of every question it passes on.
+type labelRecorder struct {
+	*interactive.ScriptedAdapter
+
+	labels []string
+}
+
+func (r *labelRecorder) Prompt(
+	ctx context.Context,
+	cfg spi.PromptConfig,
+) (string, error) {
+	r.labels = append(r.labels, cfg.Label)
+	return r.ScriptedAdapter.Prompt(ctx, cfg)
+}
+
+func (r *labelRecorder) Suggester(
+	ctx context.Context,
+	cfg spi.SuggesterConfig,
+) (string, error) {
+	r.labels = append(r.labels, cfg.Label)
+	return r.ScriptedAdapter.Suggester(ctx, cfg)
+}
+
+func (r *labelRecorder) Confirm(
+	ctx context.Context,
+	cfg spi.ConfirmConfig,
+) (bool, error) {
+	r.labels = append(r.labels, cfg.Label)
+	return r.ScriptedAdapter.Confirm(ctx, cfg)
+}
+
+func TestSchemaForm_Fill_AsksInSchemaOrder(t *testing.T) {
+	want := []string{
+		"status", "title", "due", "estimate", "project", "tags", "urgent",
+	}
+	for range 5 {
+		mockFS := newMockFileSystemPort()
+		addTaskVault(mockFS)
+		recorder := &labelRecorder{
+			ScriptedAdapter: interactive.NewScriptedAdapter(nil),
+		}
+		recorder.AddAnswers(map[string]string{
+			"title": "Launch", "status": "open", "estimate": "",
+			"due": "", "urgent": "no", "project": "", "tags": "",
+		})
+		form := formFor(
+			newOptions{interactive: true},
+			newTaskSchemaEngine(),
+			recorder,
+			mockFS,
+			newMockConfigPort(),
+		)
+		tmpl := &domain.Template{
+			Name:   "task",
+			Header: domain.TemplateHeader{Schema: "task"},
+		}
+		rc := domain.NewRenderContext()
+
+		if err := form.Fill(t.Context(), tmpl, &rc); err != nil {
+			t.Fatalf("Fill() unexpected error = %v", err)
+		}
+		if !reflect.DeepEqual(recorder.labels, want) {
+			t.Fatalf("asked %v, want %v", recorder.labels, want)
+		}
+	}
+}
+
+func TestPropertyHelp(t *testing.T) {
+	minValue, maxValue, step := 0.0, 10.0, 1.0
+	tests := []struct {
+		property domain.Property
+		want     string
+	}{
+		{
+			property: domain.NewProperty(
+				"count", true, false, domain.NumberPropertySpec{
+					Min: &minValue, Max: &maxValue, Step: &step,
+				},
+			),
+			want: "a whole number from 0 to 10",
+		},
+		{
+			property: domain.NewProperty(
+				"logged", false, false, domain.DatePropertySpec{},
+			),
+			want: "a date formatted like 2006-01-02T15:04:05Z07:00; optional",
+		},
+		{
+			property: domain.NewProperty(
+				"labels", true, true, domain.StringPropertySpec{
+					Enum: []string{"a", "b"},
+				},
+			),
+			want: "comma-separated; one of: a, b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.property.Name, func(t *testing.T) {
+			if got := propertyHelp(tt.property); got != tt.want {
+				t.Errorf("propertyHelp() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteNewCommand_Interactive(t *testing.T) {
+	const task = "{{- /* lithos\nschema: task\n" +
+		"output: tasks/{{ .title | slug }}.md\n*/ -}}\n" +
+		"---\nfileClass: task\ntitle: {{ .title }}\n" +
+		"status: {{ .status }}\n---\n"
+
+	mockFS := newMockFileSystemPort()
+	addTaskVault(mockFS)
+	addVaultTemplate(mockFS, "task.md", task)
+	promptPort := interactive.NewScriptedAdapter(nil)
+	promptPort.AddAnswers(map[string]string{
+		"status": "done", "estimate": "", "due": "",
+		"urgent": "no", "project": "", "tags": "",
+	})
+	opts := newOptions{
+		data:        dataOptions{setPairs: []string{"title=Ship it"}},
+		interactive: true,
+	}
+
+	err := executeNewCommand(
+		"task",
+		opts,
+		strings.NewReader(""),
+		createTemplateEngine(),
+		templaterepo.NewFSAdapter(
+			mockFS,
+			createTemplateParser(),
+			newMockConfigPort(),
+		),
+		mockFS,
+		newMockConfigPort(),
+		note.NewWriter(mockFS),
+		validatorFor(opts, newTaskSchemaEngine(), nil),
+		formFor(
+			opts,
+			newTaskSchemaEngine(),
+			promptPort,
+			mockFS,
+			newMockConfigPort(),
+		),
+	)
+	if err != nil {
+		t.Fatalf("executeNewCommand() unexpected error = %v", err)
+	}
+
+	got := string(mockFS.GetWrittenFiles()["/vault/tasks/ship-it.md"])
+	want := "---\nfileClass: task\ntitle: Ship it\nstatus: done\n---\n"
+	if got != want {
+		t.Errorf("note = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,213 @@
+// Package cli provides CLI command implementations for the Lithos application.
+// This file contains the implementation of the 'insert' command, which splices
+// a rendered template into an existing note.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/JackMatanky/lithos/internal/app/note"
+	"github.com/JackMatanky/lithos/internal/app/template"
+	"github.com/JackMatanky/lithos/internal/domain"
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+	"github.com/spf13/cobra"
+)
+
+// insertOptions holds the flag values for the 'insert' command.
+type insertOptions struct {
+	data      dataOptions
+	into      string // --into path of the note to change
+	atHeading string // --at-heading line whose section receives the block
+	append    bool   // --append adds the block at the end of the note
+	prepend   bool   // --prepend adds the block after the frontmatter
+}
+
+// position maps the placement flags to where the block goes. Appending is
+// the default.
+func (o insertOptions) position() note.InsertPosition {
+	switch {
+	case o.atHeading != "":
+		return note.InsertPosition{
+			Mode:    note.InsertAtHeading,
+			Heading: o.atHeading,
+		}
+	case o.prepend:
+		return note.InsertPosition{Mode: note.InsertPrepend}
+	default:
+		return note.InsertPosition{Mode: note.InsertAppend}
+	}
+}
+
+// NewInsertCommand creates and returns the 'insert' command, which renders a
+// template and splices the result into an existing note.
+func NewInsertCommand(
+	templateEngine *template.TemplateEngine,
+	templateRepo spi.TemplateRepositoryPort,
+	fileSystemPort spi.FileSystemPort,
+	configPort spi.ConfigPort,
+) *cobra.Command {
+	var opts insertOptions
+
+	cmd := &cobra.Command{
+		Use:   "insert <template> --into <note>",
+		Short: "Insert a rendered template into an existing note",
+		Long: `Render a template and insert the result into an existing note.
+
+The template is resolved and its data supplied the same way as for
+"lithos new". The note given with --into must already exist; its path is
+relative to the current directory.
+
+By default the block is appended to the end of the note. --prepend puts it
+at the start of the body instead, and --at-heading puts it at the end of the
+section under a heading, before the blank lines that lead into the next
+section:
+
+  lithos insert log-entry --into daily/2025-01-02.md --at-heading "## Log"
+
+The heading must match a heading line of the note exactly, including its
+level. The section ends at the next heading of the same or a higher level.
+The note's frontmatter is never changed, and the note is replaced atomically.`,
+		Args: cobra.ExactArgs(1),
+		// Failed insertions are not usage errors
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeInsertCommand(
+				cmd.OutOrStdout(),
+				args[0],
+				opts,
+				cmd.InOrStdin(),
+				templateEngine,
+				templateRepo,
+				fileSystemPort,
+				configPort,
+			)
+		},
+	}
+
+	addInsertFlags(cmd, &opts)
+	return cmd
+}
+
+// addInsertFlags registers the flags of 'insert' on cmd, storing their
+// values in opts.
+func addInsertFlags(cmd *cobra.Command, opts *insertOptions) {
+	addDataFlags(cmd, &opts.data)
+	cmd.Flags().StringVar(
+		&opts.into,
+		"into",
+		"",
+		"path of the existing note to insert into",
+	)
+	_ = cmd.MarkFlagRequired("into")
+	cmd.Flags().StringVar(
+		&opts.atHeading,
+		"at-heading",
+		"",
+		`insert at the end of the section under this heading ("## Log")`,
+	)
+	cmd.Flags().BoolVar(
+		&opts.append,
+		"append",
+		false,
+		"insert at the end of the note (the default)",
+	)
+	cmd.Flags().BoolVar(
+		&opts.prepend,
+		"prepend",
+		false,
+		"insert at the start of the note, after its frontmatter",
+	)
+	cmd.MarkFlagsMutuallyExclusive("at-heading", "append", "prepend")
+}
+
+// executeInsertCommand renders the template identified by templateRef, with
+// the note at opts.into as its target, and inserts the result into that note,
+// replacing it atomically.
+func executeInsertCommand(
+	out io.Writer,
+	templateRef string,
+	opts insertOptions,
+	stdin io.Reader,
+	templateEngine *template.TemplateEngine,
+	templateRepo spi.TemplateRepositoryPort,
+	fileSystemPort spi.FileSystemPort,
+	configPort spi.ConfigPort,
+) error {
+	ctx := context.Background()
+
+	// Read the note first so a missing note fails before any rendering
+	content, err := fileSystemPort.ReadFile(opts.into)
+	if err != nil {
+		return fmt.Errorf("failed to read note %q: %w", opts.into, err)
+	}
+
+	rc, err := loadRenderContext(opts.data, stdin, fileSystemPort)
+	if err != nil {
+		return err
+	}
+	if rc.Vault, err = vaultInfo(configPort); err != nil {
+		return err
+	}
+	rc.Target = targetInfo(opts.into, configPort)
+
+	rendered, err := renderTemplateRef(
+		ctx,
+		templateRef,
+		rc,
+		templateEngine,
+		templateRepo,
+	)
+	if err != nil {
+		return err
+	}
+
+	updated, err := note.InsertBlock(string(content), rendered, opts.position())
+	if err != nil {
+		return fmt.Errorf(
+			"failed to insert into note %q: %w",
+			opts.into,
+			err,
+		)
+	}
+
+	if err := fileSystemPort.WriteFileAtomic(
+		opts.into,
+		[]byte(updated),
+	); err != nil {
+		return fmt.Errorf("failed to write note %q: %w", opts.into, err)
+	}
+
+	reportUpdated(out, fileSystemPort, opts.into)
+	return nil
+}
+
+// renderTemplateRef loads the template identified by templateRef and renders
+// it against rc.
+func renderTemplateRef(
+	ctx context.Context,
+	templateRef string,
+	rc domain.RenderContext,
+	templateEngine *template.TemplateEngine,
+	templateRepo spi.TemplateRepositoryPort,
+) (string, error) {
+	tmpl, err := loadTemplate(ctx, templateRef, templateRepo)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed to load template %q: %w",
+			templateRef,
+			err,
+		)
+	}
+
+	rendered, err := templateEngine.ExecuteParsedTemplate(ctx, tmpl, rc)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed to execute template %q: %w",
+			templateRef,
+			err,
+		)
+	}
+	return rendered, nil
+}
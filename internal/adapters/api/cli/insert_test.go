@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	templaterepo "github.com/JackMatanky/lithos/internal/adapters/spi/template"
+)
+
+func TestExecuteInsertCommand(t *testing.T) {
+	const notePath = "daily.md"
+	const dailyNote = "---\ntags: [daily]\n---\n## Log\n- 09:00 start\n\n" +
+		"## Tasks\n"
+
+	tests := []struct {
+		name    string
+		opts    insertOptions
+		note    string // content of daily.md when set
+		want    string
+		wantOut string
+		wantErr string
+	}{
+		{
+			name:    "append by default",
+			note:    dailyNote,
+			want:    dailyNote + "- 10:00 Ada\n",
+			wantOut: "Updated daily.md\n",
+		},
+		{
+			name: "at heading",
+			opts: insertOptions{atHeading: "## Log"},
+			note: dailyNote,
+			want: "---\ntags: [daily]\n---\n## Log\n- 09:00 start\n" +
+				"- 10:00 Ada\n\n## Tasks\n",
+			wantOut: "Updated daily.md\n",
+		},
+		{
+			name: "prepend keeps frontmatter",
+			opts: insertOptions{prepend: true},
+			note: dailyNote,
+			want: "---\ntags: [daily]\n---\n- 10:00 Ada\n## Log\n" +
+				"- 09:00 start\n\n## Tasks\n",
+			wantOut: "Updated daily.md\n",
+		},
+		{
+			name:    "missing heading leaves the note alone",
+			opts:    insertOptions{atHeading: "## Notes"},
+			note:    dailyNote,
+			want:    dailyNote,
+			wantErr: `heading "## Notes" not found`,
+		},
+		{
+			name:    "missing note",
+			wantErr: `failed to read note "daily.md"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := newMockFileSystemPort()
+			addVaultTemplate(mockFS, "entry.md", "- {{.time}} {{.name}}")
+			if tt.note != "" {
+				mockFS.AddFile(notePath, []byte(tt.note))
+			}
+
+			opts := tt.opts
+			opts.into = notePath
+			opts.data.setPairs = []string{"time=10:00", "name=Ada"}
+
+			var out bytes.Buffer
+			err := executeInsertCommand(
+				&out,
+				"entry",
+				opts,
+				strings.NewReader(""),
+				createTemplateEngine(),
+				templaterepo.NewFSAdapter(
+					mockFS,
+					createTemplateParser(),
+					newMockConfigPort(),
+				),
+				mockFS,
+				newMockConfigPort(),
+			)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("executeInsertCommand() error = %v, want %q",
+						err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("executeInsertCommand() unexpected error = %v", err)
+			}
+
+			if got := out.String(); got != tt.wantOut {
+				t.Errorf("output = %q, want %q", got, tt.wantOut)
+			}
+			if tt.note == "" {
+				return
+			}
+			got := string(mockFS.GetWrittenFiles()[notePath])
+			if got != tt.want {
+				t.Errorf("note = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
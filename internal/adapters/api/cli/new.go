@@ -5,87 +5,562 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/JackMatanky/lithos/internal/app/note"
+	"github.com/JackMatanky/lithos/internal/app/schema"
 	"github.com/JackMatanky/lithos/internal/app/template"
+	"github.com/JackMatanky/lithos/internal/domain"
 	"github.com/JackMatanky/lithos/internal/ports/spi"
+	sharederrors "github.com/JackMatanky/lithos/internal/shared/errors"
 	"github.com/spf13/cobra"
 )
 
+// newOptions holds the flag values for the 'new' command.
+type newOptions struct {
+	data        dataOptions
+	output      string // --output path, relative to the working directory
+	force       bool   // --force overwrites an existing note
+	unique      string // --unique suffix strategy: "counter" or "timestamp"
+	batch       string // --batch file with one row of template data per note
+	failFast    bool   // --fail-fast stops a batch at the first failed row
+	noValidate  bool   // --no-validate skips checking notes against schemas
+	stamp       bool   // --stamp records the template in each note
+	interactive bool   // --interactive asks for the schema's properties
+}
+
+// Values accepted by the --unique flag.
+const (
+	uniqueCounter   = "counter"
+	uniqueTimestamp = "timestamp"
+)
+
+// collisionPolicy maps the --force and --unique flags to the policy used when
+// the target note already exists.
+func (o newOptions) collisionPolicy() (note.CollisionPolicy, error) {
+	switch {
+	case o.force:
+		return note.CollisionOverwrite, nil
+	case o.unique == "":
+		return note.CollisionFail, nil
+	case o.unique == uniqueCounter:
+		return note.CollisionUniqueCounter, nil
+	case o.unique == uniqueTimestamp:
+		return note.CollisionUniqueTimestamp, nil
+	default:
+		return note.CollisionFail, fmt.Errorf(
+			"invalid --unique value %q: expected %q or %q",
+			o.unique,
+			uniqueCounter,
+			uniqueTimestamp,
+		)
+	}
+}
+
+// newLong is the long help text of the 'new' command.
+const newLong = `Create a new item by reading and processing a template file.
+
+The template is looked up by ID in the templates directory first (see
+"lithos templates list"), so "lithos new meeting" finds templates/meeting.md.
+A unique base name also matches a nested template. Anything else is treated
+as a path to a template file.
+
+Template data can be supplied with --data (a JSON or YAML file, or "-" for
+JSON on stdin) and --set key=value pairs. Values from --set take precedence
+over values from --data. When the template declares params in its header
+(see "lithos templates show"), missing required values and values of the
+wrong type are reported before anything is rendered, and defaults fill in
+omitted optional values.
+
+The note is written to --output when given. Otherwise, a template may declare
+an output path pattern in its header, which is rendered with the template data
+and resolved relative to the vault root:
+
+  {{- /* lithos
+  output: projects/{{ .title | slug }}.md
+  */ -}}
+
+Without either, the note is written as <template-name>.md in the current
+directory. Missing folders are created.
+
+Besides its data, which stays available as {{ .title }} or {{ .Data.title }},
+a template receives details of the render:
+
+  .Now       the time of the render (pinned by --now or LITHOS_NOW)
+  .Target    the note being written: .Path (relative to the vault), .Name,
+             .Basename (without extension), and .Folder
+  .Template  the template itself: .ID, .Path, and .Hash (SHA-256 of its source)
+  .Vault     .Root, the vault path, and .Config, the configuration values
+
+.Target is the final path, after any --unique suffix has been added.
+
+Templates may also ask for values as they render: {{ prompt "Title" }} asks
+for text, with an optional default as a second argument, and
+{{ suggester "Status" .options }} asks to choose from a list, or from the
+keys of a map, returning the chosen item or value. Each question is asked
+once per run, on the terminal. Give answers in advance, by label, with
+--answer "Title=Weekly Sync" or an --answers JSON or YAML file; without a
+terminal, a question with no answer is an error.
+
+If the target already exists the command fails. Use --force to overwrite it,
+or --unique to append a suffix instead (--unique=counter gives "note-1.md",
+--unique=timestamp gives "note-20060102-150405.md").
+
+With --batch, one note is created per row of a CSV file (with a header row),
+a JSON array of objects, or a JSONL file with one object per line. Each row
+is the template data of its note, on top of --data and below --set, and the
+output path pattern is rendered per row. Empty CSV cells count as missing.
+Rows whose target already exists are skipped unless --force or --unique is
+given. A summary of created, skipped, and failed rows is printed at the end;
+--fail-fast stops at the first failed row instead of carrying on.
+
+With --stamp, or stampProvenance set in the config, every note records the
+template it was generated from in its frontmatter: lithos_template (the
+template ID), lithos_template_hash, and lithos_version. "lithos templates
+drift" uses them to find notes whose template has changed since.
+
+With --interactive, the template must name its schema in its header
+("schema: task") or set a literal fileClass in its frontmatter
+("fileClass: task"). Each property of the schema without a value from --data
+or --set is asked for before rendering, required properties first, and the
+answer becomes template data under the property name, e.g. {{ .status }}.
+Enums are chosen from a list, booleans are answered yes or no, file
+properties are chosen from the notes matching their fileClass and directory,
+and numbers, dates, and other text are typed, with their range or format
+shown. Answers are checked against the schema as they are given, and invalid
+ones are asked again. Optional properties can be left empty.
+--answer "status=open" answers a property in advance.
+
+When a rendered note has frontmatter with a fileClass, its fields are checked
+against that schema before anything is written. A note that does not match
+is not written, and every invalid field is reported. Use --no-validate to
+write it anyway. When the vault has no schemas directory or the schema does
+not exist, the note is written with a warning instead.
+
+A template may declare a bundle of further notes to create with it, such as
+a project note with its kickoff meeting and task list:
+
+  bundle:
+    - template: meeting
+      output: meetings/{{ .title | slug }}-kickoff.md
+      data:
+        kind: kickoff
+    - template: tasks
+
+Each bundle note is rendered with the same data, merged with the entry's
+data, and its output pattern may be overridden. The note of the declaring
+template is available as .parent, so {{ wikilink .parent.path }} links back
+to it. Every note is rendered and validated first, and none is written if any
+of them fails.`
+
 // NewCommand creates and returns the 'new' command for template processing.
-// The command reads a template file, parses it, executes it, and writes to
-// file.
+// The command reads a template file, parses it, executes it with the supplied
+// template data, and writes to file. Rendered notes are validated against
+// their schema through schemaEngine, which may be nil to skip validation.
+// With --interactive, promptPort asks for the properties of the schema.
 func NewCommand(
 	templateEngine *template.TemplateEngine,
 	templateRepo spi.TemplateRepositoryPort,
 	fileSystemPort spi.FileSystemPort,
+	configPort spi.ConfigPort,
+	noteWriter *note.Writer,
+	schemaEngine *schema.SchemaEngine,
+	promptPort spi.PromptPort,
 ) *cobra.Command {
-	return &cobra.Command{
-		Use:   "new <template-path>",
+	var opts newOptions
+
+	cmd := &cobra.Command{
+		Use:   "new <template>",
 		Short: "Create a new item from a template",
-		Long:  `Create a new item by reading and processing a template file.`,
+		Long:  newLong,
 		Args:  cobra.ExactArgs(1),
+		// Failed notes and rows are not usage errors
+		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			validator := validatorFor(opts, schemaEngine, cmd.ErrOrStderr())
+			if opts.batch != "" {
+				return executeBatchCommand(
+					cmd.OutOrStdout(),
+					args[0],
+					opts,
+					cmd.InOrStdin(),
+					templateEngine,
+					templateRepo,
+					fileSystemPort,
+					configPort,
+					noteWriter,
+					validator,
+				)
+			}
+			if opts.failFast {
+				return errors.New("--fail-fast requires --batch")
+			}
 			return executeNewCommand(
 				args[0],
+				opts,
+				cmd.InOrStdin(),
 				templateEngine,
 				templateRepo,
 				fileSystemPort,
+				configPort,
+				noteWriter,
+				validator,
+				formFor(
+					opts,
+					schemaEngine,
+					promptPort,
+					fileSystemPort,
+					configPort,
+				),
 			)
 		},
 	}
+
+	addNewFlags(cmd, &opts)
+	return cmd
+}
+
+// addNewFlags registers the flags of 'new' on cmd, storing their values in
+// opts.
+func addNewFlags(cmd *cobra.Command, opts *newOptions) {
+	addDataFlags(cmd, &opts.data)
+	cmd.Flags().StringVarP(
+		&opts.output,
+		"output",
+		"o",
+		"",
+		"path of the note to create (overrides the template's output pattern)",
+	)
+	cmd.Flags().BoolVar(
+		&opts.force,
+		"force",
+		false,
+		"overwrite the target note if it already exists",
+	)
+	cmd.Flags().StringVar(
+		&opts.unique,
+		"unique",
+		"",
+		`append a suffix when the target exists ("counter" or "timestamp")`,
+	)
+	cmd.Flags().Lookup("unique").NoOptDefVal = uniqueCounter
+	cmd.MarkFlagsMutuallyExclusive("force", "unique")
+	cmd.Flags().StringVar(
+		&opts.batch,
+		"batch",
+		"",
+		"CSV, JSON, or JSONL file with one row of template data per note",
+	)
+	cmd.Flags().BoolVar(
+		&opts.failFast,
+		"fail-fast",
+		false,
+		"stop a batch at the first row that fails",
+	)
+	cmd.MarkFlagsMutuallyExclusive("batch", "output")
+	cmd.Flags().BoolVar(
+		&opts.noValidate,
+		"no-validate",
+		false,
+		"write notes even if their frontmatter does not match their schema",
+	)
+	cmd.Flags().BoolVar(
+		&opts.stamp,
+		"stamp",
+		false,
+		"record the template, its hash, and the lithos version in each note",
+	)
+	cmd.Flags().BoolVar(
+		&opts.interactive,
+		"interactive",
+		false,
+		"ask for each property of the template's schema before rendering",
+	)
+	cmd.MarkFlagsMutuallyExclusive("batch", "interactive")
 }
 
 // executeNewCommand handles the core logic for the new command.
 func executeNewCommand(
-	templatePath string,
+	templateRef string,
+	opts newOptions,
+	stdin io.Reader,
 	templateEngine *template.TemplateEngine,
 	templateRepo spi.TemplateRepositoryPort,
 	fileSystemPort spi.FileSystemPort,
+	configPort spi.ConfigPort,
+	noteWriter *note.Writer,
+	validator *noteValidator,
+	form *schemaForm,
 ) error {
 	ctx := context.Background()
 
+	policy, err := opts.collisionPolicy()
+	if err != nil {
+		return err
+	}
+
+	// Collect template data before touching the template
+	rc, err := loadRenderContext(opts.data, stdin, fileSystemPort)
+	if err != nil {
+		return err
+	}
+
 	// Get parsed template from repository
-	tmpl, err := templateRepo.GetByPath(ctx, templatePath)
+	tmpl, err := loadTemplate(ctx, templateRef, templateRepo)
 	if err != nil {
 		return fmt.Errorf(
 			"failed to load template %q: %w",
-			templatePath,
+			templateRef,
 			err,
 		)
 	}
 
-	// Execute parsed template
-	renderedContent, err := templateEngine.ExecuteParsedTemplate(ctx, tmpl)
+	if err := form.Fill(ctx, tmpl, &rc); err != nil {
+		return err
+	}
+
+	bundle, err := loadBundle(ctx, tmpl, templateRepo)
 	if err != nil {
-		return fmt.Errorf(
-			"failed to execute template %q: %w",
-			templatePath,
+		return err
+	}
+
+	return createNote(
+		ctx,
+		templateRef,
+		tmpl,
+		bundle,
+		rc,
+		opts.output,
+		policy,
+		templateEngine,
+		fileSystemPort,
+		configPort,
+		noteWriter,
+		validator,
+		stampFor(opts, configPort),
+	)
+}
+
+// stampFor reports whether notes created with opts get a provenance stamp,
+// either for this run or for every run through the config.
+func stampFor(opts newOptions, configPort spi.ConfigPort) bool {
+	return opts.stamp || configPort.Config().StampProvenance
+}
+
+// validatorFor returns the validator for notes created with opts, or nil when
+// --no-validate is given.
+func validatorFor(
+	opts newOptions,
+	schemaEngine *schema.SchemaEngine,
+	warnings io.Writer,
+) *noteValidator {
+	if opts.noValidate {
+		return nil
+	}
+	return newNoteValidator(schemaEngine, warnings)
+}
+
+// createNote renders tmpl with rc, validates the result with validator, and
+// writes it to the note's output path, applying policy when the target
+// already exists. The notes of the template's bundle are rendered and
+// validated as well, and nothing is written until all of them are good. With
+// stamp, every note records the template it was generated from.
+func createNote(
+	ctx context.Context,
+	templateRef string,
+	tmpl *domain.Template,
+	bundle []bundleChild,
+	rc domain.RenderContext,
+	output string,
+	policy note.CollisionPolicy,
+	templateEngine *template.TemplateEngine,
+	fileSystemPort spi.FileSystemPort,
+	configPort spi.ConfigPort,
+	noteWriter *note.Writer,
+	validator *noteValidator,
+	stamp bool,
+) error {
+	parent, err := planNote(
+		ctx,
+		templateRef,
+		tmpl,
+		rc,
+		output,
+		policy,
+		templateEngine,
+		configPort,
+		noteWriter,
+		validator,
+		stamp,
+	)
+	if err != nil {
+		return err
+	}
+
+	children, err := planBundle(
+		ctx,
+		bundle,
+		rc,
+		parent.path,
+		policy,
+		templateEngine,
+		configPort,
+		noteWriter,
+		validator,
+		stamp,
+	)
+	if err != nil {
+		return err
+	}
+
+	notes := append([]plannedNote{parent}, children...)
+	if err := checkDistinctTargets(notes); err != nil {
+		return err
+	}
+
+	return writePlannedNotes(ctx, notes, policy, noteWriter, fileSystemPort)
+}
+
+// writePlannedNotes writes every planned note to its target under policy,
+// in order, stopping at the first failure.
+func writePlannedNotes(
+	ctx context.Context,
+	notes []plannedNote,
+	policy note.CollisionPolicy,
+	noteWriter *note.Writer,
+	fileSystemPort spi.FileSystemPort,
+) error {
+	for _, planned := range notes {
+		err := writeOutputFile(
+			ctx,
+			planned.path,
+			planned.content,
+			policy,
+			noteWriter,
+			fileSystemPort,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadTemplate resolves ref as a template ID and falls back to treating it as
+// a file path when no template with that ID exists.
+func loadTemplate(
+	ctx context.Context,
+	ref string,
+	templateRepo spi.TemplateRepositoryPort,
+) (*domain.Template, error) {
+	tmpl, err := templateRepo.Get(ctx, ref)
+	var notFound sharederrors.TemplateNotFoundError
+	if !errors.As(err, &notFound) {
+		return tmpl, err
+	}
+
+	tmpl, err = templateRepo.GetByPath(ctx, ref)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf(
+			"%w: no template with that ID and no file at that path "+
+				`(see "lithos templates list")`,
+			notFound,
+		)
+	}
+	return tmpl, err
+}
+
+// resolveOutputPath determines where the rendered note is written. An explicit
+// --output path wins; otherwise the template header's output pattern is
+// rendered and resolved against the vault root; otherwise the note is written
+// as <template-name>.md in the working directory.
+func resolveOutputPath(
+	ctx context.Context,
+	output string,
+	tmpl *domain.Template,
+	rc domain.RenderContext,
+	templateEngine *template.TemplateEngine,
+	configPort spi.ConfigPort,
+) (string, error) {
+	if output != "" {
+		return output, nil
+	}
+
+	pattern, err := templateEngine.RenderOutputPath(ctx, tmpl, rc)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed to resolve output path for template %q: %w",
+			tmpl.Name,
 			err,
 		)
 	}
 
-	// Write output file
-	return writeOutputFile(tmpl.Name, renderedContent, fileSystemPort)
+	if pattern == "" {
+		return tmpl.Name + ".md", nil
+	}
+
+	return resolveVaultPath(pattern, configPort)
 }
 
-// writeOutputFile writes the rendered content to a markdown file.
+// resolveVaultPath resolves a rendered output pattern against the vault root
+// and rejects results that would land outside the vault.
+func resolveVaultPath(
+	pattern string,
+	configPort spi.ConfigPort,
+) (string, error) {
+	cfg := configPort.Config()
+	resolved := cfg.ResolvePath(pattern)
+
+	rel, err := filepath.Rel(cfg.VaultPath, resolved)
+	if err != nil || rel == ".." ||
+		strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf(
+			"output path %q resolves outside the vault %q",
+			pattern,
+			cfg.VaultPath,
+		)
+	}
+
+	return resolved, nil
+}
+
+// writeOutputFile writes the rendered content to the output path, applying the
+// collision policy. Missing parent folders are created by the filesystem port.
 func writeOutputFile(
-	templateName string,
+	ctx context.Context,
+	outputPath string,
 	content string,
+	policy note.CollisionPolicy,
+	noteWriter *note.Writer,
 	fileSystemPort spi.FileSystemPort,
 ) error {
-	outputFileName := templateName + ".md"
-
-	err := fileSystemPort.WriteFileAtomic(outputFileName, []byte(content))
+	written, err := noteWriter.Write(ctx, outputPath, []byte(content), policy)
 	if err != nil {
+		return writeError(outputPath, err)
+	}
+
+	reportWritten(os.Stdout, fileSystemPort, written)
+	return nil
+}
+
+// writeError explains a failure to write the note at outputPath, pointing
+// at --force and --unique when the target already exists.
+func writeError(outputPath string, err error) error {
+	if errors.Is(err, fs.ErrExist) {
 		return fmt.Errorf(
-			"failed to write output file %q: %w",
-			outputFileName,
+			"%w (use --force to overwrite or --unique to pick a new name)",
 			err,
 		)
 	}
-
-	fmt.Printf("Created %s\n", outputFileName)
-	return nil
+	return fmt.Errorf(
+		"failed to write output file %q: %w",
+		outputPath,
+		err,
+	)
 }
@@ -0,0 +1,129 @@
+// Package cli provides CLI command implementations for the Lithos application.
+// This file contains the implementation of the 'render' command, which prints
+// a rendered template instead of writing a note.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/JackMatanky/lithos/internal/app/template"
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+	"github.com/spf13/cobra"
+)
+
+// stdinTemplateRef is the template argument that reads template text from
+// stdin.
+const stdinTemplateRef = "-"
+
+// stdinTemplateName labels templates read from stdin in error messages.
+const stdinTemplateName = "stdin"
+
+// NewRenderCommand creates and returns the 'render' command, which renders a
+// template to stdout without touching the vault.
+func NewRenderCommand(
+	templateEngine *template.TemplateEngine,
+	templateRepo spi.TemplateRepositoryPort,
+	fileSystemPort spi.FileSystemPort,
+	configPort spi.ConfigPort,
+) *cobra.Command {
+	var opts dataOptions
+
+	cmd := &cobra.Command{
+		Use:   "render <template|->",
+		Short: "Render a template to stdout",
+		Long: `Render a template and print the result to stdout. Nothing is written
+to the vault, so the output can be previewed or piped into other tools.
+
+The template is resolved the same way as for "lithos new". Use "-" to read
+the template text from stdin instead; such templates are rendered as-is,
+without partials, layouts, or header params.
+
+Template data is supplied with --data and --set as for "lithos new". Stdin
+can only be used for one of the template and --data.`,
+		Args: cobra.ExactArgs(1),
+		// Render failures are not usage errors
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeRenderCommand(
+				cmd.OutOrStdout(),
+				args[0],
+				opts,
+				cmd.InOrStdin(),
+				templateEngine,
+				templateRepo,
+				fileSystemPort,
+				configPort,
+			)
+		},
+	}
+
+	addDataFlags(cmd, &opts)
+
+	return cmd
+}
+
+// executeRenderCommand renders the template identified by templateRef, or the
+// template text on stdin when templateRef is "-", and writes it to out.
+func executeRenderCommand(
+	out io.Writer,
+	templateRef string,
+	opts dataOptions,
+	stdin io.Reader,
+	templateEngine *template.TemplateEngine,
+	templateRepo spi.TemplateRepositoryPort,
+	fileSystemPort spi.FileSystemPort,
+	configPort spi.ConfigPort,
+) error {
+	ctx := context.Background()
+
+	if templateRef == stdinTemplateRef && opts.dataPath == stdinDataPath {
+		return errors.New(
+			"cannot read both the template and --data from stdin",
+		)
+	}
+
+	rc, err := loadRenderContext(opts, stdin, fileSystemPort)
+	if err != nil {
+		return err
+	}
+	if rc.Vault, err = vaultInfo(configPort); err != nil {
+		return err
+	}
+
+	var rendered string
+	if templateRef == stdinTemplateRef {
+		content, readErr := io.ReadAll(stdin)
+		if readErr != nil {
+			return fmt.Errorf("failed to read template from stdin: %w", readErr)
+		}
+		rendered, err = templateEngine.ProcessTemplate(
+			ctx,
+			string(content),
+			stdinTemplateName,
+			rc,
+		)
+	} else {
+		tmpl, loadErr := loadTemplate(ctx, templateRef, templateRepo)
+		if loadErr != nil {
+			return fmt.Errorf(
+				"failed to load template %q: %w",
+				templateRef,
+				loadErr,
+			)
+		}
+		rendered, err = templateEngine.ExecuteParsedTemplate(ctx, tmpl, rc)
+	}
+	if err != nil {
+		return fmt.Errorf(
+			"failed to render template %q: %w",
+			templateRef,
+			err,
+		)
+	}
+
+	_, err = io.WriteString(out, rendered)
+	return err
+}
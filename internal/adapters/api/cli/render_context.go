@@ -0,0 +1,64 @@
+// Package cli provides CLI command implementations for the Lithos application.
+// This file contains the parts of the render context only the CLI knows: the
+// note a render is written to and the vault it belongs to.
+package cli
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/JackMatanky/lithos/internal/domain"
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+	"go.yaml.in/yaml/v3"
+)
+
+// targetInfo describes the note at target, with its path relative to the
+// vault root when it lies inside the vault.
+func targetInfo(target string, configPort spi.ConfigPort) domain.TargetInfo {
+	rel := target
+	if abs, err := filepath.Abs(target); err == nil {
+		vaultRel, relErr := filepath.Rel(configPort.Config().VaultPath, abs)
+		if relErr == nil && vaultRel != ".." &&
+			!strings.HasPrefix(vaultRel, ".."+string(filepath.Separator)) {
+			rel = vaultRel
+		}
+	}
+	rel = filepath.ToSlash(rel)
+
+	name := path.Base(rel)
+	folder := path.Dir(rel)
+	if folder == "." {
+		folder = ""
+	}
+	return domain.TargetInfo{
+		Path:     rel,
+		Name:     name,
+		Basename: strings.TrimSuffix(name, path.Ext(name)),
+		Folder:   folder,
+	}
+}
+
+// vaultInfo describes the configured vault. Config values are keyed by their
+// names in the config file.
+func vaultInfo(configPort spi.ConfigPort) (domain.VaultInfo, error) {
+	cfg := configPort.Config()
+
+	encoded, err := yaml.Marshal(cfg)
+	if err != nil {
+		return domain.VaultInfo{}, fmt.Errorf(
+			"failed to describe the vault config: %w",
+			err,
+		)
+	}
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal(encoded, &values); err != nil {
+		return domain.VaultInfo{}, fmt.Errorf(
+			"failed to describe the vault config: %w",
+			err,
+		)
+	}
+
+	return domain.VaultInfo{Root: cfg.VaultPath, Config: values}, nil
+}
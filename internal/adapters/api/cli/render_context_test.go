@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	templaterepo "github.com/JackMatanky/lithos/internal/adapters/spi/template"
+	"github.com/JackMatanky/lithos/internal/app/note"
+	"github.com/JackMatanky/lithos/internal/domain"
+)
+
+func TestTargetInfo(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		want   domain.TargetInfo
+	}{
+		{
+			name:   "nested note in the vault",
+			target: "/vault/projects/launch.md",
+			want: domain.TargetInfo{
+				Path:     "projects/launch.md",
+				Name:     "launch.md",
+				Basename: "launch",
+				Folder:   "projects",
+			},
+		},
+		{
+			name:   "note at the vault root",
+			target: "/vault/inbox.md",
+			want: domain.TargetInfo{
+				Path:     "inbox.md",
+				Name:     "inbox.md",
+				Basename: "inbox",
+			},
+		},
+		{
+			name:   "note outside the vault keeps its path",
+			target: "/elsewhere/vault-notes/a.md",
+			want: domain.TargetInfo{
+				Path:     "/elsewhere/vault-notes/a.md",
+				Name:     "a.md",
+				Basename: "a",
+				Folder:   "/elsewhere/vault-notes",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := targetInfo(tt.target, newMockConfigPort())
+			if got != tt.want {
+				t.Errorf("targetInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVaultInfo(t *testing.T) {
+	got, err := vaultInfo(newMockConfigPort())
+	if err != nil {
+		t.Fatalf("vaultInfo() unexpected error = %v", err)
+	}
+
+	if got.Root != testVaultPath {
+		t.Errorf("Root = %q, want %q", got.Root, testVaultPath)
+	}
+	if got.Config["templatesDir"] != "/vault/templates" {
+		t.Errorf("Config[templatesDir] = %v, want %q",
+			got.Config["templatesDir"], "/vault/templates")
+	}
+}
+
+func TestExecuteNewCommand_RenderContext(t *testing.T) {
+	mockFS := newMockFileSystemPort()
+	addVaultTemplate(mockFS, "work/meeting.md", "{{- /* lithos\n"+
+		"output: notes/{{ .title | slug }}.md\n"+
+		"*/ -}}\n"+
+		"{{ .Target.Basename }} in {{ .Target.Folder }} "+
+		"from {{ .Template.ID }} ({{ .Data.title }})\n")
+	mockFS.AddFile("/vault/notes/sync.md", []byte("existing"))
+
+	err := executeNewCommand(
+		"work/meeting",
+		newOptions{
+			data:   dataOptions{setPairs: []string{"title=Sync"}},
+			unique: uniqueCounter,
+		},
+		strings.NewReader(""),
+		createTemplateEngine(),
+		templaterepo.NewFSAdapter(
+			mockFS,
+			createTemplateParser(),
+			newMockConfigPort(),
+		),
+		mockFS,
+		newMockConfigPort(),
+		note.NewWriter(mockFS),
+		nil,
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("executeNewCommand() unexpected error = %v", err)
+	}
+
+	got := string(mockFS.GetWrittenFiles()["/vault/notes/sync-1.md"])
+	want := "sync-1 in notes from work/meeting (Sync)\n"
+	if got != want {
+		t.Errorf("note = %q, want %q", got, want)
+	}
+}
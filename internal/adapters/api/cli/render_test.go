@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	templaterepo "github.com/JackMatanky/lithos/internal/adapters/spi/template"
+)
+
+func TestExecuteRenderCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		ref      string
+		opts     dataOptions
+		stdin    string
+		want     string
+		wantErr  string
+		template string // vault template "greeting.md" when set
+	}{
+		{
+			name:     "template by ID",
+			ref:      "greeting",
+			opts:     dataOptions{setPairs: []string{"name=Ada"}},
+			template: "Hello, {{.name}}!",
+			want:     "Hello, Ada!",
+		},
+		{
+			name: "header params are applied",
+			ref:  "greeting",
+			template: "{{- /* lithos\nparams:\n" +
+				"  - name: name\n    default: World\n*/ -}}\n" +
+				"Hello, {{.name}}!",
+			want: "Hello, World!",
+		},
+		{
+			name:  "template from stdin",
+			ref:   "-",
+			opts:  dataOptions{setPairs: []string{"name=Ada"}},
+			stdin: "# {{.name | toUpper}}\n",
+			want:  "# ADA\n",
+		},
+		{
+			name:     "data from stdin",
+			ref:      "greeting",
+			opts:     dataOptions{dataPath: "-"},
+			stdin:    `{"name": "Grace"}`,
+			template: "Hello, {{.name}}!",
+			want:     "Hello, Grace!",
+		},
+		{
+			name:    "stdin used twice",
+			ref:     "-",
+			opts:    dataOptions{dataPath: "-"},
+			stdin:   "ignored",
+			wantErr: "cannot read both the template and --data from stdin",
+		},
+		{
+			name:    "empty stdin template",
+			ref:     "-",
+			wantErr: "empty template content",
+		},
+		{
+			name:    "unknown template",
+			ref:     "missing",
+			wantErr: `failed to load template "missing"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := newMockFileSystemPort()
+			if tt.template != "" {
+				addVaultTemplate(mockFS, "greeting.md", tt.template)
+			}
+			templateRepo := templaterepo.NewFSAdapter(
+				mockFS,
+				createTemplateParser(),
+				newMockConfigPort(),
+			)
+
+			seeded := len(mockFS.GetWrittenFiles())
+
+			var out bytes.Buffer
+			err := executeRenderCommand(
+				&out,
+				tt.ref,
+				tt.opts,
+				strings.NewReader(tt.stdin),
+				createTemplateEngine(),
+				templateRepo,
+				mockFS,
+				newMockConfigPort(),
+			)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				if out.Len() != 0 {
+					t.Errorf("output = %q, want none on failure", out)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("executeRenderCommand() unexpected error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+			if len(mockFS.GetWrittenFiles()) != seeded {
+				t.Errorf("render wrote to the filesystem")
+			}
+		})
+	}
+}
@@ -0,0 +1,401 @@
+// Package cli provides CLI command implementations for the Lithos application.
+// This file contains the 'templates' command group for inspecting the
+// templates available in the vault.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/JackMatanky/lithos/internal/app/schema"
+	"github.com/JackMatanky/lithos/internal/app/template"
+	"github.com/JackMatanky/lithos/internal/domain"
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+	"github.com/spf13/cobra"
+)
+
+// NewTemplatesCommand creates and returns the 'templates' command group.
+// The check subcommand is only registered when linter is non-nil, and the
+// scaffold subcommand only when schemaEngine is non-nil.
+func NewTemplatesCommand(
+	templateEngine *template.TemplateEngine,
+	templateRepo spi.TemplateRepositoryPort,
+	fileSystemPort spi.FileSystemPort,
+	configPort spi.ConfigPort,
+	linter *template.Linter,
+	schemaEngine *schema.SchemaEngine,
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "templates",
+		Short: "Inspect the templates available in the vault",
+		Long: `Inspect the templates found in the templates directory.
+
+Templates are identified by their path relative to the templates directory
+without extension, e.g. "meeting" or "projects/kickoff". These IDs can be
+passed to "lithos new" instead of a file path.
+
+Files under _partials/ are not templates. They are shared by every template
+and referenced by name, e.g. {{template "footer" .}} for _partials/footer.md.
+A template can extend a partial as its layout by declaring "layout: <name>"
+in its header; the template body then fills the layout's "content" block and
+{{define}} overrides any other {{block}} the layout declares.`,
+	}
+
+	cmd.AddCommand(newTemplatesListCommand(templateRepo, configPort))
+	cmd.AddCommand(newTemplatesShowCommand(templateRepo))
+	cmd.AddCommand(newTemplatesImportCommand(fileSystemPort, configPort))
+	cmd.AddCommand(newTemplatesDriftCommand(
+		templateEngine,
+		templateRepo,
+		fileSystemPort,
+		configPort,
+	))
+	if linter != nil {
+		cmd.AddCommand(newTemplatesCheckCommand(linter))
+	}
+	if schemaEngine != nil {
+		cmd.AddCommand(newTemplatesScaffoldCommand(
+			schemaEngine,
+			fileSystemPort,
+			configPort,
+		))
+	}
+
+	return cmd
+}
+
+// newTemplatesListCommand creates the 'templates list' subcommand.
+func newTemplatesListCommand(
+	templateRepo spi.TemplateRepositoryPort,
+	configPort spi.ConfigPort,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List available templates",
+		Long: `List every template in the templates directory with its ID,
+file path, and the description declared in its header.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeTemplatesList(
+				cmd.OutOrStdout(),
+				templateRepo,
+				configPort,
+			)
+		},
+	}
+}
+
+// newTemplatesShowCommand creates the 'templates show' subcommand.
+func newTemplatesShowCommand(
+	templateRepo spi.TemplateRepositoryPort,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <template>",
+		Short: "Show a template's header and parameters",
+		Long: `Show the metadata a template declares in its header: description,
+output path pattern, schema, layout, and the parameters it expects with their
+type, whether they are required, default value, and description.
+
+The template is resolved the same way as for "lithos new".`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeTemplatesShow(
+				cmd.OutOrStdout(),
+				args[0],
+				templateRepo,
+			)
+		},
+	}
+}
+
+// newTemplatesCheckCommand creates the 'templates check' subcommand.
+func newTemplatesCheckCommand(linter *template.Linter) *cobra.Command {
+	return &cobra.Command{
+		Use:   "check [path...]",
+		Short: "Check templates for problems without rendering them",
+		Long: `Statically check templates and partials and report:
+
+  - syntax errors and calls to unknown functions
+  - partials that no template uses
+  - fields the schema named in the template header does not define
+  - frontmatter blocks that are not valid YAML
+
+With no arguments every template in the templates directory is checked.
+Otherwise only the given template files are checked, and unused partials are
+not reported. Issues are printed as "path:line:column: message". The command
+exits with a non-zero status when any issue is found.`,
+		// Problems in templates are not usage errors
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeTemplatesCheck(cmd.OutOrStdout(), args, linter)
+		},
+	}
+}
+
+// scaffoldOptions holds the flag values for the 'templates scaffold' command.
+type scaffoldOptions struct {
+	output string // --output path, or "-" for stdout
+	force  bool   // --force overwrites an existing template
+}
+
+// stdoutOutputPath is the --output value that prints instead of writing.
+const stdoutOutputPath = "-"
+
+// newTemplatesScaffoldCommand creates the 'templates scaffold' subcommand.
+func newTemplatesScaffoldCommand(
+	schemaEngine *schema.SchemaEngine,
+	fileSystemPort spi.FileSystemPort,
+	configPort spi.ConfigPort,
+) *cobra.Command {
+	var opts scaffoldOptions
+
+	cmd := &cobra.Command{
+		Use:   "scaffold <schema>",
+		Short: "Generate a starter template from a schema",
+		Long: `Generate a starter template for notes of a schema. The template
+names the schema in its header and opens with a frontmatter block that sets
+fileClass and lists every property of the schema, inherited ones included:
+
+  - required properties come first and are marked "# required"
+  - the allowed values of enum properties are listed in a comment
+  - date properties are pre-filled with {{ now "<format>" }}
+  - list properties start as []
+  - every other property is declared as a header param of its type and
+    filled in from template data, e.g. --set title=Launch; optional ones
+    are left out of the note when no value is given
+
+The template is written to <schema>.md in the templates directory, or to
+--output ("-" prints it instead). An existing file is only replaced with
+--force.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeTemplatesScaffold(
+				cmd.OutOrStdout(),
+				args[0],
+				opts,
+				schemaEngine,
+				fileSystemPort,
+				configPort,
+			)
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&opts.output,
+		"output",
+		"o",
+		"",
+		`path of the template to create ("-" prints it to stdout)`,
+	)
+	cmd.Flags().BoolVar(
+		&opts.force,
+		"force",
+		false,
+		"overwrite the template if it already exists",
+	)
+
+	return cmd
+}
+
+// executeTemplatesList writes a table of the available templates to out.
+func executeTemplatesList(
+	out io.Writer,
+	templateRepo spi.TemplateRepositoryPort,
+	configPort spi.ConfigPort,
+) error {
+	templates, err := templateRepo.List(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	if len(templates) == 0 {
+		_, err = fmt.Fprintf(
+			out,
+			"No templates found in %s\n",
+			configPort.Config().TemplatesDir,
+		)
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPATH\tDESCRIPTION")
+	for _, metadata := range templates {
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\n",
+			metadata.ID,
+			metadata.FilePath,
+			metadata.Description,
+		)
+	}
+	return w.Flush()
+}
+
+// executeTemplatesShow writes the header of the template identified by ref
+// to out.
+func executeTemplatesShow(
+	out io.Writer,
+	ref string,
+	templateRepo spi.TemplateRepositoryPort,
+) error {
+	tmpl, err := loadTemplate(context.Background(), ref, templateRepo)
+	if err != nil {
+		return fmt.Errorf("failed to load template %q: %w", ref, err)
+	}
+
+	header := tmpl.Header
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", tmpl.Name)
+	fmt.Fprintf(w, "Path:\t%s\n", tmpl.FilePath)
+	for _, field := range []struct{ label, value string }{
+		{"Description:", header.Description},
+		{"Output:", header.Output},
+		{"Schema:", header.Schema},
+		{"Layout:", header.Layout},
+	} {
+		if field.value != "" {
+			fmt.Fprintf(w, "%s\t%s\n", field.label, field.value)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if err := writeTemplateParams(out, header.Params); err != nil {
+		return err
+	}
+	if len(header.Bundle) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(out, "\nBundle:")
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  TEMPLATE\tOUTPUT")
+	for _, entry := range header.Bundle {
+		fmt.Fprintf(w, "  %s\t%s\n", entry.Template, entry.Output)
+	}
+	return w.Flush()
+}
+
+// writeTemplateParams writes the parameters table of a template header.
+func writeTemplateParams(out io.Writer, params []domain.TemplateParam) error {
+	if len(params) == 0 {
+		_, err := fmt.Fprintln(out, "\nParameters: none")
+		return err
+	}
+
+	fmt.Fprintln(out, "\nParameters:")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  NAME\tTYPE\tREQUIRED\tDEFAULT\tDESCRIPTION")
+	for _, param := range params {
+		required := "no"
+		if param.Required {
+			required = "yes"
+		}
+		defaultValue := ""
+		if param.Default != nil {
+			defaultValue = fmt.Sprint(param.Default)
+		}
+		fmt.Fprintf(
+			w,
+			"  %s\t%s\t%s\t%s\t%s\n",
+			param.Name,
+			param.Type,
+			required,
+			defaultValue,
+			param.Description,
+		)
+	}
+	return w.Flush()
+}
+
+// executeTemplatesCheck lints the templates at paths, or every template when
+// paths is empty, and writes the issues found to out. It returns an error
+// when any issue is found so the command exits non-zero.
+func executeTemplatesCheck(
+	out io.Writer,
+	paths []string,
+	linter *template.Linter,
+) error {
+	issues, err := linter.Check(context.Background(), paths)
+	if err != nil {
+		return fmt.Errorf("failed to check templates: %w", err)
+	}
+
+	if len(issues) == 0 {
+		_, err = fmt.Fprintln(out, "No problems found")
+		return err
+	}
+
+	for _, issue := range issues {
+		fmt.Fprintln(out, issue)
+	}
+	if len(issues) == 1 {
+		return errors.New("found 1 problem")
+	}
+	return fmt.Errorf("found %d problems", len(issues))
+}
+
+// executeTemplatesScaffold generates a starter template for the schema named
+// schemaName and writes it to its output path, or to out for "-".
+func executeTemplatesScaffold(
+	out io.Writer,
+	schemaName string,
+	opts scaffoldOptions,
+	schemaEngine *schema.SchemaEngine,
+	fileSystemPort spi.FileSystemPort,
+	configPort spi.ConfigPort,
+) error {
+	ctx := context.Background()
+
+	if result := schemaEngine.LoadRegistry(ctx); result.IsErr() {
+		return fmt.Errorf("failed to load schemas: %w", result.Error())
+	}
+	schemaResult := schemaEngine.GetSchema(ctx, schemaName)
+	if schemaResult.IsErr() {
+		return schemaResult.Error()
+	}
+
+	content, err := template.Scaffold(schemaResult.Value())
+	if err != nil {
+		return err
+	}
+
+	if opts.output == stdoutOutputPath {
+		_, err = io.WriteString(out, content)
+		return err
+	}
+
+	path := opts.output
+	if path == "" {
+		path = filepath.Join(
+			configPort.Config().TemplatesDir,
+			schemaName+".md",
+		)
+	}
+
+	if !opts.force {
+		_, statErr := fileSystemPort.Stat(path)
+		if statErr == nil {
+			return fmt.Errorf(
+				"template %q already exists (use --force to overwrite)",
+				path,
+			)
+		}
+		if !errors.Is(statErr, fs.ErrNotExist) {
+			return fmt.Errorf("failed to check %q: %w", path, statErr)
+		}
+	}
+
+	err = fileSystemPort.WriteFileAtomic(path, []byte(content))
+	if err != nil {
+		return fmt.Errorf("failed to write template %q: %w", path, err)
+	}
+	reportWritten(out, fileSystemPort, path)
+	return nil
+}
@@ -0,0 +1,349 @@
+// Package cli provides CLI command implementations for the Lithos application.
+// This file contains the 'templates drift' subcommand, which finds notes whose
+// template has changed since they were generated.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/JackMatanky/lithos/internal/app/note"
+	"github.com/JackMatanky/lithos/internal/app/template"
+	"github.com/JackMatanky/lithos/internal/domain"
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+	sharederrors "github.com/JackMatanky/lithos/internal/shared/errors"
+	"github.com/spf13/cobra"
+)
+
+// newTemplatesDriftCommand creates the 'templates drift' subcommand.
+func newTemplatesDriftCommand(
+	templateEngine *template.TemplateEngine,
+	templateRepo spi.TemplateRepositoryPort,
+	fileSystemPort spi.FileSystemPort,
+	configPort spi.ConfigPort,
+) *cobra.Command {
+	return &cobra.Command{
+		Use:   "drift [path...]",
+		Short: "List notes whose template changed since they were generated",
+		Long: `Find notes whose template has changed since they were generated.
+
+Only notes created with "lithos new --stamp" (or with stampProvenance set in
+the config) can be checked: their frontmatter records the template ID and a
+hash of the template and its partials as they were. A note has drifted when
+the hash no longer matches, or when the template is gone.
+
+For every drifted note, the template is rendered again with the note's
+frontmatter as its data, and the frontmatter keys that differ are listed:
+"+ key" for a key the template now produces that the note lacks, and
+"- key" for a key of the note the template no longer produces. Values the
+template writes out, such as "status: open", are compared too and listed as
+"~ key: old -> new". Values produced by actions are not compared, since dates
+and other computed values change on every render. Nothing is asked while
+rendering: a template that prompts for a value not in the note is reported
+as one that cannot be rendered.
+
+With no arguments every note in the vault is checked, except the templates
+directory and hidden folders. Otherwise only the given files and folders are
+checked. The command exits with a non-zero status when any note has drifted.`,
+		// Drifted notes are not usage errors
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeTemplatesDrift(
+				cmd.OutOrStdout(),
+				args,
+				templateEngine,
+				templateRepo,
+				fileSystemPort,
+				configPort,
+			)
+		},
+	}
+}
+
+// noteDrift describes a generated note whose template has changed.
+type noteDrift struct {
+	path     string   // vault-relative path of the note
+	template string   // ID of the template the note was generated from
+	problem  string   // why the keys could not be compared, if they could not
+	added    []string // keys the template now produces that the note lacks
+	removed  []string // keys of the note the template no longer produces
+	changed  []string // "key: old -> new" for values the template writes out
+}
+
+// String formats the drift as a heading line followed by one line per key.
+func (d noteDrift) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (template %q)", d.path, d.template)
+	switch {
+	case d.problem != "":
+		fmt.Fprintf(&b, "\n  %s", d.problem)
+	case len(d.added) == 0 && len(d.removed) == 0 && len(d.changed) == 0:
+		b.WriteString("\n  frontmatter unchanged; the template body changed")
+	}
+	for _, key := range d.added {
+		fmt.Fprintf(&b, "\n  + %s", key)
+	}
+	for _, key := range d.removed {
+		fmt.Fprintf(&b, "\n  - %s", key)
+	}
+	for _, change := range d.changed {
+		fmt.Fprintf(&b, "\n  ~ %s", change)
+	}
+	return b.String()
+}
+
+// executeTemplatesDrift reports the stamped notes under paths, or in the
+// whole vault when paths is empty, whose template has changed since.
+func executeTemplatesDrift(
+	out io.Writer,
+	paths []string,
+	templateEngine *template.TemplateEngine,
+	templateRepo spi.TemplateRepositoryPort,
+	fileSystemPort spi.FileSystemPort,
+	configPort spi.ConfigPort,
+) error {
+	ctx := context.Background()
+
+	notes, err := vaultNotes(paths, fileSystemPort, configPort)
+	if err != nil {
+		return err
+	}
+
+	vault, err := vaultInfo(configPort)
+	if err != nil {
+		return err
+	}
+
+	stamped := 0
+	var drifts []noteDrift
+	for _, path := range notes {
+		fm, stamp, ok, err := readStamp(path, fileSystemPort)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		stamped++
+
+		drift, drifted, err := checkDrift(
+			ctx,
+			path,
+			fm,
+			stamp,
+			vault,
+			templateEngine,
+			templateRepo,
+			configPort,
+		)
+		if err != nil {
+			return err
+		}
+		if drifted {
+			drifts = append(drifts, drift)
+		}
+	}
+
+	return reportDrifts(out, drifts, stamped)
+}
+
+// readStamp reads the frontmatter of the note at path and the provenance
+// stamped into it. It reports false for notes without a stamp, including
+// notes whose frontmatter cannot be parsed, which are not ours to judge.
+func readStamp(
+	path string,
+	fileSystemPort spi.FileSystemPort,
+) (domain.Frontmatter, note.Provenance, bool, error) {
+	content, err := fileSystemPort.ReadFile(path)
+	if err != nil {
+		return domain.Frontmatter{}, note.Provenance{}, false, fmt.Errorf(
+			"failed to read note %q: %w",
+			path,
+			err,
+		)
+	}
+	fm, _, err := note.ParseFrontmatter(string(content))
+	if err != nil {
+		return domain.Frontmatter{}, note.Provenance{}, false, nil
+	}
+	stamp, ok := note.ReadProvenance(fm)
+	return fm, stamp, ok, nil
+}
+
+// reportDrifts writes the drifted notes to out, or that there are none. It
+// returns an error when any of the stamped notes has drifted.
+func reportDrifts(out io.Writer, drifts []noteDrift, stamped int) error {
+	for _, drift := range drifts {
+		fmt.Fprintln(out, drift)
+	}
+	if len(drifts) == 0 {
+		_, err := fmt.Fprintf(
+			out,
+			"No drifted notes (%d generated notes checked)\n",
+			stamped,
+		)
+		return err
+	}
+	return fmt.Errorf(
+		"%d of %d generated notes have drifted",
+		len(drifts),
+		stamped,
+	)
+}
+
+// vaultNotes returns the Markdown files under paths, or under the vault
+// root when paths is empty, leaving out the templates directory and hidden
+// folders.
+func vaultNotes(
+	paths []string,
+	fileSystemPort spi.FileSystemPort,
+	configPort spi.ConfigPort,
+) ([]string, error) {
+	cfg := configPort.Config()
+	if len(paths) == 0 {
+		paths = []string{cfg.VaultPath}
+	}
+
+	var notes []string
+	for _, root := range paths {
+		err := fileSystemPort.Walk(root, func(path string, isDir bool) error {
+			if isDir || !strings.EqualFold(filepath.Ext(path), ".md") {
+				return nil
+			}
+			if rel, err := filepath.Rel(root, path); err == nil &&
+				isHiddenPath(rel) {
+				return nil
+			}
+			if isWithin(path, cfg.TemplatesDir) {
+				return nil
+			}
+			notes = append(notes, path)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read notes in %q: %w", root, err)
+		}
+	}
+	sort.Strings(notes)
+	return notes, nil
+}
+
+// isWithin reports whether path lies inside the directory dir.
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." &&
+		!strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// checkDrift compares the note at path, generated as recorded by stamp, with
+// its template today. It reports false when the template is unchanged.
+func checkDrift(
+	ctx context.Context,
+	path string,
+	fm domain.Frontmatter,
+	stamp note.Provenance,
+	vault domain.VaultInfo,
+	templateEngine *template.TemplateEngine,
+	templateRepo spi.TemplateRepositoryPort,
+	configPort spi.ConfigPort,
+) (noteDrift, bool, error) {
+	drift := noteDrift{
+		path:     targetInfo(path, configPort).Path,
+		template: stamp.Template,
+	}
+
+	tmpl, err := templateRepo.Get(ctx, stamp.Template)
+	var notFound sharederrors.TemplateNotFoundError
+	if errors.As(err, &notFound) {
+		drift.problem = "template no longer exists"
+		return drift, true, nil
+	}
+	if err != nil {
+		return noteDrift{}, false, fmt.Errorf(
+			"failed to load template %q: %w",
+			stamp.Template,
+			err,
+		)
+	}
+	if template.DescribeTemplate(tmpl).Hash == stamp.Hash {
+		return noteDrift{}, false, nil
+	}
+
+	rc := domain.NewRenderContext()
+	rc.Merge(fm.Fields)
+	rc.Target = targetInfo(path, configPort)
+	rc.Vault = vault
+
+	rendered, err := templateEngine.ExecuteParsedTemplateUnattended(
+		ctx,
+		tmpl,
+		rc,
+	)
+	if err != nil {
+		drift.problem = fmt.Sprintf("cannot render the template: %v", err)
+		return drift, true, nil
+	}
+	current, _, err := note.ParseFrontmatter(rendered)
+	if err != nil {
+		drift.problem = fmt.Sprintf(
+			"cannot read the template's frontmatter: %v",
+			err,
+		)
+		return drift, true, nil
+	}
+
+	drift.added = missingKeys(current.Fields, fm.Fields)
+	drift.removed = missingKeys(fm.Fields, current.Fields)
+	drift.changed = changedValues(tmpl.Content, fm.Fields, current.Fields)
+	return drift, true, nil
+}
+
+// changedValues compares the values of the note with the values the template
+// now produces, for the keys whose value the template writes out rather than
+// computes, such as "status: open". It returns one "key: old -> new" entry
+// per difference, sorted by key.
+func changedValues(
+	content string,
+	fields, current map[string]interface{},
+) []string {
+	static, _, err := template.TemplateFrontmatter(content)
+	if err != nil {
+		return nil
+	}
+
+	var changes []string
+	for key, value := range static {
+		if note.IsProvenanceKey(key) || !template.IsStaticValue(value) {
+			continue
+		}
+		old, inNote := fields[key]
+		now, inTemplate := current[key]
+		if inNote && inTemplate && !reflect.DeepEqual(old, now) {
+			changes = append(
+				changes,
+				fmt.Sprintf("%s: %v -> %v", key, old, now),
+			)
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+// missingKeys returns the sorted keys of from that are not in to, ignoring
+// the keys of the provenance stamp.
+func missingKeys(from, to map[string]interface{}) []string {
+	var keys []string
+	for key := range from {
+		if _, ok := to[key]; !ok && !note.IsProvenanceKey(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
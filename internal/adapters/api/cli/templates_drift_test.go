@@ -0,0 +1,234 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/JackMatanky/lithos/internal/adapters/spi/interactive"
+	templaterepo "github.com/JackMatanky/lithos/internal/adapters/spi/template"
+	"github.com/JackMatanky/lithos/internal/app/note"
+	"github.com/JackMatanky/lithos/internal/app/template"
+	"github.com/JackMatanky/lithos/internal/domain"
+)
+
+// addStampedNote adds a note generated from the template with the given ID
+// and content, as a walkable vault file.
+func addStampedNote(
+	t *testing.T,
+	mockFS *mockFileSystemPort,
+	path, body, templateID, templateContent string,
+) {
+	t.Helper()
+	info := template.DescribeTemplate(&domain.Template{
+		ID:      templateID,
+		Content: templateContent,
+	})
+	content, err := note.StampProvenance(body, note.Provenance{
+		Template: info.ID,
+		Hash:     info.Hash,
+		Version:  lithosVersion,
+	})
+	if err != nil {
+		t.Fatalf("StampProvenance() unexpected error = %v", err)
+	}
+	mockFS.AddFile(path, []byte(content))
+	mockFS.AddWalkPath(path)
+}
+
+func TestExecuteTemplatesDrift(t *testing.T) {
+	const before = "---\ntitle: {{ .title }}\nstage: idea\n---\n"
+	const after = "---\ntitle: {{ .title }}\nstatus: active\n---\n"
+	const launch = "---\ntitle: Launch\nstage: idea\n---\n"
+
+	tests := []struct {
+		name       string
+		template   string // current content of project.md
+		templateID string // template the note was generated from
+		wantOut    string
+		wantErr    string
+	}{
+		{
+			name:       "unchanged template",
+			template:   before,
+			templateID: "project",
+			wantOut:    "No drifted notes (1 generated notes checked)\n",
+		},
+		{
+			name:       "changed template lists the differing keys",
+			template:   after,
+			templateID: "project",
+			wantOut: "projects/launch.md (template \"project\")\n" +
+				"  + status\n  - stage\n",
+			wantErr: "1 of 1 generated notes have drifted",
+		},
+		{
+			name:       "changed literal value is listed",
+			template:   "---\ntitle: {{ .title }}\nstage: draft\n---\n",
+			templateID: "project",
+			wantOut: "projects/launch.md (template \"project\")\n" +
+				"  ~ stage: idea -> draft\n",
+			wantErr: "1 of 1 generated notes have drifted",
+		},
+		{
+			name:       "changed body",
+			template:   before + "# {{ .title }}\n",
+			templateID: "project",
+			wantOut: "projects/launch.md (template \"project\")\n" +
+				"  frontmatter unchanged; the template body changed\n",
+			wantErr: "1 of 1 generated notes have drifted",
+		},
+		{
+			name:       "missing template",
+			template:   before,
+			templateID: "retired",
+			wantOut: "projects/launch.md (template \"retired\")\n" +
+				"  template no longer exists\n",
+			wantErr: "1 of 1 generated notes have drifted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := newMockFileSystemPort()
+			addVaultTemplate(mockFS, "project.md", tt.template)
+			addStampedNote(t, mockFS, "/vault/projects/launch.md", launch,
+				tt.templateID, before)
+			// Hidden folders and unstamped notes are not checked
+			addStampedNote(t, mockFS, "/vault/.trash/old.md", launch,
+				"retired", before)
+			mockFS.AddFile("/vault/inbox.md", []byte("# Inbox\n"))
+			mockFS.AddWalkPath("/vault/inbox.md")
+
+			var out bytes.Buffer
+			err := executeTemplatesDrift(
+				&out,
+				nil,
+				createTemplateEngine(),
+				templaterepo.NewFSAdapter(
+					mockFS,
+					createTemplateParser(),
+					newMockConfigPort(),
+				),
+				mockFS,
+				newMockConfigPort(),
+			)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("executeTemplatesDrift() error = %v, want %q",
+						err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("executeTemplatesDrift() unexpected error = %v", err)
+			}
+			if got := out.String(); got != tt.wantOut {
+				t.Errorf("output = %q, want %q", got, tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestExecuteTemplatesDrift_DoesNotAsk(t *testing.T) {
+	const before = "---\ntitle: {{ .title }}\n---\n"
+	const after = "---\ntitle: {{ .title }}\n" +
+		"status: {{ prompt \"Status\" }}\n---\n"
+
+	mockFS := newMockFileSystemPort()
+	addVaultTemplate(mockFS, "project.md", after)
+	addStampedNote(t, mockFS, "/vault/projects/launch.md",
+		"---\ntitle: Launch\n---\n", "project", before)
+
+	// Answers are at hand, but drift must not ask for them
+	promptPort := interactive.NewScriptedAdapter(nil)
+	promptPort.AddAnswers(map[string]string{"Status": "open"})
+	opts := template.FuncMapOptions{
+		Prompter: template.NewPrompter(promptPort),
+	}
+	parser := template.NewStaticTemplateParserWithOptions(opts)
+	engine := template.NewTemplateEngineWithOptions(
+		parser,
+		template.NewGoTemplateExecutor(),
+		opts,
+	)
+
+	var out bytes.Buffer
+	err := executeTemplatesDrift(
+		&out,
+		nil,
+		engine,
+		templaterepo.NewFSAdapter(mockFS, parser, newMockConfigPort()),
+		mockFS,
+		newMockConfigPort(),
+	)
+	if err == nil {
+		t.Fatal("executeTemplatesDrift() expected drift error, got nil")
+	}
+	want := "projects/launch.md (template \"project\")\n" +
+		"  cannot render the template: template asks \"Status\", " +
+		"which cannot be answered here\n"
+	if got := out.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestExecuteNewCommand_Stamp(t *testing.T) {
+	const project = "{{- /* lithos\noutput: projects/{{ .title | slug }}.md\n" +
+		"*/ -}}\n---\ntitle: {{ .title }}\n---\n# {{ .title }}\n"
+
+	mockFS := newMockFileSystemPort()
+	addVaultTemplate(mockFS, "project.md", project)
+	templateRepo := templaterepo.NewFSAdapter(
+		mockFS,
+		createTemplateParser(),
+		newMockConfigPort(),
+	)
+
+	err := executeNewCommand(
+		"project",
+		newOptions{
+			data:  dataOptions{setPairs: []string{"title=Launch"}},
+			stamp: true,
+		},
+		strings.NewReader(""),
+		createTemplateEngine(),
+		templateRepo,
+		mockFS,
+		newMockConfigPort(),
+		note.NewWriter(mockFS),
+		nil,
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("executeNewCommand() unexpected error = %v", err)
+	}
+
+	const path = "/vault/projects/launch.md"
+	got := string(mockFS.GetWrittenFiles()[path])
+	want := "---\ntitle: Launch\nlithos_template: project\n" +
+		"lithos_template_hash: " +
+		template.DescribeTemplate(&domain.Template{Content: project}).Hash +
+		"\nlithos_version: " + lithosVersion + "\n---\n# Launch\n"
+	if got != want {
+		t.Fatalf("note = %q, want %q", got, want)
+	}
+
+	// The note just generated has not drifted from its template
+	mockFS.AddWalkPath(path)
+	var out bytes.Buffer
+	err = executeTemplatesDrift(
+		&out,
+		nil,
+		createTemplateEngine(),
+		templateRepo,
+		mockFS,
+		newMockConfigPort(),
+	)
+	if err != nil {
+		t.Fatalf("executeTemplatesDrift() unexpected error = %v", err)
+	}
+	want = "No drifted notes (1 generated notes checked)\n"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
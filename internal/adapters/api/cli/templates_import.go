@@ -0,0 +1,280 @@
+// Package cli provides CLI command implementations for the Lithos application.
+// This file contains the 'templates import' subcommand, which converts
+// templates written for other tools into lithos templates.
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/JackMatanky/lithos/internal/app/template"
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+	"github.com/spf13/cobra"
+)
+
+// importFromTemplater is the --from value for Obsidian Templater templates.
+const importFromTemplater = "templater"
+
+// importOptions holds the flag values for the 'templates import' command.
+type importOptions struct {
+	from   string // --from format of the source templates
+	output string // --output directory, defaulting to the templates directory
+	force  bool   // --force overwrites existing templates
+}
+
+// importedTemplate is a converted template and the path it is written to.
+type importedTemplate struct {
+	path   string
+	result template.TemplaterImport
+}
+
+// newTemplatesImportCommand creates the 'templates import' subcommand.
+func newTemplatesImportCommand(
+	fileSystemPort spi.FileSystemPort,
+	configPort spi.ConfigPort,
+) *cobra.Command {
+	var opts importOptions
+
+	cmd := &cobra.Command{
+		Use:   "import --from templater <dir>",
+		Short: "Convert templates from another tool into lithos templates",
+		Long: `Convert every Markdown template under a directory into a lithos
+template. The only supported source is Obsidian's Templater plugin
+(--from templater), whose tags are translated as follows:
+
+  tp.file.title                  {{ .Target.Basename }}
+  tp.date.now(format, days)      {{ now "<layout>" }}, or dateAdd for days
+  tp.date.tomorrow(format)       {{ relativeDate "tomorrow" | dateFormat }}
+  tp.date.yesterday(format)      {{ relativeDate "yesterday" | dateFormat }}
+  tp.file.creation_date(format)  {{ now "<layout>" }}
+  tp.frontmatter.<key>           {{ .<key> }}
+  tp.file.include("[[name]]")    {{ template "name" . }}
+  tp.file.cursor()               removed
+
+Moment.js formats become Go layouts, and Templater's whitespace control
+becomes trim markers. Everything else, including every <%* %> block, is kept
+as written and reported as "path:line:column: message" for manual
+conversion.
+
+Templates are written to the same relative paths under the templates
+directory, or under --output. Nothing is written if a target already exists,
+unless --force is given. The command exits with a non-zero status when any
+construct could not be converted.`,
+		Args: cobra.ExactArgs(1),
+		// Unconverted constructs are not usage errors
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeTemplatesImport(
+				cmd.OutOrStdout(),
+				args[0],
+				opts,
+				fileSystemPort,
+				configPort,
+			)
+		},
+	}
+
+	addImportFlags(cmd, &opts)
+	return cmd
+}
+
+// addImportFlags registers the flags of 'templates import' on cmd, storing
+// their values in opts.
+func addImportFlags(cmd *cobra.Command, opts *importOptions) {
+	cmd.Flags().StringVar(
+		&opts.from,
+		"from",
+		"",
+		`format of the source templates ("templater")`,
+	)
+	_ = cmd.MarkFlagRequired("from")
+	cmd.Flags().StringVarP(
+		&opts.output,
+		"output",
+		"o",
+		"",
+		"directory to write the templates to (default: templates directory)",
+	)
+	cmd.Flags().BoolVar(
+		&opts.force,
+		"force",
+		false,
+		"overwrite templates that already exist",
+	)
+}
+
+// executeTemplatesImport converts the templates under dir and writes them to
+// the output directory. The unconverted constructs are listed on out.
+func executeTemplatesImport(
+	out io.Writer,
+	dir string,
+	opts importOptions,
+	fileSystemPort spi.FileSystemPort,
+	configPort spi.ConfigPort,
+) error {
+	if opts.from != importFromTemplater {
+		return fmt.Errorf(
+			"unsupported --from value %q: expected %q",
+			opts.from,
+			importFromTemplater,
+		)
+	}
+
+	outputDir := opts.output
+	if outputDir == "" {
+		outputDir = configPort.Config().TemplatesDir
+	}
+
+	imported, err := importTemplater(dir, outputDir, fileSystemPort)
+	if err != nil {
+		return err
+	}
+	if len(imported) == 0 {
+		return fmt.Errorf("no Markdown templates found in %q", dir)
+	}
+
+	// Check every target before writing any of them
+	if !opts.force {
+		if err := checkImportTargets(imported, fileSystemPort); err != nil {
+			return err
+		}
+	}
+
+	for _, tmpl := range imported {
+		err := fileSystemPort.WriteFileAtomic(
+			tmpl.path,
+			[]byte(tmpl.result.Content),
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"failed to write template %q: %w",
+				tmpl.path,
+				err,
+			)
+		}
+		reportWritten(out, fileSystemPort, tmpl.path)
+	}
+
+	return reportImport(out, imported)
+}
+
+// checkImportTargets returns an error when the target of any imported
+// template already exists.
+func checkImportTargets(
+	imported []importedTemplate,
+	fileSystemPort spi.FileSystemPort,
+) error {
+	for _, tmpl := range imported {
+		_, err := fileSystemPort.Stat(tmpl.path)
+		if err == nil {
+			return fmt.Errorf(
+				"template %q already exists (use --force to overwrite)",
+				tmpl.path,
+			)
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to check %q: %w", tmpl.path, err)
+		}
+	}
+	return nil
+}
+
+// reportImport lists the unconverted constructs of the imported templates
+// on out, followed by a summary. It returns an error when any construct
+// could not be converted.
+func reportImport(out io.Writer, imported []importedTemplate) error {
+	unconverted := 0
+	for _, tmpl := range imported {
+		for _, issue := range tmpl.result.Issues {
+			fmt.Fprintln(out, issue)
+		}
+		unconverted += len(tmpl.result.Issues)
+	}
+
+	needs := "need"
+	if unconverted == 1 {
+		needs = "needs"
+	}
+	fmt.Fprintf(
+		out,
+		"Imported %s; %s %s manual conversion\n",
+		countOf(len(imported), "template"),
+		countOf(unconverted, "construct"),
+		needs,
+	)
+	if unconverted > 0 {
+		return fmt.Errorf(
+			"%s could not be converted",
+			countOf(unconverted, "construct"),
+		)
+	}
+	return nil
+}
+
+// countOf formats n followed by noun, adding an "s" unless n is 1.
+func countOf(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// importTemplater converts every Markdown file under dir, outside hidden
+// directories, and maps it to the same relative path under outputDir. The
+// templates are sorted by source path.
+func importTemplater(
+	dir string,
+	outputDir string,
+	fileSystemPort spi.FileSystemPort,
+) ([]importedTemplate, error) {
+	var sources []string
+	err := fileSystemPort.Walk(dir, func(path string, isDir bool) error {
+		if !isDir && strings.EqualFold(filepath.Ext(path), ".md") {
+			sources = append(sources, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read templates in %q: %w", dir, err)
+	}
+	sort.Strings(sources)
+
+	imported := make([]importedTemplate, 0, len(sources))
+	for _, source := range sources {
+		rel, err := filepath.Rel(dir, source)
+		if err != nil || isHiddenPath(rel) {
+			continue
+		}
+
+		content, err := fileSystemPort.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to read template %q: %w",
+				source,
+				err,
+			)
+		}
+
+		imported = append(imported, importedTemplate{
+			path:   filepath.Join(outputDir, rel),
+			result: template.ImportTemplater(source, string(content)),
+		})
+	}
+	return imported, nil
+}
+
+// isHiddenPath reports whether any element of the relative path rel starts
+// with a dot.
+func isHiddenPath(rel string) bool {
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		if strings.HasPrefix(part, ".") && part != "." && part != ".." {
+			return true
+		}
+	}
+	return false
+}
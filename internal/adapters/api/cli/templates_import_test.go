@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	templaterepo "github.com/JackMatanky/lithos/internal/adapters/spi/template"
+	"github.com/JackMatanky/lithos/internal/domain"
+)
+
+func TestExecuteTemplatesImport(t *testing.T) {
+	const meeting = "---\ndate: <% tp.date.now(\"YYYY-MM-DD\") %>\n---\n" +
+		"# <% tp.file.title %>\n<% tp.file.cursor() %>"
+	const clipboard = "Owner: <% tp.system.clipboard() %>\n"
+
+	tests := []struct {
+		name     string
+		opts     importOptions
+		sources  map[string]string
+		existing map[string]string
+		want     []string // paths written under /vault/templates
+		wantOut  string
+		wantErr  string
+	}{
+		{
+			name:    "converts every template",
+			sources: map[string]string{"meeting.md": meeting},
+			want:    []string{"meeting.md"},
+			wantOut: "Imported 1 template; 0 constructs need manual " +
+				"conversion\n",
+		},
+		{
+			name: "reports unconverted constructs",
+			sources: map[string]string{
+				"meeting.md":      meeting,
+				"people/owner.md": clipboard,
+			},
+			want: []string{"meeting.md", "people/owner.md"},
+			wantOut: "Templater/people/owner.md:1:8: cannot convert " +
+				`"tp.system.clipboard()": tp.system.clipboard has no ` +
+				"lithos equivalent\n" +
+				"Imported 2 templates; 1 construct needs manual conversion\n",
+			wantErr: "1 construct could not be converted",
+		},
+		{
+			name:    "existing template writes nothing",
+			sources: map[string]string{"meeting.md": meeting},
+			existing: map[string]string{
+				"/vault/templates/meeting.md": "old",
+			},
+			wantErr: "already exists (use --force to overwrite)",
+		},
+		{
+			name:    "unsupported source format",
+			opts:    importOptions{from: "obsidian"},
+			sources: map[string]string{"meeting.md": meeting},
+			wantErr: `unsupported --from value "obsidian"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := newMockFileSystemPort()
+			for rel, content := range tt.sources {
+				path := "Templater/" + rel
+				mockFS.AddFile(path, []byte(content))
+				mockFS.AddWalkPath(path)
+			}
+			for path, content := range tt.existing {
+				mockFS.AddFile(path, []byte(content))
+			}
+
+			opts := tt.opts
+			if opts.from == "" {
+				opts.from = importFromTemplater
+			}
+
+			var out bytes.Buffer
+			err := executeTemplatesImport(
+				&out,
+				"Templater",
+				opts,
+				mockFS,
+				newMockConfigPort(),
+			)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("executeTemplatesImport() error = %v, want %q",
+						err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("executeTemplatesImport() unexpected error = %v",
+					err)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output = %q, want it to contain %q",
+					out.String(), tt.wantOut)
+			}
+
+			written := mockFS.GetWrittenFiles()
+			for _, rel := range tt.want {
+				if _, ok := written["/vault/templates/"+rel]; !ok {
+					t.Errorf("template %q was not written", rel)
+				}
+			}
+			if len(tt.want) == 0 && len(tt.existing) > 0 {
+				if got := string(
+					written["/vault/templates/meeting.md"],
+				); got != "old" {
+					t.Errorf("existing template = %q, want it unchanged", got)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteTemplatesImport_RendersConvertedTemplate(t *testing.T) {
+	mockFS := newMockFileSystemPort()
+	mockFS.AddFile(
+		"Templater/meeting.md",
+		[]byte("# <% tp.file.title %> on <% tp.date.now(\"YYYY\") %>\n"),
+	)
+	mockFS.AddWalkPath("Templater/meeting.md")
+
+	err := executeTemplatesImport(
+		&bytes.Buffer{},
+		"Templater",
+		importOptions{from: importFromTemplater},
+		mockFS,
+		newMockConfigPort(),
+	)
+	if err != nil {
+		t.Fatalf("executeTemplatesImport() unexpected error = %v", err)
+	}
+
+	repo := templaterepo.NewFSAdapter(
+		mockFS,
+		createTemplateParser(),
+		newMockConfigPort(),
+	)
+	tmpl, err := repo.GetByPath(t.Context(), "/vault/templates/meeting.md")
+	if err != nil {
+		t.Fatalf("GetByPath() unexpected error = %v", err)
+	}
+
+	rc := domain.NewRenderContext()
+	rc.Target = domain.TargetInfo{Basename: "Kickoff"}
+	rendered, err := createTemplateEngine().ExecuteParsedTemplate(
+		t.Context(),
+		tmpl,
+		rc,
+	)
+	if err != nil {
+		t.Fatalf("ExecuteParsedTemplate() unexpected error = %v", err)
+	}
+	if !strings.HasPrefix(rendered, "# Kickoff on 20") {
+		t.Errorf("rendered = %q, want it to start with %q",
+			rendered, "# Kickoff on 20")
+	}
+}
@@ -0,0 +1,394 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	templaterepo "github.com/JackMatanky/lithos/internal/adapters/spi/template"
+	"github.com/JackMatanky/lithos/internal/app/note"
+	"github.com/JackMatanky/lithos/internal/app/schema"
+	"github.com/JackMatanky/lithos/internal/app/template"
+	"github.com/JackMatanky/lithos/internal/domain"
+)
+
+// addVaultTemplate adds a template to mockFS under the mock config's
+// templates directory and returns its path.
+func addVaultTemplate(mockFS *mockFileSystemPort, rel, content string) string {
+	path := filepath.Join(
+		newMockConfigPort().Config().TemplatesDir,
+		filepath.FromSlash(rel),
+	)
+	mockFS.AddFile(path, []byte(content))
+	mockFS.AddWalkPath(path)
+	return path
+}
+
+func TestExecuteTemplatesList(t *testing.T) {
+	mockFS := newMockFileSystemPort()
+	meetingPath := addVaultTemplate(
+		mockFS,
+		"meeting.md",
+		"{{/* lithos\ndescription: Weekly team meeting\n*/}}\n# Meeting",
+	)
+	kickoffPath := addVaultTemplate(mockFS, "projects/kickoff.md", "# Kickoff")
+
+	templateRepo := templaterepo.NewFSAdapter(
+		mockFS,
+		createTemplateParser(),
+		newMockConfigPort(),
+	)
+
+	var out bytes.Buffer
+	if err := executeTemplatesList(
+		&out,
+		templateRepo,
+		newMockConfigPort(),
+	); err != nil {
+		t.Fatalf("executeTemplatesList() unexpected error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and 2 rows, got:\n%s", out.String())
+	}
+	if fields := strings.Fields(lines[0]); strings.Join(fields, " ") !=
+		"NAME PATH DESCRIPTION" {
+		t.Errorf("header = %q", lines[0])
+	}
+	if fields := strings.Fields(lines[1]); fields[0] != "meeting" ||
+		fields[1] != meetingPath ||
+		strings.Join(fields[2:], " ") != "Weekly team meeting" {
+		t.Errorf("meeting row = %q", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); fields[0] != "projects/kickoff" ||
+		fields[1] != kickoffPath || len(fields) != 2 {
+		t.Errorf("kickoff row = %q", lines[2])
+	}
+}
+
+func TestExecuteTemplatesList_Empty(t *testing.T) {
+	mockFS := newMockFileSystemPort()
+	templateRepo := templaterepo.NewFSAdapter(
+		mockFS,
+		createTemplateParser(),
+		newMockConfigPort(),
+	)
+
+	var out bytes.Buffer
+	if err := executeTemplatesList(
+		&out,
+		templateRepo,
+		newMockConfigPort(),
+	); err != nil {
+		t.Fatalf("executeTemplatesList() unexpected error = %v", err)
+	}
+
+	if !strings.HasPrefix(out.String(), "No templates found in ") {
+		t.Errorf("output = %q, want empty-directory notice", out.String())
+	}
+}
+
+func TestExecuteTemplatesShow(t *testing.T) {
+	mockFS := newMockFileSystemPort()
+	path := addVaultTemplate(
+		mockFS,
+		"meeting.md",
+		"{{/* lithos\n"+
+			"description: Weekly team meeting\n"+
+			"output: meetings/{{.title | slug}}.md\n"+
+			"params:\n"+
+			"  - name: title\n"+
+			"    required: true\n"+
+			"    description: Meeting title\n"+
+			"  - name: duration\n"+
+			"    type: integer\n"+
+			"    default: 30\n"+
+			"*/}}\n# {{.title}}",
+	)
+	addVaultTemplate(mockFS, "plain.md", "# Plain")
+	templateRepo := templaterepo.NewFSAdapter(
+		mockFS,
+		createTemplateParser(),
+		newMockConfigPort(),
+	)
+
+	var out bytes.Buffer
+	if err := executeTemplatesShow(&out, "meeting", templateRepo); err != nil {
+		t.Fatalf("executeTemplatesShow() unexpected error = %v", err)
+	}
+
+	for _, want := range []string{
+		"Name:         meeting",
+		"Path:         " + path,
+		"Description:  Weekly team meeting",
+		"Output:       meetings/{{.title | slug}}.md",
+		"NAME      TYPE     REQUIRED  DEFAULT  DESCRIPTION",
+		"title     string   yes                Meeting title",
+		"duration  integer  no        30",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := executeTemplatesShow(&out, "plain", templateRepo); err != nil {
+		t.Fatalf("executeTemplatesShow() unexpected error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Parameters: none") {
+		t.Errorf("output = %q, want no parameters notice", out.String())
+	}
+}
+
+func TestExecuteTemplatesCheck(t *testing.T) {
+	mockFS := newMockFileSystemPort()
+	addVaultTemplate(mockFS, "good.md", "# {{.title | toUpper}}")
+	templateRepo := templaterepo.NewFSAdapter(
+		mockFS,
+		createTemplateParser(),
+		newMockConfigPort(),
+	)
+	linter := template.NewLinter(templateRepo, mockFS, nil)
+
+	var out bytes.Buffer
+	if err := executeTemplatesCheck(&out, nil, linter); err != nil {
+		t.Fatalf("executeTemplatesCheck() unexpected error = %v", err)
+	}
+	if out.String() != "No problems found\n" {
+		t.Errorf("output = %q, want no problems", out.String())
+	}
+
+	badPath := addVaultTemplate(mockFS, "bad.md", "# {{.title | shout}}")
+	out.Reset()
+	err := executeTemplatesCheck(&out, nil, linter)
+	if err == nil || err.Error() != "found 1 problem" {
+		t.Fatalf("executeTemplatesCheck() error = %v, want 1 problem", err)
+	}
+	want := badPath + `:1:14: unknown function "shout"` + "\n"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
+
+// staticSchemas is a schema registry populated up front.
+type staticSchemas map[string]domain.Schema
+
+func (s staticSchemas) Get(name string) (domain.Schema, bool) {
+	found, ok := s[name]
+	return found, ok
+}
+
+func TestExecuteTemplatesScaffold(t *testing.T) {
+	const want = "{{- /* lithos\n" +
+		"description: Scaffolded from the contact schema\n" +
+		"schema: contact\n" +
+		"params:\n" +
+		"  - name: name\n" +
+		"    type: string\n" +
+		"    required: true\n" +
+		"*/ -}}\n" +
+		"---\n" +
+		"fileClass: contact\n" +
+		"name: {{ .name | quoteYAML }} # required\n" +
+		"---\n"
+	templatePath := filepath.Join(
+		newMockConfigPort().Config().TemplatesDir,
+		"contact.md",
+	)
+
+	tests := []struct {
+		name     string
+		schema   string
+		opts     scaffoldOptions
+		existing bool
+		wantPath string
+		wantOut  string
+		wantErr  string
+	}{
+		{
+			name:     "written to the templates directory",
+			schema:   "contact",
+			wantPath: templatePath,
+			wantOut:  "Created " + templatePath + "\n",
+		},
+		{
+			name:     "written to output path",
+			schema:   "contact",
+			opts:     scaffoldOptions{output: "people.md"},
+			wantPath: "people.md",
+			wantOut:  "Created people.md\n",
+		},
+		{
+			name:    "printed to stdout",
+			schema:  "contact",
+			opts:    scaffoldOptions{output: "-"},
+			wantOut: want,
+		},
+		{
+			name:     "existing template is kept",
+			schema:   "contact",
+			existing: true,
+			wantErr:  "already exists (use --force to overwrite)",
+		},
+		{
+			name:     "force overwrites existing template",
+			schema:   "contact",
+			opts:     scaffoldOptions{force: true},
+			existing: true,
+			wantPath: templatePath,
+			wantOut:  "Created " + templatePath + "\n",
+		},
+		{
+			name:    "unknown schema",
+			schema:  "missing",
+			wantErr: "missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := newMockFileSystemPort()
+			if tt.existing {
+				mockFS.AddFile(templatePath, []byte("old"))
+			}
+			engine := schema.NewSchemaEngine(
+				nil,
+				staticSchemas{
+					"contact": domain.NewSchema("contact", []domain.Property{
+						domain.NewProperty(
+							"name", true, false, domain.StringPropertySpec{},
+						),
+					}),
+				},
+				schema.NewSchemaValidator(),
+			)
+
+			var out bytes.Buffer
+			err := executeTemplatesScaffold(
+				&out,
+				tt.schema,
+				tt.opts,
+				engine,
+				mockFS,
+				newMockConfigPort(),
+			)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("executeTemplatesScaffold() error = %v, want %q",
+						err, tt.wantErr)
+				}
+				if tt.existing &&
+					string(mockFS.GetWrittenFiles()[templatePath]) != "old" {
+					t.Error("existing template should be left untouched")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("executeTemplatesScaffold() error = %v", err)
+			}
+			if out.String() != tt.wantOut {
+				t.Errorf("output = %q, want %q", out.String(), tt.wantOut)
+			}
+			if tt.wantPath != "" {
+				got := string(mockFS.GetWrittenFiles()[tt.wantPath])
+				if got != want {
+					t.Errorf("%s =\n%s\nwant\n%s", tt.wantPath, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteTemplatesScaffold_NewNoteMatchesSchema(t *testing.T) {
+	const notePath = "/vault/tasks/launch.md"
+
+	tests := []struct {
+		name    string
+		set     []string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "set values fill the properties",
+			set: []string{
+				"title=Launch: phase 1", "status=open",
+				"estimate=2.5", "urgent=true",
+			},
+			want: []string{
+				"title: \"Launch: phase 1\"",
+				"estimate: 2.5",
+				"tags: []\nurgent: true",
+			},
+		},
+		{
+			name:    "required values must be given",
+			set:     []string{"status=open"},
+			wantErr: "title",
+		},
+		{
+			name:    "values are checked against the schema",
+			set:     []string{"title=Launch", "status=later"},
+			wantErr: "field 'status'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := newMockFileSystemPort()
+			err := executeTemplatesScaffold(
+				&bytes.Buffer{},
+				"task",
+				scaffoldOptions{},
+				newTaskSchemaEngine(),
+				mockFS,
+				newMockConfigPort(),
+			)
+			if err != nil {
+				t.Fatalf("executeTemplatesScaffold() error = %v", err)
+			}
+			mockFS.AddWalkPath(filepath.Join(
+				newMockConfigPort().Config().TemplatesDir,
+				"task.md",
+			))
+
+			opts := newOptions{
+				data:   dataOptions{setPairs: tt.set},
+				output: notePath,
+			}
+			err = executeNewCommand(
+				"task",
+				opts,
+				strings.NewReader(""),
+				createTemplateEngine(),
+				templaterepo.NewFSAdapter(
+					mockFS,
+					createTemplateParser(),
+					newMockConfigPort(),
+				),
+				mockFS,
+				newMockConfigPort(),
+				note.NewWriter(mockFS),
+				validatorFor(opts, newTaskSchemaEngine(), nil),
+				nil,
+			)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("executeNewCommand() error = %v, want %q",
+						err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("executeNewCommand() unexpected error = %v", err)
+			}
+			got := string(mockFS.GetWrittenFiles()[notePath])
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("note =\n%s\nwant it to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Package cli provides CLI command implementations for the Lithos application.
+// This file contains the validation of rendered notes against the schema
+// named by their fileClass before they are written.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+
+	"github.com/JackMatanky/lithos/internal/app/frontmatter"
+	"github.com/JackMatanky/lithos/internal/app/note"
+	"github.com/JackMatanky/lithos/internal/app/schema"
+	sharederrors "github.com/JackMatanky/lithos/internal/shared/errors"
+)
+
+// noteValidator checks rendered notes against their schema. Schemas are
+// loaded on the first note that names one, so notes without a fileClass work
+// without a schemas directory. A nil noteValidator accepts every note.
+type noteValidator struct {
+	schemaEngine *schema.SchemaEngine
+	validator    *frontmatter.FrontmatterValidator
+	warnings     io.Writer
+	loaded       bool
+	noSchemas    bool
+}
+
+// newNoteValidator creates a noteValidator backed by schemaEngine, or returns
+// nil to skip validation when schemaEngine is nil. Notes that cannot be
+// validated because their schema is missing are reported to warnings.
+func newNoteValidator(
+	schemaEngine *schema.SchemaEngine,
+	warnings io.Writer,
+) *noteValidator {
+	if schemaEngine == nil {
+		return nil
+	}
+	return &noteValidator{
+		schemaEngine: schemaEngine,
+		validator:    frontmatter.NewFrontmatterValidator(schemaEngine),
+		warnings:     warnings,
+	}
+}
+
+// Validate checks the frontmatter of content against the schema named by its
+// fileClass. Notes without frontmatter or without a fileClass are accepted,
+// and so are notes whose schema does not exist or whose vault has no schemas
+// directory, with a warning. Invalid fields are listed one per line in the
+// returned error.
+func (v *noteValidator) Validate(ctx context.Context, content string) error {
+	if v == nil {
+		return nil
+	}
+
+	fm, found, err := note.ParseFrontmatter(content)
+	if err != nil {
+		return fmt.Errorf(
+			"rendered note has invalid frontmatter "+
+				"(use --no-validate to write it anyway): %w",
+			err,
+		)
+	}
+	if !found || fm.SchemaName() == "" {
+		return nil
+	}
+
+	if err := v.load(ctx); err != nil {
+		return err
+	}
+	if v.noSchemas {
+		return nil
+	}
+
+	result := v.validator.Validate(ctx, fm.SchemaName(), fm)
+	if result.IsOk() {
+		return nil
+	}
+	var notFound sharederrors.SchemaNotFoundError
+	if errors.As(result.Error(), &notFound) {
+		v.warn(
+			"schema %q not found; the note is written without validation",
+			fm.SchemaName(),
+		)
+		return nil
+	}
+
+	var invalid sharederrors.FrontmatterValidationError
+	if !errors.As(result.Error(), &invalid) {
+		return fmt.Errorf(
+			"failed to validate note against schema %q: %w",
+			fm.SchemaName(),
+			result.Error(),
+		)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(
+		&b,
+		"rendered note does not match schema %q "+
+			"(use --no-validate to write it anyway):",
+		invalid.Schema(),
+	)
+	for _, field := range invalid.Fields() {
+		fmt.Fprintf(&b, "\n  - %v", field)
+	}
+	return errors.New(b.String())
+}
+
+// load loads the schemas on first use. A vault without a schemas directory
+// is warned about once, and its notes are not validated.
+func (v *noteValidator) load(ctx context.Context) error {
+	if v.loaded {
+		return nil
+	}
+	result := v.schemaEngine.LoadRegistry(ctx)
+	if result.IsErr() && !errors.Is(result.Error(), fs.ErrNotExist) {
+		return fmt.Errorf("failed to load schemas: %w", result.Error())
+	}
+	v.loaded = true
+	if result.IsErr() {
+		v.noSchemas = true
+		v.warn("no schemas directory; notes are written without validation")
+	}
+	return nil
+}
+
+// warn reports a note that cannot be validated.
+func (v *noteValidator) warn(format string, args ...interface{}) {
+	if v.warnings != nil {
+		fmt.Fprintf(v.warnings, "warning: "+format+"\n", args...)
+	}
+}
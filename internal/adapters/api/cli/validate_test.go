@@ -0,0 +1,272 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JackMatanky/lithos/internal/adapters/spi/filesystem"
+	schemarepo "github.com/JackMatanky/lithos/internal/adapters/spi/schema"
+	templaterepo "github.com/JackMatanky/lithos/internal/adapters/spi/template"
+	"github.com/JackMatanky/lithos/internal/app/note"
+	"github.com/JackMatanky/lithos/internal/app/schema"
+	"github.com/JackMatanky/lithos/internal/domain"
+	"github.com/JackMatanky/lithos/internal/shared/clock"
+	testutils "github.com/JackMatanky/lithos/tests/utils"
+)
+
+// newContactSchemaEngine returns a schema engine serving a "contact" schema
+// with a required name and an optional date.
+func newContactSchemaEngine() *schema.SchemaEngine {
+	return schema.NewSchemaEngine(
+		nil,
+		staticSchemas{
+			"contact": domain.NewSchema("contact", []domain.Property{
+				domain.NewProperty(
+					"name", true, false, &domain.StringPropertySpec{},
+				),
+				domain.NewProperty(
+					"met", false, false, &domain.DatePropertySpec{
+						Format: "2006-01-02",
+					},
+				),
+			}),
+		},
+		schema.NewSchemaValidator(),
+	)
+}
+
+func TestNoteValidator_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantErr     []string
+		wantWarning string
+	}{
+		{
+			name: "valid note",
+			content: "---\nfileClass: contact\n" +
+				"name: Ada\nmet: 2025-01-02\n---\n",
+		},
+		{
+			name:    "no frontmatter",
+			content: "# Ada\n",
+		},
+		{
+			name:    "no fileClass",
+			content: "---\ntitle: Ada\n---\n",
+		},
+		{
+			name:    "every invalid field is reported",
+			content: "---\nfileClass: contact\nmet: 02/01/2025\n---\n",
+			wantErr: []string{
+				`does not match schema "contact"`,
+				"\n  - field 'name': is required but missing",
+				"\n  - field 'met': must be a valid date in format",
+			},
+		},
+		{
+			name:    "unknown schema is warned about",
+			content: "---\nfileClass: person\n---\n",
+			wantWarning: "warning: schema \"person\" not found; " +
+				"the note is written without validation\n",
+		},
+		{
+			name:    "invalid frontmatter",
+			content: "---\nname: [Ada\n---\n",
+			wantErr: []string{"invalid frontmatter"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var warnings bytes.Buffer
+			validator := newNoteValidator(newContactSchemaEngine(), &warnings)
+
+			err := validator.Validate(t.Context(), tt.content)
+			if got := warnings.String(); got != tt.wantWarning {
+				t.Errorf("warnings = %q, want %q", got, tt.wantWarning)
+			}
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() error = nil, want %q", tt.wantErr)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() error = %q, want it to contain %q",
+						err.Error(), want)
+				}
+			}
+		})
+	}
+}
+
+// newVaultNoteValidator returns a noteValidator loading schemas from a vault
+// on disk through the schema loader. The vault has a "task" schema unless
+// withSchemas is false, in which case it has no schemas directory at all.
+func newVaultNoteValidator(
+	t *testing.T,
+	withSchemas bool,
+	warnings io.Writer,
+) *noteValidator {
+	t.Helper()
+	configPort := testutils.NewMockConfigPort(t.TempDir())
+	schemasDir := configPort.Config().SchemasDir
+	if withSchemas {
+		propertiesDir := filepath.Join(schemasDir, "properties")
+		if err := os.MkdirAll(propertiesDir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		schemaPath := filepath.Join(schemasDir, "task.json")
+		if err := os.WriteFile(schemaPath, []byte(`{
+			"name": "task",
+			"properties": {
+				"title": {"type": "string", "required": true},
+				"status": {"type": "string", "enum": ["open", "done"]}
+			}
+		}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	loader := schemarepo.NewSchemaLoaderAdapter(
+		filesystem.NewLocalFileSystemAdapter(),
+		configPort,
+	)
+	return newNoteValidator(
+		schema.NewSchemaEngine(
+			loader,
+			schemarepo.NewSchemaRegistryAdapter(loader, configPort),
+			schema.NewSchemaValidator(),
+		),
+		warnings,
+	)
+}
+
+func TestNoteValidator_Validate_LoadedSchema(t *testing.T) {
+	validator := newVaultNoteValidator(t, true, nil)
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "valid note",
+			content: "---\nfileClass: task\ntitle: Hello\nstatus: open\n---\n",
+		},
+		{
+			name:    "value outside the enum",
+			content: "---\nfileClass: task\ntitle: Hello\nstatus: later\n---\n",
+			wantErr: "field 'status'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate(t.Context(), tt.content)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNoteValidator_Validate_NoSchemasDirectory(t *testing.T) {
+	var warnings bytes.Buffer
+	validator := newVaultNoteValidator(t, false, &warnings)
+
+	for _, content := range []string{
+		"---\nfileClass: task\nstatus: later\n---\n",
+		"---\nfileClass: person\n---\n",
+	} {
+		if err := validator.Validate(t.Context(), content); err != nil {
+			t.Fatalf("Validate() unexpected error = %v", err)
+		}
+	}
+	want := "warning: no schemas directory; " +
+		"notes are written without validation\n"
+	if got := warnings.String(); got != want {
+		t.Errorf("warnings = %q, want %q", got, want)
+	}
+}
+
+func TestNoteValidator_Nil(t *testing.T) {
+	if newNoteValidator(nil, nil) != nil {
+		t.Fatal("newNoteValidator(nil) should disable validation")
+	}
+	var validator *noteValidator
+	err := validator.Validate(t.Context(), "---\nfileClass: x\n---\n")
+	if err != nil {
+		t.Errorf("nil validator should accept every note, got %v", err)
+	}
+}
+
+func TestCobraCLIAdapter_Execute_NewCommand_Validation(t *testing.T) {
+	const template = "---\nfileClass: contact\nname: {{.name}}\n---\n"
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantWrite bool
+	}{
+		{
+			name:      "valid note is written",
+			args:      []string{"--set", "name=Ada"},
+			wantWrite: true,
+		},
+		{
+			name: "invalid note is refused",
+			args: []string{"--set", "name="},
+		},
+		{
+			name:      "no-validate writes invalid note",
+			args:      []string{"--set", "name=", "--no-validate"},
+			wantWrite: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := newMockFileSystemPort()
+			mockFS.AddFile(testTemplateFile, []byte(template))
+			adapter := NewCobraCLIAdapter(AdapterOptions{
+				TemplateEngine: createTemplateEngine(),
+				TemplateRepo: templaterepo.NewFSAdapter(
+					mockFS,
+					createTemplateParser(),
+					newMockConfigPort(),
+				),
+				FileSystemPort: mockFS,
+				ConfigPort:     newMockConfigPort(),
+				NoteWriter:     note.NewWriter(mockFS),
+				Clock:          clock.NewPinnable(nil),
+				SchemaEngine:   newContactSchemaEngine(),
+			})
+
+			args := append([]string{"new", testTemplateFile}, tt.args...)
+			exitCode := adapter.Execute(args)
+
+			_, written := mockFS.GetWrittenFiles()["template.md"]
+			if written != tt.wantWrite {
+				t.Errorf("note written = %v, want %v", written, tt.wantWrite)
+			}
+			if (exitCode == 0) != tt.wantWrite {
+				t.Errorf("Execute() exit code = %d", exitCode)
+			}
+		})
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/JackMatanky/lithos/internal/shared/clock"
 )
 
 // Config represents application configuration loaded from lithos.yaml and
@@ -40,8 +43,39 @@ type Config struct {
 	// "warn", "error". Default: "info". Case-insensitive. Invalid values fall
 	// back to "info" with warning.
 	LogLevel string `yaml:"logLevel" json:"logLevel"`
+
+	// Timezone is the IANA time zone name (e.g. "Europe/Berlin") that template
+	// date functions work in. Default: "" (the system's local time zone).
+	Timezone string `yaml:"timezone" json:"timezone"`
+
+	// Now pins the render time, e.g. "2025-03-14" or "2025-03-14T09:30:00Z",
+	// so template date functions and timestamp suffixes are reproducible.
+	// Usually set through LITHOS_NOW or the --now flag rather than the file.
+	// Default: "" (the system time).
+	Now string `yaml:"now" json:"now"`
+
+	// RenderTimeout bounds how long rendering a single template may take, as
+	// a Go duration such as "30s" or "2m". "0" disables the limit. Default:
+	// "30s".
+	RenderTimeout string `yaml:"renderTimeout" json:"renderTimeout"`
+
+	// MaxOutputSize bounds the size of a single rendered template in bytes.
+	// 0 disables the limit. Default: 10485760 (10 MiB).
+	MaxOutputSize int64 `yaml:"maxOutputSize" json:"maxOutputSize"`
+
+	// StampProvenance adds the template ID, template hash, and lithos version
+	// to the frontmatter of every note `lithos new` writes, as the --stamp
+	// flag does for a single run. Default: false.
+	StampProvenance bool `yaml:"stampProvenance" json:"stampProvenance"`
 }
 
+// Render limit defaults, generous enough for any real note while still
+// stopping runaway templates.
+const (
+	DefaultRenderTimeout = "30s"
+	DefaultMaxOutputSize = 10 << 20
+)
+
 // NewConfig creates a new Config with sensible defaults based on the vault
 // path.
 // VaultPath defaults to current working directory if empty.
@@ -64,11 +98,13 @@ func NewConfig(vaultPath string) *Config {
 	}
 
 	return &Config{
-		VaultPath:    absVaultPath,
-		TemplatesDir: filepath.Join(absVaultPath, "templates"),
-		SchemasDir:   filepath.Join(absVaultPath, "schemas"),
-		CacheDir:     filepath.Join(absVaultPath, ".lithos", "cache"),
-		LogLevel:     "info",
+		VaultPath:     absVaultPath,
+		TemplatesDir:  filepath.Join(absVaultPath, "templates"),
+		SchemasDir:    filepath.Join(absVaultPath, "schemas"),
+		CacheDir:      filepath.Join(absVaultPath, ".lithos", "cache"),
+		LogLevel:      "info",
+		RenderTimeout: DefaultRenderTimeout,
+		MaxOutputSize: DefaultMaxOutputSize,
 	}
 }
 
@@ -86,6 +122,27 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("log level validation failed: %w", err)
 	}
 
+	// Validate Timezone names a known location
+	if _, err := c.Location(); err != nil {
+		return fmt.Errorf("timezone validation failed: %w", err)
+	}
+
+	// Validate Now is a parseable time
+	if _, _, err := c.PinnedTime(); err != nil {
+		return fmt.Errorf("now validation failed: %w", err)
+	}
+
+	// Validate the render limits
+	if _, err := c.RenderTimeoutDuration(); err != nil {
+		return fmt.Errorf("render timeout validation failed: %w", err)
+	}
+	if c.MaxOutputSize < 0 {
+		return fmt.Errorf(
+			"max output size validation failed: %d is negative",
+			c.MaxOutputSize,
+		)
+	}
+
 	return nil
 }
 
@@ -97,6 +154,60 @@ func (c *Config) ResolvePath(path string) string {
 	return filepath.Join(c.VaultPath, path)
 }
 
+// Location returns the time zone named by Timezone, or time.Local when
+// Timezone is empty.
+func (c *Config) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.Local, nil
+	}
+
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", c.Timezone, err)
+	}
+	return loc, nil
+}
+
+// PinnedTime returns the time named by Now, interpreted in Location when it
+// carries no offset. ok is false when Now is empty and time is not pinned.
+func (c *Config) PinnedTime() (t time.Time, ok bool, err error) {
+	if c.Now == "" {
+		return time.Time{}, false, nil
+	}
+
+	loc, err := c.Location()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	t, err = clock.Parse(c.Now, loc)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t, true, nil
+}
+
+// RenderTimeoutDuration returns RenderTimeout as a duration. An empty or
+// zero value yields 0, meaning no limit.
+func (c *Config) RenderTimeoutDuration() (time.Duration, error) {
+	if c.RenderTimeout == "" || c.RenderTimeout == "0" {
+		return 0, nil
+	}
+
+	timeout, err := time.ParseDuration(c.RenderTimeout)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"invalid render timeout %q (expected e.g. 30s or 2m): %w",
+			c.RenderTimeout,
+			err,
+		)
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("render timeout %q is negative", c.RenderTimeout)
+	}
+	return timeout, nil
+}
+
 // validateVaultPath checks that VaultPath exists and is a readable directory.
 func (c *Config) validateVaultPath() error {
 	if c.VaultPath == "" {
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestNewConfig(t *testing.T) {
@@ -97,6 +98,55 @@ func TestConfig_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "invalid log level",
 		},
+		{
+			name: "invalid timezone",
+			config: &Config{
+				VaultPath: tempDir,
+				LogLevel:  "info",
+				Timezone:  "Mars/Olympus_Mons",
+			},
+			wantErr: true,
+			errMsg:  "invalid timezone",
+		},
+		{
+			name: "valid timezone",
+			config: &Config{
+				VaultPath: tempDir,
+				LogLevel:  "info",
+				Timezone:  "Europe/Berlin",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid now",
+			config: &Config{
+				VaultPath: tempDir,
+				LogLevel:  "info",
+				Now:       "yesterday",
+			},
+			wantErr: true,
+			errMsg:  "invalid time",
+		},
+		{
+			name: "invalid render timeout",
+			config: &Config{
+				VaultPath:     tempDir,
+				LogLevel:      "info",
+				RenderTimeout: "forever",
+			},
+			wantErr: true,
+			errMsg:  "invalid render timeout",
+		},
+		{
+			name: "negative max output size",
+			config: &Config{
+				VaultPath:     tempDir,
+				LogLevel:      "info",
+				MaxOutputSize: -1,
+			},
+			wantErr: true,
+			errMsg:  "max output size validation failed",
+		},
 		{
 			name: "log level normalization",
 			config: &Config{
@@ -392,6 +442,68 @@ func TestConfig_ResolvePath(t *testing.T) {
 	}
 }
 
+func TestConfig_Location(t *testing.T) {
+	loc, err := (&Config{}).Location()
+	if err != nil || loc != time.Local {
+		t.Errorf("Location() = %v, %v; want time.Local", loc, err)
+	}
+
+	loc, err = (&Config{Timezone: "Asia/Tokyo"}).Location()
+	if err != nil || loc.String() != "Asia/Tokyo" {
+		t.Errorf("Location() = %v, %v; want Asia/Tokyo", loc, err)
+	}
+
+	if _, err = (&Config{Timezone: "Nowhere"}).Location(); err == nil {
+		t.Error("Location() expected error for unknown timezone")
+	}
+}
+
+func TestConfig_PinnedTime(t *testing.T) {
+	if _, ok, err := (&Config{}).PinnedTime(); ok || err != nil {
+		t.Errorf("PinnedTime() = ok %v, err %v; want unpinned", ok, err)
+	}
+
+	got, ok, err := (&Config{
+		Timezone: "Asia/Tokyo",
+		Now:      "2025-03-14 09:30",
+	}).PinnedTime()
+	if err != nil || !ok {
+		t.Fatalf("PinnedTime() = ok %v, err %v; want pinned", ok, err)
+	}
+	if got.Format(time.RFC3339) != "2025-03-14T09:30:00+09:00" {
+		t.Errorf("PinnedTime() = %v, want 09:30 in Asia/Tokyo", got)
+	}
+}
+
+func TestConfig_RenderTimeoutDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "", want: 0},
+		{value: "0", want: 0},
+		{value: "30s", want: 30 * time.Second},
+		{value: "1m30s", want: 90 * time.Second},
+		{value: "soon", wantErr: true},
+		{value: "-5s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := (&Config{RenderTimeout: tt.value}).RenderTimeoutDuration()
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf(
+				"RenderTimeoutDuration(%q) = %v, %v; want %v, error %v",
+				tt.value,
+				got,
+				err,
+				tt.want,
+				tt.wantErr,
+			)
+		}
+	}
+}
+
 // Helper function to check if a string contains a substring.
 func contains(s, substr string) bool {
 	return substr == "" ||
@@ -77,6 +77,11 @@ func setDefaults(v *viper.Viper) error {
 	v.SetDefault("schemasDir", filepath.Join(cwd, "schemas"))
 	v.SetDefault("cacheDir", filepath.Join(cwd, ".lithos", "cache"))
 	v.SetDefault("logLevel", "info")
+	v.SetDefault("timezone", "")
+	v.SetDefault("now", "")
+	v.SetDefault("renderTimeout", DefaultRenderTimeout)
+	v.SetDefault("maxOutputSize", DefaultMaxOutputSize)
+	v.SetDefault("stampProvenance", false)
 
 	return nil
 }
@@ -89,11 +94,16 @@ func buildConfigFromViper(v *viper.Viper) (*Config, error) {
 	}
 
 	config := &Config{
-		VaultPath:    vaultPath,
-		LogLevel:     v.GetString("logLevel"),
-		TemplatesDir: "",
-		SchemasDir:   "",
-		CacheDir:     "",
+		VaultPath:       vaultPath,
+		LogLevel:        v.GetString("logLevel"),
+		Timezone:        v.GetString("timezone"),
+		Now:             v.GetString("now"),
+		RenderTimeout:   v.GetString("renderTimeout"),
+		MaxOutputSize:   v.GetInt64("maxOutputSize"),
+		StampProvenance: v.GetBool("stampProvenance"),
+		TemplatesDir:    "",
+		SchemasDir:      "",
+		CacheDir:        "",
 	}
 
 	config.TemplatesDir = resolvePath(v.GetString("templatesDir"), vaultPath)
@@ -130,6 +140,11 @@ func bindEnvironmentVariables(v *viper.Viper) error {
 		"schemasDir",
 		"cacheDir",
 		"logLevel",
+		"timezone",
+		"now",
+		"renderTimeout",
+		"maxOutputSize",
+		"stampProvenance",
 	}
 
 	for _, envVar := range envVars {
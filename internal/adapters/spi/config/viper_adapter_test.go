@@ -246,6 +246,15 @@ func TestSetDefaults(t *testing.T) {
 		)
 	}
 
+	if v.GetString("renderTimeout") != DefaultRenderTimeout ||
+		v.GetInt64("maxOutputSize") != DefaultMaxOutputSize {
+		t.Errorf(
+			"render limit defaults = %q, %d",
+			v.GetString("renderTimeout"),
+			v.GetInt64("maxOutputSize"),
+		)
+	}
+
 	// Verify that VaultPath is absolute
 	vaultPath := v.GetString("vaultPath")
 	if !filepath.IsAbs(vaultPath) {
@@ -445,6 +454,7 @@ func TestConfigViperAdapter_WithEnvironmentVariables(t *testing.T) {
 	// Set environment variables (viper converts camelCase keys to UPPERCASE)
 	_ = os.Setenv("LITHOS_VAULTPATH", tempDir)
 	_ = os.Setenv("LITHOS_LOGLEVEL", testLogLevelDebug)
+	t.Setenv("LITHOS_NOW", "2025-03-14")
 
 	// Change to a different directory to ensure env vars are used
 	originalDir, err := os.Getwd()
@@ -481,4 +491,8 @@ func TestConfigViperAdapter_WithEnvironmentVariables(t *testing.T) {
 			testLogLevelDebug,
 		)
 	}
+	if config.Now != "2025-03-14" {
+		t.Errorf("Now = %q, want %q (from LITHOS_NOW)", config.Now,
+			"2025-03-14")
+	}
 }
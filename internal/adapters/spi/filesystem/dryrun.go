@@ -0,0 +1,185 @@
+// Package filesystem provides a local file system adapter implementing
+// FileSystemPort.
+//
+// This file contains the dry-run adapter, which records writes in an
+// in-memory overlay instead of performing them.
+package filesystem
+
+import (
+	stderrors "errors"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+	"github.com/JackMatanky/lithos/internal/shared/errors"
+)
+
+// DryRunAdapter wraps a FileSystemPort and, once dry-run mode is enabled,
+// records writes in an overlay instead of passing them on. Reads, Stat, and
+// Walk see the overlay on top of the wrapped port, so a command behaves as it
+// would for real; collision checks, for example, see notes written earlier
+// in the same run. Until dry-run mode is enabled every call is passed through.
+type DryRunAdapter struct {
+	base    spi.FileSystemPort
+	mu      sync.RWMutex
+	enabled bool
+	order   []string
+	pending map[string]spi.PendingWrite
+}
+
+// NewDryRunAdapter creates a DryRunAdapter wrapping base, with dry-run mode
+// disabled.
+func NewDryRunAdapter(base spi.FileSystemPort) *DryRunAdapter {
+	return &DryRunAdapter{
+		base:    base,
+		pending: make(map[string]spi.PendingWrite),
+	}
+}
+
+// EnableDryRun implements spi.DryRunFileSystemPort.EnableDryRun.
+func (a *DryRunAdapter) EnableDryRun() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.enabled = true
+}
+
+// DryRunEnabled implements spi.DryRunFileSystemPort.DryRunEnabled.
+func (a *DryRunAdapter) DryRunEnabled() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.enabled
+}
+
+// PendingWrites implements spi.DryRunFileSystemPort.PendingWrites.
+func (a *DryRunAdapter) PendingWrites() []spi.PendingWrite {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	writes := make([]spi.PendingWrite, 0, len(a.order))
+	for _, path := range a.order {
+		writes = append(writes, a.pending[path])
+	}
+	return writes
+}
+
+// ReadFile returns the recorded content for path, or reads it from the
+// wrapped port.
+func (a *DryRunAdapter) ReadFile(path string) ([]byte, error) {
+	if write, ok := a.pendingWrite(path); ok {
+		return append([]byte(nil), write.After...), nil
+	}
+	return a.base.ReadFile(path)
+}
+
+// WriteFileAtomic records the write in dry-run mode and passes it on
+// otherwise.
+func (a *DryRunAdapter) WriteFileAtomic(path string, data []byte) error {
+	if !a.DryRunEnabled() {
+		return a.base.WriteFileAtomic(path, data)
+	}
+
+	key := filepath.Clean(path)
+	a.mu.Lock()
+	write, recorded := a.pending[key]
+	a.mu.Unlock()
+
+	if !recorded {
+		before, existed, err := a.currentContent(path)
+		if err != nil {
+			return err
+		}
+		write = spi.PendingWrite{Path: path, Existed: existed, Before: before}
+	}
+	write.After = append([]byte(nil), data...)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.pending[key]; !ok {
+		a.order = append(a.order, key)
+	}
+	a.pending[key] = write
+	return nil
+}
+
+// Walk walks the wrapped port and then visits recorded files under root that
+// do not exist there yet.
+func (a *DryRunAdapter) Walk(root string, fn spi.WalkFunc) error {
+	if err := a.base.Walk(root, fn); err != nil {
+		return err
+	}
+
+	for _, write := range a.newFilesUnder(root) {
+		if err := fn(write.Path, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stat reports recorded files as regular files of their recorded size and
+// passes other paths on. ModTime is zero for files that do not exist yet.
+func (a *DryRunAdapter) Stat(path string) (spi.FileInfo, error) {
+	info, err := a.base.Stat(path)
+	write, recorded := a.pendingWrite(path)
+	if !recorded {
+		return info, err
+	}
+
+	if err != nil && !stderrors.Is(err, fs.ErrNotExist) {
+		return info, err
+	}
+	info.Path = path
+	info.IsDir = false
+	info.Size = int64(len(write.After))
+	return info, nil
+}
+
+// pendingWrite returns the write recorded for path, if any.
+func (a *DryRunAdapter) pendingWrite(path string) (spi.PendingWrite, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	write, ok := a.pending[filepath.Clean(path)]
+	return write, ok
+}
+
+// currentContent reads the content path holds in the wrapped port and
+// reports whether it exists.
+func (a *DryRunAdapter) currentContent(path string) ([]byte, bool, error) {
+	if _, err := a.base.Stat(path); err != nil {
+		if stderrors.Is(err, fs.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	content, err := a.base.ReadFile(path)
+	if err != nil {
+		return nil, false, errors.NewResourceError("file", "read", path, err)
+	}
+	return content, true, nil
+}
+
+// newFilesUnder returns the recorded writes below root that create new files,
+// sorted by path.
+func (a *DryRunAdapter) newFilesUnder(root string) []spi.PendingWrite {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	prefix := filepath.Clean(root) + string(filepath.Separator)
+	var writes []spi.PendingWrite
+	for key, write := range a.pending {
+		if !write.Existed && strings.HasPrefix(key, prefix) {
+			writes = append(writes, write)
+		}
+	}
+	sort.Slice(writes, func(i, j int) bool {
+		return writes[i].Path < writes[j].Path
+	})
+	return writes
+}
+
+// Ensure DryRunAdapter implements spi.DryRunFileSystemPort.
+var _ spi.DryRunFileSystemPort = (*DryRunAdapter)(nil)
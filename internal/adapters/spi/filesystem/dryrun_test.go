@@ -0,0 +1,94 @@
+package filesystem
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDryRunAdapter_PassesThroughUntilEnabled(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "note.md")
+	adapter := NewDryRunAdapter(NewLocalFileSystemAdapter())
+
+	if err := adapter.WriteFileAtomic(path, []byte("real")); err != nil {
+		t.Fatalf("WriteFileAtomic() error = %v", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "real" {
+		t.Fatalf("file content = %q, want the real write", content)
+	}
+	if len(adapter.PendingWrites()) != 0 {
+		t.Errorf("writes before EnableDryRun should not be recorded")
+	}
+}
+
+func TestDryRunAdapter_RecordsWrites(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.md")
+	created := filepath.Join(dir, "notes", "new.md")
+	if err := os.WriteFile(existing, []byte("before"), 0o600); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	adapter := NewDryRunAdapter(NewLocalFileSystemAdapter())
+	adapter.EnableDryRun()
+
+	for _, write := range []struct{ path, content string }{
+		{created, "first"},
+		{existing, "after"},
+		{created, "second"},
+	} {
+		if err := adapter.WriteFileAtomic(
+			write.path,
+			[]byte(write.content),
+		); err != nil {
+			t.Fatalf("WriteFileAtomic(%s) error = %v", write.path, err)
+		}
+	}
+
+	// Nothing reaches the disk
+	if content, _ := os.ReadFile(existing); string(content) != "before" {
+		t.Errorf("existing file changed to %q", content)
+	}
+	if _, err := os.Stat(created); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("new file was created on disk: %v", err)
+	}
+
+	// The overlay is visible to later reads
+	if content, err := adapter.ReadFile(created); err != nil ||
+		string(content) != "second" {
+		t.Errorf("ReadFile() = %q, %v; want the recorded write", content, err)
+	}
+	info, err := adapter.Stat(created)
+	if err != nil || info.IsDir || info.Size != int64(len("second")) {
+		t.Errorf("Stat() = %+v, %v; want the recorded file", info, err)
+	}
+	var walked []string
+	if err := adapter.Walk(dir, func(path string, isDir bool) error {
+		if !isDir {
+			walked = append(walked, path)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+	if len(walked) != 2 || walked[1] != created {
+		t.Errorf("Walk() visited %v, want existing and recorded files", walked)
+	}
+
+	writes := adapter.PendingWrites()
+	if len(writes) != 2 {
+		t.Fatalf("PendingWrites() = %+v, want 2 writes", writes)
+	}
+	if writes[0].Path != created || writes[0].Existed ||
+		string(writes[0].After) != "second" {
+		t.Errorf("writes[0] = %+v, want the new file's last content", writes[0])
+	}
+	if writes[1].Path != existing || !writes[1].Existed ||
+		string(writes[1].Before) != "before" ||
+		string(writes[1].After) != "after" {
+		t.Errorf("writes[1] = %+v, want the replaced file", writes[1])
+	}
+}
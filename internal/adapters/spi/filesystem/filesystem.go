@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 
 	"github.com/JackMatanky/lithos/internal/ports/spi"
+	"github.com/JackMatanky/lithos/internal/shared/errors"
 )
 
 // LocalFileSystemAdapter implements the FileSystemPort interface using the
@@ -84,6 +85,23 @@ func (a *LocalFileSystemAdapter) Walk(root string, fn spi.WalkFunc) error {
 	)
 }
 
+// Stat returns metadata for the file or directory at path.
+// Errors are returned as ResourceError values that wrap the underlying
+// os error, so errors.Is(err, fs.ErrNotExist) reports missing paths.
+func (a *LocalFileSystemAdapter) Stat(path string) (spi.FileInfo, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return spi.FileInfo{}, errors.NewResourceError("file", "stat", path, err)
+	}
+
+	return spi.FileInfo{
+		Path:    path,
+		IsDir:   info.IsDir(),
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}, nil
+}
+
 // ensureDirectory creates the directory structure if it doesn't exist.
 func (a *LocalFileSystemAdapter) ensureDirectory(dir string) error {
 	return os.MkdirAll(dir, 0o750)
@@ -306,3 +306,37 @@ func TestLocalFileSystemAdapter_Walk_NonExistentDirectory(t *testing.T) {
 		t.Error("Walk() should return error for non-existent directory")
 	}
 }
+
+func TestLocalFileSystemAdapter_Stat(t *testing.T) {
+	adapter := NewLocalFileSystemAdapter()
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "stat.md")
+	if err := os.WriteFile(testFile, []byte("12345"), 0o600); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	info, err := adapter.Stat(testFile)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if info.IsDir || info.Size != 5 || info.Path != testFile {
+		t.Errorf("Stat() = %+v, want 5-byte file at %s", info, testFile)
+	}
+	if info.ModTime.IsZero() {
+		t.Error("Stat() ModTime should be set")
+	}
+
+	dirInfo, err := adapter.Stat(tempDir)
+	if err != nil {
+		t.Fatalf("Stat() directory error = %v", err)
+	}
+	if !dirInfo.IsDir {
+		t.Error("Stat() IsDir = false for directory")
+	}
+
+	_, err = adapter.Stat(filepath.Join(tempDir, "missing.md"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Stat() error = %v, want wrapping os.ErrNotExist", err)
+	}
+}
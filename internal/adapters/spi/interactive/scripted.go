@@ -0,0 +1,167 @@
+// Package interactive provides adapters implementing PromptPort.
+//
+// This file contains the scripted adapter, which answers questions from
+// answers given in advance so that prompts work in CI and in tests.
+package interactive
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+)
+
+// ScriptedAdapter implements spi.ScriptedPromptPort. Questions are answered
+// from the answers recorded for their label; questions without one are
+// passed on to the wrapped port, if there is one.
+type ScriptedAdapter struct {
+	fallback spi.PromptPort
+	mu       sync.RWMutex
+	answers  map[string]string
+}
+
+// NewScriptedAdapter creates a ScriptedAdapter without answers that passes
+// unanswered questions on to fallback. A nil fallback makes every unanswered
+// question an error.
+func NewScriptedAdapter(fallback spi.PromptPort) *ScriptedAdapter {
+	return &ScriptedAdapter{
+		fallback: fallback,
+		answers:  make(map[string]string),
+	}
+}
+
+// AddAnswers implements spi.ScriptedPromptPort.AddAnswers.
+func (a *ScriptedAdapter) AddAnswers(answers map[string]string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for label, answer := range answers {
+		a.answers[label] = answer
+	}
+}
+
+// Prompt implements spi.PromptPort.Prompt. A scripted answer that fails
+// cfg.Validate is an error.
+func (a *ScriptedAdapter) Prompt(
+	ctx context.Context,
+	cfg spi.PromptConfig,
+) (string, error) {
+	if answer, ok := a.answer(cfg.Label); ok {
+		if cfg.Validate != nil {
+			if err := cfg.Validate(answer); err != nil {
+				return "", fmt.Errorf(
+					"answer %q for %q is invalid: %w",
+					answer,
+					cfg.Label,
+					err,
+				)
+			}
+		}
+		return answer, nil
+	}
+	if a.fallback == nil {
+		return "", a.unanswered(cfg.Label, nil)
+	}
+	answer, err := a.fallback.Prompt(ctx, cfg)
+	if err != nil {
+		return "", a.unanswered(cfg.Label, err)
+	}
+	return answer, nil
+}
+
+// Suggester implements spi.PromptPort.Suggester. A scripted answer may be
+// the label or the value of an option.
+func (a *ScriptedAdapter) Suggester(
+	ctx context.Context,
+	cfg spi.SuggesterConfig,
+) (string, error) {
+	if answer, ok := a.answer(cfg.Label); ok {
+		for _, option := range cfg.Options {
+			if answer == option.Label || answer == option.Value {
+				return option.Value, nil
+			}
+		}
+		return "", fmt.Errorf(
+			"answer %q for %q is not one of the options: %s",
+			answer,
+			cfg.Label,
+			optionLabels(cfg.Options),
+		)
+	}
+	if a.fallback == nil {
+		return "", a.unanswered(cfg.Label, nil)
+	}
+	answer, err := a.fallback.Suggester(ctx, cfg)
+	if err != nil {
+		return "", a.unanswered(cfg.Label, err)
+	}
+	return answer, nil
+}
+
+// Confirm implements spi.PromptPort.Confirm. A scripted answer is y, yes, or
+// true, or n, no, or false.
+func (a *ScriptedAdapter) Confirm(
+	ctx context.Context,
+	cfg spi.ConfirmConfig,
+) (bool, error) {
+	if answer, ok := a.answer(cfg.Label); ok {
+		yes, valid := parseYesNo(answer)
+		if !valid {
+			return false, fmt.Errorf(
+				"answer %q for %q is not yes or no",
+				answer,
+				cfg.Label,
+			)
+		}
+		return yes, nil
+	}
+	if a.fallback == nil {
+		return false, a.unanswered(cfg.Label, nil)
+	}
+	yes, err := a.fallback.Confirm(ctx, cfg)
+	if err != nil {
+		return false, a.unanswered(cfg.Label, err)
+	}
+	return yes, nil
+}
+
+// answer returns the scripted answer for label.
+func (a *ScriptedAdapter) answer(label string) (string, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	answer, ok := a.answers[label]
+	return answer, ok
+}
+
+// unanswered describes a question without a scripted answer that could not
+// be asked either, explaining how to script it when nobody could be asked.
+func (a *ScriptedAdapter) unanswered(label string, err error) error {
+	hint := fmt.Sprintf(
+		"answer it with --answer %q or an --answers file",
+		label+"=...",
+	)
+	switch {
+	case err == nil:
+		return fmt.Errorf("no answer was given for %q; %s", label, hint)
+	case stderrors.Is(err, ErrNotInteractive):
+		return fmt.Errorf(
+			"no answer was given for %q and %w; %s",
+			label,
+			err,
+			hint,
+		)
+	default:
+		return err
+	}
+}
+
+// optionLabels lists the labels of options, in order, for error messages.
+func optionLabels(options []spi.SuggesterOption) string {
+	labels := make([]string, len(options))
+	for i, option := range options {
+		labels[i] = option.Label
+	}
+	return strings.Join(labels, ", ")
+}
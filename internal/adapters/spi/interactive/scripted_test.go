@@ -0,0 +1,196 @@
+package interactive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+)
+
+func TestScriptedAdapter_Prompt(t *testing.T) {
+	tests := []struct {
+		name     string
+		answers  map[string]string
+		fallback spi.PromptPort
+		want     string
+		wantErr  string
+	}{
+		{
+			name:    "scripted answer",
+			answers: map[string]string{"Title": "Weekly Sync"},
+			want:    "Weekly Sync",
+		},
+		{
+			name:    "empty scripted answer is kept",
+			answers: map[string]string{"Title": ""},
+			want:    "",
+		},
+		{
+			name: "falls back to the terminal",
+			fallback: newTTYAdapter(
+				strings.NewReader("Typed\n"),
+				&bytes.Buffer{},
+				true,
+			),
+			want: "Typed",
+		},
+		{
+			name: "no terminal",
+			fallback: newTTYAdapter(
+				strings.NewReader(""),
+				&bytes.Buffer{},
+				false,
+			),
+			wantErr: `no answer was given for "Title" and input is not a ` +
+				`terminal; answer it with --answer "Title=..." or an ` +
+				"--answers file",
+		},
+		{
+			name: "no fallback",
+			wantErr: `no answer was given for "Title"; answer it with ` +
+				`--answer "Title=..." or an --answers file`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := NewScriptedAdapter(tt.fallback)
+			adapter.AddAnswers(tt.answers)
+
+			got, err := adapter.Prompt(
+				context.Background(),
+				spi.PromptConfig{Label: "Title", Default: "Untitled"},
+			)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Prompt() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Prompt() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Prompt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScriptedAdapter_Prompt_Validate(t *testing.T) {
+	adapter := NewScriptedAdapter(nil)
+	adapter.AddAnswers(map[string]string{"Estimate": "three"})
+
+	_, err := adapter.Prompt(context.Background(), spi.PromptConfig{
+		Label: "Estimate",
+		Validate: func(string) error {
+			return errors.New("must be a number")
+		},
+	})
+	want := `answer "three" for "Estimate" is invalid: must be a number`
+	if err == nil || err.Error() != want {
+		t.Errorf("Prompt() error = %v, want %q", err, want)
+	}
+}
+
+func TestScriptedAdapter_Suggester(t *testing.T) {
+	tests := []struct {
+		name    string
+		answer  string
+		want    string
+		wantErr string
+	}{
+		{name: "by label", answer: "Done", want: "done"},
+		{name: "by value", answer: "open", want: "open"},
+		{
+			name:   "not an option",
+			answer: "Later",
+			wantErr: `answer "Later" for "Status" is not one of the ` +
+				"options: Open, Done",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := NewScriptedAdapter(nil)
+			adapter.AddAnswers(map[string]string{"Status": tt.answer})
+
+			got, err := adapter.Suggester(
+				context.Background(),
+				spi.SuggesterConfig{Label: "Status", Options: statusOptions},
+			)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Suggester() error = %v, want %q",
+						err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Suggester() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Suggester() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScriptedAdapter_Confirm(t *testing.T) {
+	tests := []struct {
+		name    string
+		answer  string
+		want    bool
+		wantErr string
+	}{
+		{name: "yes", answer: "yes", want: true},
+		{name: "true", answer: "true", want: true},
+		{name: "no", answer: "N", want: false},
+		{
+			name:    "not yes or no",
+			answer:  "maybe",
+			wantErr: `answer "maybe" for "Urgent" is not yes or no`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := NewScriptedAdapter(nil)
+			adapter.AddAnswers(map[string]string{"Urgent": tt.answer})
+
+			got, err := adapter.Confirm(
+				context.Background(),
+				spi.ConfirmConfig{Label: "Urgent", Default: true},
+			)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Confirm() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Confirm() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Confirm() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScriptedAdapter_UnansweredWrapsNotInteractive(t *testing.T) {
+	adapter := NewScriptedAdapter(
+		newTTYAdapter(strings.NewReader(""), &bytes.Buffer{}, false),
+	)
+
+	_, err := adapter.Suggester(
+		context.Background(),
+		spi.SuggesterConfig{Label: "Status", Options: statusOptions},
+	)
+	if !errors.Is(err, ErrNotInteractive) {
+		t.Errorf("Suggester() error = %v, want ErrNotInteractive", err)
+	}
+}
@@ -0,0 +1,199 @@
+// Package interactive provides adapters implementing PromptPort.
+//
+// This file contains the terminal adapter, which asks questions on a
+// terminal and reads the answers typed in reply.
+package interactive
+
+import (
+	"bufio"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+	"golang.org/x/term"
+)
+
+// ErrNotInteractive is returned when a question needs asking but the input
+// is not a terminal, so nobody could answer it.
+var ErrNotInteractive = stderrors.New("input is not a terminal")
+
+// TTYAdapter implements spi.PromptPort by asking on a terminal. Questions
+// are written to out and answers are read a line at a time from in.
+type TTYAdapter struct {
+	in          *bufio.Reader
+	out         io.Writer
+	interactive bool
+}
+
+// NewTTYAdapter creates a TTYAdapter reading answers from in and writing
+// questions to out. When in is not a terminal, every question fails with
+// ErrNotInteractive instead of waiting for input that never comes.
+func NewTTYAdapter(in *os.File, out io.Writer) *TTYAdapter {
+	return newTTYAdapter(in, out, term.IsTerminal(int(in.Fd())))
+}
+
+// newTTYAdapter creates a TTYAdapter over any reader, which is treated as a
+// terminal when interactive is true.
+func newTTYAdapter(in io.Reader, out io.Writer, interactive bool) *TTYAdapter {
+	return &TTYAdapter{
+		in:          bufio.NewReader(in),
+		out:         out,
+		interactive: interactive,
+	}
+}
+
+// Prompt implements spi.PromptPort.Prompt. An empty answer takes the default,
+// and an answer that fails cfg.Validate is asked again.
+func (a *TTYAdapter) Prompt(
+	ctx context.Context,
+	cfg spi.PromptConfig,
+) (string, error) {
+	if err := a.ready(ctx); err != nil {
+		return "", err
+	}
+
+	question := cfg.Label
+	if cfg.Help != "" {
+		question += " (" + cfg.Help + ")"
+	}
+	if cfg.Default != "" {
+		question += " [" + cfg.Default + "]"
+	}
+	for {
+		fmt.Fprintf(a.out, "%s: ", question)
+		answer, err := a.readLine()
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			answer = cfg.Default
+		}
+		if cfg.Validate == nil {
+			return answer, nil
+		}
+		err = cfg.Validate(answer)
+		if err == nil {
+			return answer, nil
+		}
+		fmt.Fprintf(a.out, "%v\n", err)
+	}
+}
+
+// Suggester implements spi.PromptPort.Suggester. The options are listed with
+// numbers; the user answers with a number or with the label of an option,
+// and is asked again until the answer matches one.
+func (a *TTYAdapter) Suggester(
+	ctx context.Context,
+	cfg spi.SuggesterConfig,
+) (string, error) {
+	if err := a.ready(ctx); err != nil {
+		return "", err
+	}
+
+	fmt.Fprintf(a.out, "%s\n", cfg.Label)
+	for i, option := range cfg.Options {
+		fmt.Fprintf(a.out, "  %d) %s\n", i+1, option.Label)
+	}
+	for {
+		fmt.Fprintf(a.out, "Choose 1-%d: ", len(cfg.Options))
+		answer, err := a.readLine()
+		if err != nil {
+			return "", err
+		}
+		if option, ok := chooseOption(cfg.Options, answer); ok {
+			return option.Value, nil
+		}
+		fmt.Fprintf(a.out, "%q is not one of the options\n", answer)
+	}
+}
+
+// Confirm implements spi.PromptPort.Confirm. The user answers y or n, or
+// nothing for the default, and is asked again until the answer is one of
+// them.
+func (a *TTYAdapter) Confirm(
+	ctx context.Context,
+	cfg spi.ConfirmConfig,
+) (bool, error) {
+	if err := a.ready(ctx); err != nil {
+		return false, err
+	}
+
+	choices := "y/N"
+	if cfg.Default {
+		choices = "Y/n"
+	}
+	for {
+		fmt.Fprintf(a.out, "%s [%s]: ", cfg.Label, choices)
+		answer, err := a.readLine()
+		if err != nil {
+			return false, err
+		}
+		if answer == "" {
+			return cfg.Default, nil
+		}
+		if yes, ok := parseYesNo(answer); ok {
+			return yes, nil
+		}
+		fmt.Fprintf(a.out, "%q is not yes or no\n", answer)
+	}
+}
+
+// ready fails when ctx is done or when there is nobody to ask.
+func (a *TTYAdapter) ready(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if !a.interactive {
+		return ErrNotInteractive
+	}
+	return nil
+}
+
+// readLine reads one answer without its line ending. Input that ends before
+// a full line is still an answer; input that ends before anything is typed
+// is an error.
+func (a *TTYAdapter) readLine() (string, error) {
+	line, err := a.in.ReadString('\n')
+	if err != nil && (!stderrors.Is(err, io.EOF) || line == "") {
+		return "", fmt.Errorf("failed to read answer: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// parseYesNo reads a yes/no answer: y, yes, or true, and n, no, or false, in
+// any case.
+func parseYesNo(answer string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes", "true":
+		return true, true
+	case "n", "no", "false":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// chooseOption returns the option answer selects: by its number in the list,
+// or by its label.
+func chooseOption(
+	options []spi.SuggesterOption,
+	answer string,
+) (spi.SuggesterOption, bool) {
+	if n, err := strconv.Atoi(answer); err == nil {
+		if n >= 1 && n <= len(options) {
+			return options[n-1], true
+		}
+		return spi.SuggesterOption{}, false
+	}
+	for _, option := range options {
+		if option.Label == answer {
+			return option, true
+		}
+	}
+	return spi.SuggesterOption{}, false
+}
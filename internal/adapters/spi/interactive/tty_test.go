@@ -0,0 +1,244 @@
+package interactive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+)
+
+var statusOptions = []spi.SuggesterOption{
+	{Label: "Open", Value: "open"},
+	{Label: "Done", Value: "done"},
+}
+
+func TestTTYAdapter_Prompt(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     spi.PromptConfig
+		input   string
+		want    string
+		wantOut string
+		wantErr string
+	}{
+		{
+			name:    "answer",
+			cfg:     spi.PromptConfig{Label: "Title"},
+			input:   "  Weekly Sync \n",
+			want:    "Weekly Sync",
+			wantOut: "Title: ",
+		},
+		{
+			name:    "empty answer takes the default",
+			cfg:     spi.PromptConfig{Label: "Status", Default: "draft"},
+			input:   "\n",
+			want:    "draft",
+			wantOut: "Status [draft]: ",
+		},
+		{
+			name:  "answer without a line ending",
+			cfg:   spi.PromptConfig{Label: "Title"},
+			input: "Sync",
+			want:  "Sync",
+		},
+		{
+			name: "asks again until the answer is valid",
+			cfg: spi.PromptConfig{
+				Label: "Estimate",
+				Help:  "a number",
+				Validate: func(answer string) error {
+					if answer != "3" {
+						return errors.New("must be a number")
+					}
+					return nil
+				},
+			},
+			input: "three\n3\n",
+			want:  "3",
+			wantOut: "Estimate (a number): must be a number\n" +
+				"Estimate (a number): ",
+		},
+		{
+			name:    "no input",
+			cfg:     spi.PromptConfig{Label: "Title"},
+			wantErr: "failed to read answer: EOF",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			adapter := newTTYAdapter(strings.NewReader(tt.input), &out, true)
+
+			got, err := adapter.Prompt(context.Background(), tt.cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Prompt() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Prompt() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Prompt() = %q, want %q", got, tt.want)
+			}
+			if tt.wantOut != "" && out.String() != tt.wantOut {
+				t.Errorf("output = %q, want %q", out.String(), tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestTTYAdapter_Suggester(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantOut string
+		wantErr string
+	}{
+		{
+			name:  "by number",
+			input: "2\n",
+			want:  "done",
+			wantOut: "Status\n  1) Open\n  2) Done\n" +
+				"Choose 1-2: ",
+		},
+		{
+			name:  "by label",
+			input: "Open\n",
+			want:  "open",
+		},
+		{
+			name:  "asks again until an option matches",
+			input: "3\nlater\n1\n",
+			want:  "open",
+			wantOut: "Status\n  1) Open\n  2) Done\n" +
+				"Choose 1-2: \"3\" is not one of the options\n" +
+				"Choose 1-2: \"later\" is not one of the options\n" +
+				"Choose 1-2: ",
+		},
+		{
+			name:    "input ends without a match",
+			input:   "later\n",
+			wantErr: "failed to read answer: EOF",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			adapter := newTTYAdapter(strings.NewReader(tt.input), &out, true)
+
+			got, err := adapter.Suggester(
+				context.Background(),
+				spi.SuggesterConfig{Label: "Status", Options: statusOptions},
+			)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Suggester() error = %v, want %q",
+						err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Suggester() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Suggester() = %q, want %q", got, tt.want)
+			}
+			if tt.wantOut != "" && out.String() != tt.wantOut {
+				t.Errorf("output = %q, want %q", out.String(), tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestTTYAdapter_Confirm(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     spi.ConfirmConfig
+		input   string
+		want    bool
+		wantOut string
+	}{
+		{
+			name:    "yes",
+			cfg:     spi.ConfirmConfig{Label: "Urgent"},
+			input:   "y\n",
+			want:    true,
+			wantOut: "Urgent [y/N]: ",
+		},
+		{
+			name:  "no",
+			cfg:   spi.ConfirmConfig{Label: "Urgent", Default: true},
+			input: "No\n",
+			want:  false,
+		},
+		{
+			name:    "empty answer takes the default",
+			cfg:     spi.ConfirmConfig{Label: "Urgent", Default: true},
+			input:   "\n",
+			want:    true,
+			wantOut: "Urgent [Y/n]: ",
+		},
+		{
+			name:  "asks again until the answer is yes or no",
+			cfg:   spi.ConfirmConfig{Label: "Urgent"},
+			input: "maybe\nyes\n",
+			want:  true,
+			wantOut: "Urgent [y/N]: \"maybe\" is not yes or no\n" +
+				"Urgent [y/N]: ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			adapter := newTTYAdapter(strings.NewReader(tt.input), &out, true)
+
+			got, err := adapter.Confirm(context.Background(), tt.cfg)
+			if err != nil {
+				t.Fatalf("Confirm() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Confirm() = %v, want %v", got, tt.want)
+			}
+			if tt.wantOut != "" && out.String() != tt.wantOut {
+				t.Errorf("output = %q, want %q", out.String(), tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestTTYAdapter_NotInteractive(t *testing.T) {
+	var out bytes.Buffer
+	adapter := newTTYAdapter(strings.NewReader("answer\n"), &out, false)
+	ctx := context.Background()
+
+	if _, err := adapter.Prompt(
+		ctx,
+		spi.PromptConfig{Label: "Title"},
+	); !errors.Is(err, ErrNotInteractive) {
+		t.Errorf("Prompt() error = %v, want ErrNotInteractive", err)
+	}
+	if _, err := adapter.Suggester(
+		ctx,
+		spi.SuggesterConfig{Label: "Status", Options: statusOptions},
+	); !errors.Is(err, ErrNotInteractive) {
+		t.Errorf("Suggester() error = %v, want ErrNotInteractive", err)
+	}
+	if _, err := adapter.Confirm(
+		ctx,
+		spi.ConfirmConfig{Label: "Urgent"},
+	); !errors.Is(err, ErrNotInteractive) {
+		t.Errorf("Confirm() error = %v, want ErrNotInteractive", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("output = %q, want nothing asked", out.String())
+	}
+}
@@ -5,6 +5,8 @@
 // and internal data structures for schema and property bank processing.
 package schema
 
+import "encoding/json"
+
 // schemaDTO represents the JSON structure for schema files.
 type schemaDTO struct {
 	Name       string                 `json:"name"`
@@ -21,7 +23,23 @@ type propertyDTO struct {
 	Required bool                   `json:"required,omitempty"`
 	Array    bool                   `json:"array,omitempty"`
 	Type     string                 `json:"type,omitempty"`
-	Spec     map[string]interface{} `json:",inline"`
+	Spec     map[string]interface{} `json:"-"`
+}
+
+// UnmarshalJSON decodes the common property fields and keeps the whole
+// object in Spec, where the type-specific fields such as enum, min, and
+// format are read from. encoding/json has no inline option to do this.
+func (p *propertyDTO) UnmarshalJSON(data []byte) error {
+	type fields propertyDTO
+	var decoded fields
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &decoded.Spec); err != nil {
+		return err
+	}
+	*p = propertyDTO(decoded)
+	return nil
 }
 
 // propertyRefDTO represents the JSON structure for $ref-only property
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/JackMatanky/lithos/internal/domain"
 	sharederrors "github.com/JackMatanky/lithos/internal/shared/errors"
 )
 
@@ -74,6 +76,46 @@ func TestLoadSchemas_Success(t *testing.T) {
 	}
 }
 
+func TestLoadSchemas_TypeSpecificFields(t *testing.T) {
+	adapter, fs, cfg := createTestAdapter()
+	setupSchemaFile(fs, cfg, "task.json", `{
+		"name": "task",
+		"properties": {
+			"status": {"type": "string", "enum": ["open", "done"]},
+			"estimate": {"type": "number", "min": 1, "max": 8, "step": 0.5},
+			"due": {"type": "date", "format": "2006-01-02"},
+			"project": {"type": "file", "fileClass": "project"}
+		}
+	}`)
+
+	schemas, err := adapter.LoadSchemas(context.Background())
+	if err != nil {
+		t.Fatalf("LoadSchemas failed: %v", err)
+	}
+	if len(schemas) != 1 {
+		t.Fatalf("Expected 1 schema, got %d", len(schemas))
+	}
+
+	specs := make(map[string]domain.PropertySpec)
+	for _, property := range schemas[0].Properties {
+		specs[property.Name] = property.Spec
+	}
+	step, minValue, maxValue := 0.5, 1.0, 8.0
+	want := map[string]domain.PropertySpec{
+		"status": domain.StringPropertySpec{Enum: []string{"open", "done"}},
+		"estimate": domain.NumberPropertySpec{
+			Min:  &minValue,
+			Max:  &maxValue,
+			Step: &step,
+		},
+		"due":     domain.DatePropertySpec{Format: "2006-01-02"},
+		"project": domain.FilePropertySpec{FileClass: "project"},
+	}
+	if !reflect.DeepEqual(specs, want) {
+		t.Errorf("specs = %#v, want %#v", specs, want)
+	}
+}
+
 func TestLoadSchemas_FileSystemError(t *testing.T) {
 	adapter, fs, _ := createTestAdapter()
 
@@ -161,7 +161,7 @@ func (s *SchemaRegistryAdapter) refreshRegistry(
 func (s *SchemaRegistryAdapter) logInitialization() {
 	cfg := s.config.Config()
 	entry := newRegistryLogger()
-	entry.Info().
+	entry.Debug().
 		Str("operation", "initialize").
 		Str("schemas_dir", cfg.SchemasDir).
 		Msg("initializing schema registry")
@@ -169,7 +169,7 @@ func (s *SchemaRegistryAdapter) logInitialization() {
 
 func (s *SchemaRegistryAdapter) logCompletion(count int) {
 	entry := newRegistryLogger()
-	entry.Info().
+	entry.Debug().
 		Str("operation", "initialize").
 		Int("schema_count", count).
 		Msg("schema registry initialized successfully")
@@ -178,3 +178,6 @@ func (s *SchemaRegistryAdapter) logCompletion(count int) {
 func newRegistryLogger() logger.Logger {
 	return logger.WithComponent("spi.schema.registry")
 }
+
+// Ensure SchemaRegistryAdapter implements spi.SchemaRegistryLoaderPort.
+var _ spi.SchemaRegistryLoaderPort = (*SchemaRegistryAdapter)(nil)
@@ -0,0 +1,101 @@
+// Package template provides SPI adapter implementations for template
+// operations.
+//
+// This file contains the in-process cache of parsed templates used by
+// FSAdapter.GetByPath.
+package template
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/JackMatanky/lithos/internal/domain"
+	"github.com/JackMatanky/lithos/internal/shared/logger"
+)
+
+// cacheEntry is a parsed template together with the content hash and
+// modification time it was parsed from. Parsed already has any layout
+// applied.
+type cacheEntry struct {
+	hash    string
+	modTime time.Time
+	parsed  *template.Template
+	header  domain.TemplateHeader
+}
+
+// parseCache holds one parsed template per path. An entry is only reused
+// while both the content hash and the modification time of the file match.
+type parseCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	hits    int
+	misses  int
+}
+
+// newParseCache creates an empty parseCache.
+func newParseCache() *parseCache {
+	return &parseCache{entries: make(map[string]cacheEntry)}
+}
+
+// get returns the entry for path if it was parsed from content with the given
+// hash and modification time, and records the lookup as a hit or a miss.
+func (c *parseCache) get(
+	path, hash string,
+	modTime time.Time,
+) (cacheEntry, bool) {
+	c.mu.Lock()
+	entry, ok := c.entries[path]
+	ok = ok && entry.hash == hash && entry.modTime.Equal(modTime)
+	if ok {
+		c.hits++
+	} else {
+		c.misses++
+	}
+	hits, misses := c.hits, c.misses
+	c.mu.Unlock()
+
+	entryLogger := newCacheLogger()
+	entryLogger.Debug().
+		Str("path", path).
+		Bool("hit", ok).
+		Int("hits", hits).
+		Int("misses", misses).
+		Msg("template cache lookup")
+	return entry, ok
+}
+
+// put stores entry for path, replacing any earlier entry.
+func (c *parseCache) put(path string, entry cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[path] = entry
+}
+
+// stats returns the number of hits and misses recorded so far.
+func (c *parseCache) stats() (hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// contentHash hashes a template's content together with the name and content
+// of every partial, so that editing a partial invalidates templates parsed
+// with it.
+func contentHash(content []byte, partials []domain.Partial) string {
+	h := sha256.New()
+	h.Write(content)
+	for _, partial := range partials {
+		h.Write([]byte{0})
+		h.Write([]byte(partial.Name))
+		h.Write([]byte{0})
+		h.Write([]byte(partial.Content))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func newCacheLogger() logger.Logger {
+	return logger.WithComponent("spi.template.cache")
+}
@@ -0,0 +1,115 @@
+package template
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	templatedomain "github.com/JackMatanky/lithos/internal/app/template"
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+	testutils "github.com/JackMatanky/lithos/tests/utils"
+)
+
+func TestFSAdapter_GetByPath_Cache(t *testing.T) {
+	modified := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		// change edits the templates directory between the two loads.
+		change     func(fs *testutils.MockFileSystemPort, dir string)
+		touch      bool
+		wantHits   int
+		wantMisses int
+		want       string
+	}{
+		{
+			name:       "unchanged template is reused",
+			wantHits:   1,
+			wantMisses: 1,
+			want:       "# Weekly\n-- footer --\n",
+		},
+		{
+			name: "changed content is parsed again",
+			change: func(fs *testutils.MockFileSystemPort, dir string) {
+				fs.AddFile(
+					filepath.Join(dir, "note.md"),
+					[]byte("## {{.title}}\n{{template \"footer\"}}"),
+				)
+			},
+			wantMisses: 2,
+			want:       "## Weekly\n-- footer --\n",
+		},
+		{
+			name: "changed partial is parsed again",
+			change: func(fs *testutils.MockFileSystemPort, dir string) {
+				fs.AddFile(
+					filepath.Join(dir, partialsDirName, "footer.md"),
+					[]byte("== footer ==\n"),
+				)
+			},
+			wantMisses: 2,
+			want:       "# Weekly\n== footer ==\n",
+		},
+		{
+			name:       "changed modification time is parsed again",
+			touch:      true,
+			wantMisses: 2,
+			want:       "# Weekly\n-- footer --\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS, configPort := newTemplateTree(map[string]string{
+				"_partials/footer.md": "-- footer --\n",
+				"note.md":             "# {{.title}}\n{{template \"footer\"}}",
+			})
+			templatesDir := configPort.Config().TemplatesDir
+			path := filepath.Join(templatesDir, "note.md")
+
+			modTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			mockFS.SetStatFunc(func(p string) (spi.FileInfo, error) {
+				if p == path {
+					return spi.FileInfo{Path: p, ModTime: modTime}, nil
+				}
+				return spi.FileInfo{Path: p, IsDir: true}, nil
+			})
+
+			adapter := NewFSAdapter(
+				mockFS,
+				templatedomain.NewStaticTemplateParser(),
+				configPort,
+			)
+
+			if _, err := adapter.GetByPath(t.Context(), path); err != nil {
+				t.Fatalf("GetByPath() unexpected error = %v", err)
+			}
+			if tt.change != nil {
+				tt.change(mockFS, templatesDir)
+			}
+			if tt.touch {
+				modTime = modified
+			}
+			tmpl, err := adapter.GetByPath(t.Context(), path)
+			if err != nil {
+				t.Fatalf("GetByPath() unexpected error = %v", err)
+			}
+
+			hits, misses := adapter.cache.stats()
+			if hits != tt.wantHits || misses != tt.wantMisses {
+				t.Errorf("hits, misses = %d, %d, want %d, %d",
+					hits, misses, tt.wantHits, tt.wantMisses)
+			}
+
+			var out strings.Builder
+			data := map[string]interface{}{"title": "Weekly"}
+			if execErr := tmpl.Parsed.Execute(&out, data); execErr != nil {
+				t.Fatalf("Execute() unexpected error = %v", execErr)
+			}
+			if out.String() != tt.want {
+				t.Errorf("rendered = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
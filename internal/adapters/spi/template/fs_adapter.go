@@ -4,59 +4,167 @@ package template
 
 import (
 	"context"
+	stderrors "errors"
+	"fmt"
+	"io/fs"
 	"path/filepath"
+	"sort"
+	"strings"
 	"text/template"
+	"time"
 
 	"github.com/JackMatanky/lithos/internal/domain"
 	"github.com/JackMatanky/lithos/internal/ports/spi"
 	"github.com/JackMatanky/lithos/internal/shared/errors"
 )
 
+// templateExtensions lists the file extensions recognised as templates during
+// enumeration of the templates directory.
+var templateExtensions = map[string]bool{
+	".md":  true,
+	".txt": true,
+}
+
+// partialsDirName is the directory under the templates directory holding
+// partials shared by every template. Its contents are not listed as templates.
+const partialsDirName = "_partials"
+
+// layoutContentBlock is the block a layout renders the template body into.
+const layoutContentBlock = "content"
+
 // FSAdapter implements TemplateRepositoryPort using filesystem
 // operations.
 // It provides template loading capabilities from the local filesystem.
 type FSAdapter struct {
 	fileSystemPort spi.FileSystemPort
 	parser         spi.TemplateParser
+	config         spi.ConfigPort
+	cache          *parseCache
 }
 
 // NewFSAdapter creates a new filesystem-based template repository
-// adapter with injected parser dependency.
+// adapter with injected parser dependency. Templates are enumerated from the
+// TemplatesDir of the supplied configuration.
 func NewFSAdapter(
 	fileSystemPort spi.FileSystemPort,
 	parser spi.TemplateParser,
+	configPort spi.ConfigPort,
 ) *FSAdapter {
 	return &FSAdapter{
 		fileSystemPort: fileSystemPort,
 		parser:         parser,
+		config:         configPort,
+		cache:          newParseCache(),
 	}
 }
 
-// List returns metadata for all available templates.
-// Currently returns an empty list as template enumeration is not yet
-// implemented.
+// List returns metadata for all templates found recursively under the
+// configured templates directory, sorted by ID. Hidden files and directories
+// are skipped. A missing templates directory yields an empty list.
 func (a *FSAdapter) List(
 	ctx context.Context,
 ) ([]spi.TemplateMetadata, error) {
-	// TODO: Implement template enumeration from configured template directories
-	// This will be needed for Epic 4 (interactive template selection)
-	return []spi.TemplateMetadata{}, nil
+	templatesDir := a.config.Config().TemplatesDir
+
+	exists, err := a.directoryExists(templatesDir)
+	if err != nil || !exists {
+		return []spi.TemplateMetadata{}, err
+	}
+
+	var paths []string
+	walkErr := a.fileSystemPort.Walk(
+		templatesDir,
+		func(path string, isDir bool) error {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if a.isTemplateFile(templatesDir, path, isDir) {
+				paths = append(paths, path)
+			}
+			return nil
+		},
+	)
+	if walkErr != nil {
+		return nil, errors.NewResourceError(
+			"filesystem",
+			"walk",
+			templatesDir,
+			walkErr,
+		)
+	}
+
+	templates := make([]spi.TemplateMetadata, 0, len(paths))
+	for _, path := range paths {
+		metadata, metaErr := a.loadMetadata(templatesDir, path)
+		if metaErr != nil {
+			return nil, metaErr
+		}
+		templates = append(templates, metadata)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].ID < templates[j].ID
+	})
+
+	return templates, nil
 }
 
-// Get retrieves a specific template by ID.
-// Currently not implemented as ID-based lookup requires template enumeration.
+// Get retrieves a specific template by ID. The ID is first matched exactly
+// against template IDs; otherwise a bare name matches the single template with
+// that base name. Ambiguous names are reported rather than guessed.
 func (a *FSAdapter) Get(
 	ctx context.Context,
 	id string,
 ) (*domain.Template, error) {
-	// TODO: Implement ID-based template lookup
-	// This requires implementing List first
-	return nil, errors.NewTemplateError(id, 0, "not found", nil)
+	templates, err := a.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := strings.TrimSuffix(filepath.ToSlash(id), "/")
+	var matches []spi.TemplateMetadata
+	for _, metadata := range templates {
+		if metadata.ID == wanted {
+			return a.GetByPath(ctx, metadata.FilePath)
+		}
+		if metadata.Name == wanted {
+			matches = append(matches, metadata)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, errors.NewTemplateNotFoundError(id)
+	case 1:
+		return a.GetByPath(ctx, matches[0].FilePath)
+	default:
+		ids := make([]string, len(matches))
+		for i, metadata := range matches {
+			ids[i] = metadata.ID
+		}
+		return nil, errors.NewTemplateError(
+			id,
+			0,
+			fmt.Sprintf("ambiguous name, matches %s", strings.Join(ids, ", ")),
+			nil,
+		)
+	}
+}
+
+// ListPartials returns the partials under the templates directory's _partials
+// folder, sorted by name. A missing _partials folder yields no partials.
+func (a *FSAdapter) ListPartials(
+	ctx context.Context,
+) ([]domain.Partial, error) {
+	return a.loadPartials(ctx)
 }
 
 // GetByPath loads a template from a specific file path.
 // This method supports the current CLI workflow where users specify template
-// paths.
+// paths. Partials from the templates directory are parsed into the template's
+// namespace, and a layout declared in the header becomes the entry point.
+// Parsed templates are cached per path and reused while the content, the
+// partials, and the file's modification time are unchanged.
 func (a *FSAdapter) GetByPath(
 	ctx context.Context,
 	path string,
@@ -70,20 +178,288 @@ func (a *FSAdapter) GetByPath(
 		)
 	}
 
-	// Parse the template content
-	parsed, err := a.parseTemplateContent(ctx, content)
+	// Load partials shared by every template
+	partials, err := a.loadPartials(ctx)
 	if err != nil {
-		return nil, errors.WrapWithContext(
-			errors.Wrap(err, "failed to parse template"),
-			map[string]interface{}{"path": path},
-		)
+		return nil, err
 	}
 
 	// Extract template name from path
 	templateName := a.extractTemplateName(path)
 
+	// Reuse the parse of unchanged content and partials
+	hash := contentHash(content, partials)
+	modTime := a.modTime(path)
+	entry, ok := a.cache.get(path, hash, modTime)
+	if !ok {
+		entry, err = a.compile(ctx, templateName, content, partials)
+		if err != nil {
+			return nil, errors.WrapWithContext(
+				err,
+				map[string]interface{}{"path": path},
+			)
+		}
+		entry.hash = hash
+		entry.modTime = modTime
+		a.cache.put(path, entry)
+	}
+
 	// Create and return domain template object
-	return a.createTemplate(path, templateName, content, parsed), nil
+	tmpl := a.createTemplate(
+		path,
+		templateName,
+		content,
+		entry.parsed,
+		entry.header,
+	)
+	tmpl.Partials = partials
+	return tmpl, nil
+}
+
+// compile parses template content with partials, extracts its header, and
+// makes its layout, if it declares one, the entry point. The returned entry
+// has its parse and header set, for the caller to cache.
+func (a *FSAdapter) compile(
+	ctx context.Context,
+	templateName string,
+	content []byte,
+	partials []domain.Partial,
+) (cacheEntry, error) {
+	// Parse the template content
+	parsed, err := a.parseTemplateContent(
+		ctx,
+		templateName,
+		content,
+		partials,
+	)
+	if err != nil {
+		return cacheEntry{}, errors.Wrap(err, "failed to parse template")
+	}
+
+	// Extract metadata header declared by the template
+	header, err := parseHeader(string(content))
+	if err != nil {
+		return cacheEntry{}, errors.Wrap(
+			err,
+			"failed to parse template header",
+		)
+	}
+
+	// Start rendering at the layout when the template extends one
+	parsed, err = a.applyLayout(templateName, parsed, header.Layout)
+	if err != nil {
+		return cacheEntry{}, err
+	}
+	return cacheEntry{parsed: parsed, header: header}, nil
+}
+
+// directoryExists reports whether path exists. Errors other than "not found"
+// are propagated.
+func (a *FSAdapter) directoryExists(path string) (bool, error) {
+	_, err := a.fileSystemPort.Stat(path)
+	if err == nil {
+		return true, nil
+	}
+	if stderrors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return false, errors.NewResourceError("filesystem", "stat", path, err)
+}
+
+// isTemplateFile reports whether a walked path is a template. Directories,
+// unsupported extensions, partials, and anything inside a hidden directory are
+// excluded.
+func (a *FSAdapter) isTemplateFile(root, path string, isDir bool) bool {
+	if !a.isTemplateSource(root, path, isDir) {
+		return false
+	}
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return strings.Split(filepath.ToSlash(rel), "/")[0] != partialsDirName
+}
+
+// isTemplateSource reports whether a walked path holds template text: a
+// non-hidden file with a template extension.
+func (a *FSAdapter) isTemplateSource(root, path string, isDir bool) bool {
+	if isDir || !templateExtensions[strings.ToLower(filepath.Ext(path))] {
+		return false
+	}
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		if strings.HasPrefix(part, ".") {
+			return false
+		}
+	}
+	return true
+}
+
+// loadPartials reads every partial under the partials directory, sorted by
+// name. Each partial is named by its path relative to that directory without
+// extension, e.g. "header" or "layouts/note". A missing partials directory
+// yields no partials.
+func (a *FSAdapter) loadPartials(
+	ctx context.Context,
+) ([]domain.Partial, error) {
+	partialsDir := filepath.Join(
+		a.config.Config().TemplatesDir,
+		partialsDirName,
+	)
+
+	exists, err := a.directoryExists(partialsDir)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	var partials []domain.Partial
+	walkErr := a.fileSystemPort.Walk(
+		partialsDir,
+		func(path string, isDir bool) error {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if !a.isTemplateSource(partialsDir, path, isDir) {
+				return nil
+			}
+
+			content, readErr := a.readTemplateFile(path)
+			if readErr != nil {
+				return errors.WrapWithContext(
+					errors.Wrap(readErr, "failed to read partial"),
+					map[string]interface{}{"path": path},
+				)
+			}
+			partials = append(partials, domain.Partial{
+				Name:    a.templateID(partialsDir, path),
+				Path:    path,
+				Content: string(content),
+			})
+			return nil
+		},
+	)
+	if walkErr != nil {
+		return nil, errors.NewResourceError(
+			"filesystem",
+			"walk",
+			partialsDir,
+			walkErr,
+		)
+	}
+
+	sort.Slice(partials, func(i, j int) bool {
+		return partials[i].Name < partials[j].Name
+	})
+
+	return partials, nil
+}
+
+// applyLayout returns the template to execute for a template extending
+// layout. The template body is registered as the layout's "content" block
+// unless the template defines that block explicitly and has an empty body.
+// Without a layout, parsed is returned unchanged.
+func (a *FSAdapter) applyLayout(
+	templateName string,
+	parsed *template.Template,
+	layout string,
+) (*template.Template, error) {
+	if layout == "" {
+		return parsed, nil
+	}
+
+	entry := parsed.Lookup(layout)
+	if entry == nil {
+		return nil, errors.NewTemplateError(
+			templateName,
+			0,
+			fmt.Sprintf(
+				"layout %q not found in %s",
+				layout,
+				partialsDirName,
+			),
+			nil,
+		)
+	}
+
+	_, err := parsed.AddParseTree(layoutContentBlock, parsed.Tree)
+	if err != nil {
+		return nil, errors.NewTemplateError(
+			templateName,
+			0,
+			"failed to apply layout",
+			err,
+		)
+	}
+
+	return entry, nil
+}
+
+// loadMetadata reads the template at path and builds its metadata, including
+// the description declared in its header.
+func (a *FSAdapter) loadMetadata(
+	root, path string,
+) (spi.TemplateMetadata, error) {
+	content, err := a.readTemplateFile(path)
+	if err != nil {
+		return spi.TemplateMetadata{}, errors.WrapWithContext(
+			errors.Wrap(err, "failed to read template file"),
+			map[string]interface{}{"path": path},
+		)
+	}
+
+	header, err := parseHeader(string(content))
+	if err != nil {
+		return spi.TemplateMetadata{}, errors.WrapWithContext(
+			errors.Wrap(err, "failed to parse template header"),
+			map[string]interface{}{"path": path},
+		)
+	}
+
+	return spi.TemplateMetadata{
+		ID:          a.templateID(root, path),
+		Name:        a.extractTemplateName(path),
+		FilePath:    path,
+		Content:     string(content),
+		Description: header.Description,
+	}, nil
+}
+
+// templateID derives the stable identifier of the template at path: its path
+// relative to root, with forward slashes and without extension.
+func (a *FSAdapter) templateID(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		rel = filepath.Base(path)
+	}
+	rel = filepath.ToSlash(rel)
+	return strings.TrimSuffix(rel, filepath.Ext(rel))
+}
+
+// pathID returns the ID of the template at path, or its name when path lies
+// outside the templates directory.
+func (a *FSAdapter) pathID(path string) string {
+	root := a.config.Config().TemplatesDir
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." ||
+		strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return a.extractTemplateName(path)
+	}
+	return a.templateID(root, path)
+}
+
+// modTime returns the modification time of the file at path, or the zero
+// time when it cannot be determined.
+func (a *FSAdapter) modTime(path string) time.Time {
+	info, err := a.fileSystemPort.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime
 }
 
 // readTemplateFile reads the content of a template file from the given path.
@@ -91,19 +467,32 @@ func (a *FSAdapter) readTemplateFile(path string) ([]byte, error) {
 	return a.fileSystemPort.ReadFile(path)
 }
 
-// parseTemplateContent parses the template content and returns the parse
-// result.
+// parseTemplateContent parses the template content together with the
+// partials and returns the parse result. Parse errors in the content are
+// attributed to templateName.
 func (a *FSAdapter) parseTemplateContent(
 	ctx context.Context,
+	templateName string,
 	content []byte,
+	partials []domain.Partial,
 ) (*template.Template, error) {
-	parseResult := a.parser.Parse(ctx, string(content))
+	parseResult := a.parser.ParseWithPartials(ctx, string(content), partials)
 	if parseResult.IsErr() {
-		return nil, parseResult.Error()
+		return nil, nameTemplateError(parseResult.Error(), templateName)
 	}
 	return parseResult.Value(), nil
 }
 
+// nameTemplateError attributes an unnamed TemplateError from the parser to
+// templateName. Other errors are returned unchanged.
+func nameTemplateError(err error, templateName string) error {
+	var templateErr errors.TemplateError
+	if stderrors.As(err, &templateErr) && templateErr.Template() == "" {
+		return templateErr.WithTemplate(templateName)
+	}
+	return err
+}
+
 // extractTemplateName extracts the template name from the file path by taking
 // the base name and removing the extension.
 func (a *FSAdapter) extractTemplateName(path string) string {
@@ -120,11 +509,14 @@ func (a *FSAdapter) createTemplate(
 	path, name string,
 	content []byte,
 	parsed *template.Template,
+	header domain.TemplateHeader,
 ) *domain.Template {
 	return &domain.Template{
+		ID:       a.pathID(path),
 		FilePath: path,
 		Name:     name,
 		Content:  string(content),
 		Parsed:   parsed,
+		Header:   header,
 	}
 }
@@ -2,15 +2,23 @@ package template
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"testing"
 	"text/template"
 
+	templatedomain "github.com/JackMatanky/lithos/internal/app/template"
+	"github.com/JackMatanky/lithos/internal/domain"
 	"github.com/JackMatanky/lithos/internal/ports/spi"
 	"github.com/JackMatanky/lithos/internal/shared/errors"
+	testutils "github.com/JackMatanky/lithos/tests/utils"
 )
 
+// testVaultPath is the vault root used by adapter tests.
+const testVaultPath = "/vault"
+
 // mockFileSystemPort implements spi.FileSystemPort for testing.
 type mockFileSystemPort struct {
 	readFileFunc func(path string) ([]byte, error)
@@ -32,6 +40,14 @@ func (m *mockTemplateParser) Parse(
 	return errors.Ok(template.New("mock"))
 }
 
+func (m *mockTemplateParser) ParseWithPartials(
+	ctx context.Context,
+	content string,
+	partials []domain.Partial,
+) errors.Result[*template.Template] {
+	return m.Parse(ctx, content)
+}
+
 func (m *mockFileSystemPort) ReadFile(path string) ([]byte, error) {
 	if m.readFileFunc != nil {
 		return m.readFileFunc(path)
@@ -51,45 +67,190 @@ func (m *mockFileSystemPort) Walk(root string, fn spi.WalkFunc) error {
 	return nil
 }
 
+func (m *mockFileSystemPort) Stat(path string) (spi.FileInfo, error) {
+	return spi.FileInfo{Path: path}, nil
+}
+
 func TestNewFSAdapter(t *testing.T) {
 	mockFS := &mockFileSystemPort{}
 	mockParser := &mockTemplateParser{}
-	adapter := NewFSAdapter(mockFS, mockParser)
+	adapter := NewFSAdapter(mockFS, mockParser, newTestConfigPort())
 
 	if adapter == nil {
 		t.Error("NewFSAdapter() returned nil")
 	}
 }
 
-func TestFSAdapter_ListTemplates(t *testing.T) {
-	mockFS := &mockFileSystemPort{}
-	mockParser := &mockTemplateParser{}
-	adapter := NewFSAdapter(mockFS, mockParser)
-	ctx := context.Background()
+// newTestConfigPort returns a config port rooted at testVaultPath.
+func newTestConfigPort() *testutils.MockConfigPort {
+	return testutils.NewMockConfigPort(testVaultPath)
+}
+
+// newTemplateTree creates an in-memory templates directory from files, keyed
+// by path relative to the templates directory.
+func newTemplateTree(
+	files map[string]string,
+) (*testutils.MockFileSystemPort, *testutils.MockConfigPort) {
+	configPort := newTestConfigPort()
+	templatesDir := configPort.Config().TemplatesDir
+
+	mockFS := testutils.NewMockFileSystemPort()
+	mockFS.AddWalkPath(templatesDir)
+	for rel, content := range files {
+		path := filepath.Join(templatesDir, filepath.FromSlash(rel))
+		mockFS.AddFile(path, []byte(content))
+		mockFS.AddWalkPath(path)
+	}
+	return mockFS, configPort
+}
 
-	templates, err := adapter.List(ctx)
+func TestFSAdapter_List(t *testing.T) {
+	meeting := "{{/* lithos\ndescription: Weekly meeting\n*/}}\n# Meeting"
+	mockFS, configPort := newTemplateTree(map[string]string{
+		"meeting.md":           meeting,
+		"projects/kickoff.txt": "kickoff",
+		"daily.md":             "daily",
+		"notes.json":           "{}",
+		".drafts/wip.md":       "draft",
+		".hidden.md":           "hidden",
+	})
+	adapter := NewFSAdapter(mockFS, &mockTemplateParser{}, configPort)
+
+	templates, err := adapter.List(t.Context())
 	if err != nil {
-		t.Errorf("List() unexpected error = %v", err)
-		return
+		t.Fatalf("List() unexpected error = %v", err)
+	}
+
+	templatesDir := configPort.Config().TemplatesDir
+	want := []spi.TemplateMetadata{
+		{
+			ID:       "daily",
+			Name:     "daily",
+			FilePath: filepath.Join(templatesDir, "daily.md"),
+		},
+		{
+			ID:          "meeting",
+			Name:        "meeting",
+			FilePath:    filepath.Join(templatesDir, "meeting.md"),
+			Description: "Weekly meeting",
+		},
+		{
+			ID:       "projects/kickoff",
+			Name:     "kickoff",
+			FilePath: filepath.Join(templatesDir, "projects", "kickoff.txt"),
+		},
+	}
+
+	if len(templates) != len(want) {
+		t.Fatalf("List() returned %d templates, want %d: %+v",
+			len(templates), len(want), templates)
+	}
+	for i, got := range templates {
+		if got.ID != want[i].ID || got.Name != want[i].Name ||
+			got.FilePath != want[i].FilePath ||
+			got.Description != want[i].Description {
+			t.Errorf("List()[%d] = %+v, want %+v", i, got, want[i])
+		}
 	}
+}
+
+func TestFSAdapter_List_MissingDirectory(t *testing.T) {
+	adapter := NewFSAdapter(
+		testutils.NewMockFileSystemPort(),
+		&mockTemplateParser{},
+		newTestConfigPort(),
+	)
 
-	// Currently returns empty list
+	templates, err := adapter.List(t.Context())
+	if err != nil {
+		t.Fatalf("List() unexpected error = %v", err)
+	}
 	if len(templates) != 0 {
 		t.Errorf("List() = %v, want empty slice", templates)
 	}
 }
 
 func TestFSAdapter_Get(t *testing.T) {
-	mockFS := &mockFileSystemPort{}
-	mockParser := &mockTemplateParser{}
-	adapter := NewFSAdapter(mockFS, mockParser)
-	ctx := context.Background()
+	mockFS, configPort := newTemplateTree(map[string]string{
+		"meeting.md":          "meeting",
+		"projects/kickoff.md": "kickoff",
+		"work/review.md":      "work review",
+		"personal/review.md":  "personal review",
+		"projects/meeting.md": "project meeting",
+	})
+	adapter := NewFSAdapter(mockFS, &mockTemplateParser{}, configPort)
+
+	tests := []struct {
+		name        string
+		id          string
+		wantContent string
+		wantErr     string
+	}{
+		{
+			name:        "exact ID wins over name match",
+			id:          "meeting",
+			wantContent: "meeting",
+		},
+		{
+			name:        "nested ID",
+			id:          "projects/meeting",
+			wantContent: "project meeting",
+		},
+		{
+			name:        "unique base name",
+			id:          "kickoff",
+			wantContent: "kickoff",
+		},
+		{
+			name:    "ambiguous base name",
+			id:      "review",
+			wantErr: "ambiguous name, matches personal/review, work/review",
+		},
+		{
+			name:    "not found",
+			id:      "nonexistent",
+			wantErr: "template 'nonexistent' not found",
+		},
+	}
 
-	_, err := adapter.Get(ctx, "nonexistent")
-	if err == nil {
-		t.Error(
-			"GetTemplate() expected error for nonexistent template, got nil",
-		)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := adapter.Get(t.Context(), tt.id)
+
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("Get(%q) expected error, got nil", tt.id)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Get(%q) error = %v, want %q", tt.id, err,
+						tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Get(%q) unexpected error = %v", tt.id, err)
+			}
+			if tmpl.Content != tt.wantContent {
+				t.Errorf("Get(%q) content = %q, want %q", tt.id,
+					tmpl.Content, tt.wantContent)
+			}
+		})
+	}
+}
+
+func TestFSAdapter_Get_NotFoundErrorType(t *testing.T) {
+	mockFS, configPort := newTemplateTree(nil)
+	adapter := NewFSAdapter(mockFS, &mockTemplateParser{}, configPort)
+
+	_, err := adapter.Get(t.Context(), "meeting")
+
+	var notFound errors.TemplateNotFoundError
+	if !stderrors.As(err, &notFound) {
+		t.Fatalf("Get() error = %T, want TemplateNotFoundError", err)
+	}
+	if notFound.Template() != "meeting" {
+		t.Errorf("Template() = %q, want %q", notFound.Template(), "meeting")
 	}
 }
 
@@ -104,7 +265,7 @@ func TestFSAdapter_GetByPath_Success(t *testing.T) {
 	}
 
 	mockParser := &mockTemplateParser{}
-	adapter := NewFSAdapter(mockFS, mockParser)
+	adapter := NewFSAdapter(mockFS, mockParser, newTestConfigPort())
 	ctx := context.Background()
 
 	got, err := adapter.GetByPath(ctx, "/path/to/test-template.txt")
@@ -144,7 +305,7 @@ func TestFSAdapter_GetByPath_FileReadError(t *testing.T) {
 	}
 
 	mockParser := &mockTemplateParser{}
-	adapter := NewFSAdapter(mockFS, mockParser)
+	adapter := NewFSAdapter(mockFS, mockParser, newTestConfigPort())
 	ctx := context.Background()
 
 	_, err := adapter.GetByPath(ctx, "/invalid/path.txt")
@@ -174,7 +335,7 @@ func TestFSAdapter_GetByPath_ParseError(t *testing.T) {
 	}
 
 	mockParser := &mockTemplateParser{}
-	adapter := NewFSAdapter(mockFS, mockParser)
+	adapter := NewFSAdapter(mockFS, mockParser, newTestConfigPort())
 	ctx := context.Background()
 
 	_, err := adapter.GetByPath(ctx, "/path/to/invalid.txt")
@@ -202,3 +363,128 @@ func containsString(s, substr string) bool {
 	}
 	return false
 }
+
+func TestFSAdapter_GetByPath_Partials(t *testing.T) {
+	const layout = "---\n" +
+		"{{block \"frontmatter\" .}}type: note\n{{end}}" +
+		"---\n" +
+		"{{block \"content\" .}}{{end}}" +
+		"{{template \"footer\" .}}"
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "include partial",
+			template: "# {{.title}}\n{{template \"footer\" .}}",
+			want:     "# Weekly\n-- Weekly --\n",
+		},
+		{
+			name: "layout wraps body",
+			template: "{{- /* lithos\nlayout: layouts/note\n*/ -}}\n" +
+				"# {{.title}}\n",
+			want: "---\ntype: note\n---\n# Weekly\n-- Weekly --\n",
+		},
+		{
+			name: "layout block overridden",
+			template: "{{- /* lithos\nlayout: layouts/note\n*/ -}}\n" +
+				"{{define \"frontmatter\"}}type: meeting\n{{end}}" +
+				"# {{.title}}\n",
+			want: "---\ntype: meeting\n---\n# Weekly\n-- Weekly --\n",
+		},
+		{
+			name: "explicit content block",
+			template: "{{- /* lithos\nlayout: layouts/note\n*/ -}}\n" +
+				"{{define \"content\"}}Body{{end}}",
+			want: "---\ntype: note\n---\nBody-- Weekly --\n",
+		},
+		{
+			name:     "unknown layout",
+			template: "{{/* lithos\nlayout: missing\n*/}}\n",
+			wantErr:  `layout "missing" not found`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS, configPort := newTemplateTree(map[string]string{
+				"_partials/footer.md":       "-- {{.title}} --\n",
+				"_partials/layouts/note.md": layout,
+				"note.md":                   tt.template,
+			})
+			templatesDir := configPort.Config().TemplatesDir
+			adapter := NewFSAdapter(
+				mockFS,
+				templatedomain.NewStaticTemplateParser(),
+				configPort,
+			)
+
+			tmpl, err := adapter.GetByPath(
+				t.Context(),
+				filepath.Join(templatesDir, "note.md"),
+			)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("GetByPath() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetByPath() unexpected error = %v", err)
+			}
+
+			var out strings.Builder
+			data := map[string]interface{}{"title": "Weekly"}
+			if execErr := tmpl.Parsed.Execute(&out, data); execErr != nil {
+				t.Fatalf("Execute() unexpected error = %v", execErr)
+			}
+			if out.String() != tt.want {
+				t.Errorf("rendered = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFSAdapter_List_ExcludesPartials(t *testing.T) {
+	mockFS, configPort := newTemplateTree(map[string]string{
+		"_partials/footer.md": "footer",
+		"note.md":             "note",
+	})
+	adapter := NewFSAdapter(mockFS, &mockTemplateParser{}, configPort)
+
+	templates, err := adapter.List(t.Context())
+	if err != nil {
+		t.Fatalf("List() unexpected error = %v", err)
+	}
+	if len(templates) != 1 || templates[0].ID != "note" {
+		t.Errorf("List() = %+v, want only the note template", templates)
+	}
+}
+
+func TestFSAdapter_ListPartials(t *testing.T) {
+	mockFS, configPort := newTemplateTree(map[string]string{
+		"_partials/footer.md":       "footer",
+		"_partials/layouts/note.md": "layout",
+		"note.md":                   "note",
+	})
+	templatesDir := configPort.Config().TemplatesDir
+	adapter := NewFSAdapter(mockFS, &mockTemplateParser{}, configPort)
+
+	partials, err := adapter.ListPartials(t.Context())
+	if err != nil {
+		t.Fatalf("ListPartials() unexpected error = %v", err)
+	}
+	if len(partials) != 2 {
+		t.Fatalf("ListPartials() = %+v, want 2 partials", partials)
+	}
+	want := filepath.Join(templatesDir, "_partials", "footer.md")
+	if partials[0].Name != "footer" || partials[0].Path != want {
+		t.Errorf("partials[0] = %+v, want footer at %s", partials[0], want)
+	}
+	if partials[1].Name != "layouts/note" {
+		t.Errorf("partials[1].Name = %q, want layouts/note", partials[1].Name)
+	}
+}
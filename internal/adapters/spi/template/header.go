@@ -0,0 +1,186 @@
+// Package template provides SPI adapter implementations for template
+// operations.
+//
+// This file extracts the optional metadata header from template content.
+package template
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/JackMatanky/lithos/internal/domain"
+	"go.yaml.in/yaml/v3"
+)
+
+// headerPattern matches a leading template comment whose first line is the
+// `lithos` marker, capturing the YAML body:
+//
+//	{{- /* lithos
+//	description: Project note
+//	output: projects/{{ .title | slug }}.md
+//	schema: project
+//	layout: note
+//	params:
+//	  - name: title
+//	    type: string
+//	    required: true
+//	    description: Project title
+//	bundle:
+//	  - template: meeting
+//	    output: meetings/{{ .title | slug }}-kickoff.md
+//	    data:
+//	      kind: kickoff
+//	*/ -}}
+var headerPattern = regexp.MustCompile(
+	`(?s)\A\s*\{\{-?\s*/\*[ \t]*lithos[ \t]*\r?\n(.*?)\*/\s*-?\}\}`,
+)
+
+// headerDTO mirrors the YAML structure of a template header.
+type headerDTO struct {
+	Description string      `yaml:"description"`
+	Output      string      `yaml:"output"`
+	Schema      string      `yaml:"schema"`
+	Layout      string      `yaml:"layout"`
+	Params      []paramDTO  `yaml:"params"`
+	Bundle      []bundleDTO `yaml:"bundle"`
+}
+
+// paramDTO mirrors a single parameter declaration in a template header.
+type paramDTO struct {
+	Name        string      `yaml:"name"`
+	Type        string      `yaml:"type"`
+	Required    bool        `yaml:"required"`
+	Default     interface{} `yaml:"default"`
+	Description string      `yaml:"description"`
+}
+
+// bundleDTO mirrors a single bundle entry in a template header.
+type bundleDTO struct {
+	Template string                 `yaml:"template"`
+	Output   string                 `yaml:"output"`
+	Data     map[string]interface{} `yaml:"data"`
+}
+
+// knownParamTypes lists the parameter types a header may declare.
+var knownParamTypes = map[string]bool{
+	domain.ParamTypeString:  true,
+	domain.ParamTypeNumber:  true,
+	domain.ParamTypeInteger: true,
+	domain.ParamTypeBool:    true,
+	domain.ParamTypeDate:    true,
+	domain.ParamTypeList:    true,
+}
+
+// parseHeader extracts the template header from content. Templates without a
+// header yield a zero-value TemplateHeader. Unknown header keys are rejected
+// so typos surface instead of being silently ignored.
+func parseHeader(content string) (domain.TemplateHeader, error) {
+	match := headerPattern.FindStringSubmatch(content)
+	if match == nil {
+		return domain.TemplateHeader{}, nil
+	}
+
+	var dto headerDTO
+	decoder := yaml.NewDecoder(bytes.NewReader([]byte(match[1])))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&dto); err != nil && !errors.Is(err, io.EOF) {
+		return domain.TemplateHeader{}, err
+	}
+
+	params, err := convertParams(dto.Params)
+	if err != nil {
+		return domain.TemplateHeader{}, err
+	}
+
+	bundle, err := convertBundle(dto.Bundle)
+	if err != nil {
+		return domain.TemplateHeader{}, err
+	}
+
+	return domain.TemplateHeader{
+		Description: dto.Description,
+		Output:      dto.Output,
+		Schema:      dto.Schema,
+		Layout:      dto.Layout,
+		Params:      params,
+		Bundle:      bundle,
+	}, nil
+}
+
+// convertParams validates parameter declarations and converts them to domain
+// params. Names must be unique, types must be known (an omitted type means
+// string), and required params cannot declare a default.
+func convertParams(dtos []paramDTO) ([]domain.TemplateParam, error) {
+	if len(dtos) == 0 {
+		return nil, nil
+	}
+
+	params := make([]domain.TemplateParam, 0, len(dtos))
+	seen := make(map[string]bool, len(dtos))
+	for i, dto := range dtos {
+		if dto.Name == "" {
+			return nil, fmt.Errorf("param %d: name is required", i+1)
+		}
+		if seen[dto.Name] {
+			return nil, fmt.Errorf(
+				"param %q: declared more than once",
+				dto.Name,
+			)
+		}
+		seen[dto.Name] = true
+
+		paramType := dto.Type
+		if paramType == "" {
+			paramType = domain.ParamTypeString
+		}
+		if !knownParamTypes[paramType] {
+			return nil, fmt.Errorf(
+				"param %q: unknown type %q "+
+					"(expected string, number, integer, bool, date, or list)",
+				dto.Name,
+				dto.Type,
+			)
+		}
+		if dto.Required && dto.Default != nil {
+			return nil, fmt.Errorf(
+				"param %q: required params cannot declare a default",
+				dto.Name,
+			)
+		}
+
+		params = append(params, domain.TemplateParam{
+			Name:        dto.Name,
+			Type:        paramType,
+			Required:    dto.Required,
+			Default:     dto.Default,
+			Description: dto.Description,
+		})
+	}
+
+	return params, nil
+}
+
+// convertBundle validates bundle entries and converts them to domain bundle
+// entries. Every entry must name a template.
+func convertBundle(dtos []bundleDTO) ([]domain.BundleEntry, error) {
+	if len(dtos) == 0 {
+		return nil, nil
+	}
+
+	entries := make([]domain.BundleEntry, 0, len(dtos))
+	for i, dto := range dtos {
+		if dto.Template == "" {
+			return nil, fmt.Errorf("bundle entry %d: template is required", i+1)
+		}
+		entries = append(entries, domain.BundleEntry{
+			Template: dto.Template,
+			Output:   dto.Output,
+			Data:     dto.Data,
+		})
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,274 @@
+package template
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JackMatanky/lithos/internal/domain"
+)
+
+func TestParseHeader(t *testing.T) {
+	tests := []struct {
+		name            string
+		content         string
+		wantDescription string
+		wantOutput      string
+		wantLayout      string
+		wantSchema      string
+		wantErr         bool
+	}{
+		{
+			name:    "no header",
+			content: "# {{.title}}\n",
+		},
+		{
+			name:    "plain comment is not a header",
+			content: "{{/* just a comment */}}\n# Title\n",
+		},
+		{
+			name: "header with trim markers",
+			content: "{{- /* lithos\n" +
+				"description: Project note\n" +
+				"output: projects/{{ .title | slug }}.md\n" +
+				"*/ -}}\n# {{.title}}\n",
+			wantDescription: "Project note",
+			wantOutput:      "projects/{{ .title | slug }}.md",
+		},
+		{
+			name: "header without trim markers",
+			content: "{{/* lithos\n" +
+				"output: inbox/note.md\n" +
+				"*/}}\nBody\n",
+			wantOutput: "inbox/note.md",
+		},
+		{
+			name: "header with layout",
+			content: "{{/* lithos\n" +
+				"layout: layouts/note\n" +
+				"*/}}\nBody\n",
+			wantLayout: "layouts/note",
+		},
+		{
+			name: "header with schema",
+			content: "{{/* lithos\n" +
+				"schema: project\n" +
+				"*/}}\nBody\n",
+			wantSchema: "project",
+		},
+		{
+			name:    "empty header",
+			content: "{{/* lithos\n*/}}\nBody\n",
+		},
+		{
+			name: "header must lead the template",
+			content: "Intro\n{{/* lithos\n" +
+				"output: inbox/note.md\n" +
+				"*/}}\n",
+		},
+		{
+			name: "unknown header key",
+			content: "{{/* lithos\n" +
+				"outptu: inbox/note.md\n" +
+				"*/}}\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header, err := parseHeader(tt.content)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("parseHeader() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseHeader() unexpected error = %v", err)
+			}
+
+			if header.Description != tt.wantDescription {
+				t.Errorf(
+					"Description = %q, want %q",
+					header.Description,
+					tt.wantDescription,
+				)
+			}
+			if header.Output != tt.wantOutput {
+				t.Errorf("Output = %q, want %q", header.Output, tt.wantOutput)
+			}
+			if header.Layout != tt.wantLayout {
+				t.Errorf("Layout = %q, want %q", header.Layout, tt.wantLayout)
+			}
+			if header.Schema != tt.wantSchema {
+				t.Errorf("Schema = %q, want %q", header.Schema, tt.wantSchema)
+			}
+		})
+	}
+}
+
+func TestParseHeader_Params(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  string
+		want    []domain.TemplateParam
+		wantErr string
+	}{
+		{
+			name: "typed params",
+			params: "  - name: title\n" +
+				"    required: true\n" +
+				"    description: Meeting title\n" +
+				"  - name: attendees\n" +
+				"    type: list\n" +
+				"    default: [Ada, Grace]\n" +
+				"  - name: duration\n" +
+				"    type: integer\n" +
+				"    default: 30\n",
+			want: []domain.TemplateParam{
+				{
+					Name:        "title",
+					Type:        domain.ParamTypeString,
+					Required:    true,
+					Description: "Meeting title",
+				},
+				{
+					Name:    "attendees",
+					Type:    domain.ParamTypeList,
+					Default: []interface{}{"Ada", "Grace"},
+				},
+				{
+					Name:    "duration",
+					Type:    domain.ParamTypeInteger,
+					Default: 30,
+				},
+			},
+		},
+		{
+			name:    "missing name",
+			params:  "  - type: string\n",
+			wantErr: "param 1: name is required",
+		},
+		{
+			name:    "duplicate name",
+			params:  "  - name: title\n  - name: title\n",
+			wantErr: `param "title": declared more than once`,
+		},
+		{
+			name:    "unknown type",
+			params:  "  - name: title\n    type: text\n",
+			wantErr: `param "title": unknown type "text"`,
+		},
+		{
+			name: "required with default",
+			params: "  - name: title\n" +
+				"    required: true\n" +
+				"    default: Untitled\n",
+			wantErr: "required params cannot declare a default",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := "{{/* lithos\nparams:\n" + tt.params + "*/}}\n"
+
+			header, err := parseHeader(content)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseHeader() error = %v, want %q", err,
+						tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseHeader() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(header.Params, tt.want) {
+				t.Errorf("Params = %#v, want %#v", header.Params, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHeader_Bundle(t *testing.T) {
+	tests := []struct {
+		name    string
+		bundle  string
+		want    []domain.BundleEntry
+		wantErr string
+	}{
+		{
+			name: "entries with output and data",
+			bundle: "  - template: meeting\n" +
+				"    output: meetings/{{ .title | slug }}-kickoff.md\n" +
+				"    data:\n" +
+				"      kind: kickoff\n" +
+				"  - template: tasks\n",
+			want: []domain.BundleEntry{
+				{
+					Template: "meeting",
+					Output:   "meetings/{{ .title | slug }}-kickoff.md",
+					Data:     map[string]interface{}{"kind": "kickoff"},
+				},
+				{Template: "tasks"},
+			},
+		},
+		{
+			name:    "missing template",
+			bundle:  "  - output: tasks.md\n",
+			wantErr: "bundle entry 1: template is required",
+		},
+		{
+			name:    "unknown field",
+			bundle:  "  - template: tasks\n    path: tasks.md\n",
+			wantErr: "field path not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := "{{/* lithos\nbundle:\n" + tt.bundle + "*/}}\n"
+
+			header, err := parseHeader(content)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseHeader() error = %v, want %q", err,
+						tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseHeader() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(header.Bundle, tt.want) {
+				t.Errorf("Bundle = %#v, want %#v", header.Bundle, tt.want)
+			}
+		})
+	}
+}
+
+func TestFSAdapter_GetByPath_InvalidHeader(t *testing.T) {
+	mockFS := &mockFileSystemPort{
+		readFileFunc: func(path string) ([]byte, error) {
+			return []byte("{{/* lithos\noutput: [unclosed\n*/}}\n"), nil
+		},
+	}
+
+	adapter := NewFSAdapter(mockFS, &mockTemplateParser{}, newTestConfigPort())
+
+	_, err := adapter.GetByPath(t.Context(), "/path/to/bad-header.md")
+	if err == nil {
+		t.Fatal("GetByPath() expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "failed to parse template header") {
+		t.Errorf(
+			"error = %v, want containing %q",
+			err,
+			"failed to parse template header",
+		)
+	}
+}
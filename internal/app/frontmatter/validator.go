@@ -43,8 +43,8 @@ func NewFrontmatterValidator(
 }
 
 // Validate validates frontmatter fields against a schema.
-// Returns Result[ValidationResult] with detailed field-level validation
-// lithoserrors.
+// Returns Result[ValidationResult]; when any field is invalid the error is a
+// FrontmatterValidationError listing every invalid field.
 // The schema must be pre-loaded and validated by SchemaEngine before
 // validation.
 //
@@ -83,7 +83,7 @@ func (v *FrontmatterValidator) Validate(
 	properties := schema.GetResolvedProperties()
 
 	// Validate each frontmatter field against schema properties
-	return v.validateFields(ctx, frontmatter, properties)
+	return v.validateFields(ctx, schemaName, frontmatter, properties)
 }
 
 // validateFields validates individual frontmatter fields against schema
@@ -91,6 +91,7 @@ func (v *FrontmatterValidator) Validate(
 // This is a private helper that implements the core validation logic.
 func (v *FrontmatterValidator) validateFields(
 	ctx context.Context,
+	schemaName string,
 	frontmatter domain.Frontmatter,
 	properties []domain.Property,
 ) lithoserrors.Result[lithoserrors.ValidationResult] {
@@ -120,12 +121,7 @@ func (v *FrontmatterValidator) validateFields(
 		return lithoserrors.Ok[lithoserrors.ValidationResult](result)
 	}
 	return lithoserrors.Err[lithoserrors.ValidationResult](
-		lithoserrors.NewFieldValidationError(
-			"validation",
-			"multiple field validation errors",
-			nil,
-			nil,
-		),
+		lithoserrors.NewFrontmatterValidationError(schemaName, result),
 	)
 }
 
@@ -215,7 +211,8 @@ func (v *FrontmatterValidator) validateScalarField(
 
 // validatePropertySpecValue validates a field value against its PropertySpec
 // using polymorphism. This delegates to type-specific validation methods based
-// on the PropertySpec type.
+// on the PropertySpec type. Specs are accepted as values, as the schema loader
+// produces them, or as pointers.
 func (v *FrontmatterValidator) validatePropertySpecValue(
 	fieldName string,
 	fieldValue interface{},
@@ -225,6 +222,16 @@ func (v *FrontmatterValidator) validatePropertySpecValue(
 	// and delegate to appropriate validation logic
 
 	switch s := spec.(type) {
+	case domain.StringPropertySpec:
+		return v.validateStringPropertySpec(fieldName, fieldValue, &s)
+	case domain.NumberPropertySpec:
+		return v.validateNumberPropertySpec(fieldName, fieldValue, &s)
+	case domain.DatePropertySpec:
+		return v.validateDatePropertySpec(fieldName, fieldValue, &s)
+	case domain.FilePropertySpec:
+		return v.validateFilePropertySpec(fieldName, fieldValue, &s)
+	case domain.BoolPropertySpec:
+		return v.validateBoolPropertySpec(fieldName, fieldValue, &s)
 	case *domain.StringPropertySpec:
 		return v.validateStringPropertySpec(fieldName, fieldValue, s)
 	case *domain.NumberPropertySpec:
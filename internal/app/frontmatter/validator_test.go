@@ -3,6 +3,7 @@ package frontmatter
 
 import (
 	"context"
+	stderrors "errors"
 	"testing"
 
 	"github.com/JackMatanky/lithos/internal/domain"
@@ -125,6 +126,34 @@ func TestFrontmatterValidator_Validate(t *testing.T) {
 			wantValid:    false,
 			wantErrCount: 1,
 		},
+		{
+			name:       "spec values as loaded from schema files",
+			schemaName: "test",
+			frontmatter: domain.Frontmatter{
+				Fields: map[string]interface{}{
+					"title":  "Test Note",
+					"status": "closed",
+				},
+			},
+			mockSchema: domain.Schema{
+				Name: "test",
+				ResolvedProperties: []domain.Property{
+					{
+						Name:     "title",
+						Required: true,
+						Spec:     domain.StringPropertySpec{},
+					},
+					{
+						Name: "status",
+						Spec: domain.StringPropertySpec{
+							Enum: []string{"open", "done"},
+						},
+					},
+				},
+			},
+			wantValid:    false,
+			wantErrCount: 1,
+		},
 		{
 			name:       "schema not found",
 			schemaName: "nonexistent",
@@ -156,10 +185,29 @@ func TestFrontmatterValidator_Validate(t *testing.T) {
 			)
 
 			assertValidation(t, result, tt.wantValid)
+			assertInvalidFieldCount(t, result, tt.wantErrCount)
 		})
 	}
 }
 
+// assertInvalidFieldCount checks the number of invalid fields reported by a
+// FrontmatterValidationError. Other errors report no fields.
+func assertInvalidFieldCount(
+	t *testing.T,
+	result errors.Result[errors.ValidationResult],
+	want int,
+) {
+	t.Helper()
+	got := 0
+	var validationErr errors.FrontmatterValidationError
+	if result.IsErr() && stderrors.As(result.Error(), &validationErr) {
+		got = len(validationErr.Fields())
+	}
+	if got != want {
+		t.Errorf("invalid fields = %d, want %d", got, want)
+	}
+}
+
 func TestFrontmatterValidator_validateStringPropertySpec(t *testing.T) {
 	validator := NewFrontmatterValidator(nil)
 
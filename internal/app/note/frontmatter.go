@@ -0,0 +1,124 @@
+// Package note provides domain services for creating notes in the vault.
+// This file contains the parsing of the frontmatter block at the top of a
+// note.
+package note
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JackMatanky/lithos/internal/domain"
+	"go.yaml.in/yaml/v3"
+)
+
+// frontmatterDelimiter opens and closes a frontmatter block.
+const frontmatterDelimiter = "---"
+
+// timestampTag is the YAML tag resolved for unquoted dates and times.
+const timestampTag = "!!timestamp"
+
+// ParseFrontmatter parses the YAML frontmatter block that opens content,
+// delimited by "---" lines. It reports false when content has no
+// frontmatter. Dates and times are kept as written, so that date properties
+// can be checked against the format their schema declares.
+func ParseFrontmatter(content string) (domain.Frontmatter, bool, error) {
+	block, found, err := frontmatterBlock(content)
+	if err != nil || !found {
+		return domain.Frontmatter{}, found, err
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(block), &document); err != nil {
+		return domain.Frontmatter{}, true, fmt.Errorf(
+			"frontmatter is not valid YAML: %w",
+			err,
+		)
+	}
+
+	fields := map[string]interface{}{}
+	if len(document.Content) > 0 {
+		root := document.Content[0]
+		if root.Kind != yaml.MappingNode {
+			return domain.Frontmatter{}, true, fmt.Errorf(
+				"frontmatter is not a mapping (line %d)",
+				root.Line,
+			)
+		}
+		value, err := nodeValue(root)
+		if err != nil {
+			return domain.Frontmatter{}, true, err
+		}
+		fields = value.(map[string]interface{})
+	}
+
+	return domain.NewFrontmatter(fields), true, nil
+}
+
+// frontmatterBlock returns the text between the opening and closing
+// delimiters of the frontmatter block at the start of content.
+func frontmatterBlock(content string) (string, bool, error) {
+	lines := strings.Split(content, "\n")
+	if strings.TrimRight(lines[0], "\r") != frontmatterDelimiter {
+		return "", false, nil
+	}
+
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], "\r") == frontmatterDelimiter {
+			return strings.Join(lines[1:i], "\n"), true, nil
+		}
+	}
+	return "", true, fmt.Errorf(
+		"frontmatter block is not closed with %s",
+		frontmatterDelimiter,
+	)
+}
+
+// nodeValue converts a YAML node into maps, slices, and scalars, keeping
+// timestamps as their source text.
+func nodeValue(node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return nodeValue(node.Alias)
+	case yaml.MappingNode:
+		values := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			var key string
+			if err := node.Content[i].Decode(&key); err != nil {
+				return nil, fmt.Errorf(
+					"frontmatter key on line %d: %w",
+					node.Content[i].Line,
+					err,
+				)
+			}
+			value, err := nodeValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			values[key] = value
+		}
+		return values, nil
+	case yaml.SequenceNode:
+		values := make([]interface{}, 0, len(node.Content))
+		for _, item := range node.Content {
+			value, err := nodeValue(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	}
+
+	if node.ShortTag() == timestampTag {
+		return node.Value, nil
+	}
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return nil, fmt.Errorf(
+			"frontmatter value on line %d: %w",
+			node.Line,
+			err,
+		)
+	}
+	return value, nil
+}
@@ -0,0 +1,91 @@
+package note
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFrontmatter(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		wantFound     bool
+		wantFields    map[string]interface{}
+		wantFileClass string
+		wantErr       string
+	}{
+		{
+			name: "fields and fileClass",
+			content: "---\nfileClass: contact\ncreated: 2025-01-02\n" +
+				"tags: [a, b]\nage: 36\nmeta:\n  done: true\n---\n# Body\n",
+			wantFound: true,
+			wantFields: map[string]interface{}{
+				"fileClass": "contact",
+				"created":   "2025-01-02",
+				"tags":      []interface{}{"a", "b"},
+				"age":       36,
+				"meta":      map[string]interface{}{"done": true},
+			},
+			wantFileClass: "contact",
+		},
+		{
+			name:       "empty block",
+			content:    "---\n---\nbody",
+			wantFound:  true,
+			wantFields: map[string]interface{}{},
+		},
+		{
+			name:    "no frontmatter",
+			content: "# Title\n---\nkey: value\n---\n",
+		},
+		{
+			name:      "unclosed block",
+			content:   "---\ntitle: x\n",
+			wantFound: true,
+			wantErr:   "not closed",
+		},
+		{
+			name:      "invalid YAML",
+			content:   "---\ntitle: [x\n---\n",
+			wantFound: true,
+			wantErr:   "not valid YAML",
+		},
+		{
+			name:      "not a mapping",
+			content:   "---\n- a\n- b\n---\n",
+			wantFound: true,
+			wantErr:   "not a mapping",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frontmatter, found, err := ParseFrontmatter(tt.content)
+			if found != tt.wantFound {
+				t.Errorf("found = %v, want %v", found, tt.wantFound)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseFrontmatter() error = %v, want %q",
+						err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFrontmatter() unexpected error = %v", err)
+			}
+			if !found {
+				return
+			}
+			if !reflect.DeepEqual(frontmatter.Fields, tt.wantFields) {
+				t.Errorf("Fields = %#v, want %#v",
+					frontmatter.Fields, tt.wantFields)
+			}
+			if frontmatter.FileClass != tt.wantFileClass {
+				t.Errorf("FileClass = %q, want %q",
+					frontmatter.FileClass, tt.wantFileClass)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+// Package note provides domain services for creating notes in the vault.
+// This file contains the splicing of rendered blocks into existing notes.
+package note
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InsertMode selects where InsertBlock places a block in a note.
+type InsertMode int
+
+const (
+	// InsertAppend places the block at the end of the note. This is the
+	// default mode.
+	InsertAppend InsertMode = iota
+
+	// InsertPrepend places the block at the start of the note body, right
+	// after the frontmatter block when there is one.
+	InsertPrepend
+
+	// InsertAtHeading places the block at the end of the section under a
+	// heading, before any blank lines that separate it from the next
+	// section.
+	InsertAtHeading
+)
+
+// InsertPosition describes where a block goes in a note.
+type InsertPosition struct {
+	Mode InsertMode
+
+	// Heading is the full heading line, such as "## Log", for
+	// InsertAtHeading. The section ends at the next heading of the same or
+	// a higher level.
+	Heading string
+}
+
+// fenceMarkers open and close fenced code blocks, whose lines are never
+// taken for headings.
+var fenceMarkers = []string{"```", "~~~"}
+
+// maxHeadingLevel is the deepest Markdown heading level.
+const maxHeadingLevel = 6
+
+// InsertBlock returns content with block inserted at position. The
+// frontmatter block of content is left untouched, and block is given a
+// trailing newline when it lacks one. Inserting under a heading the note
+// does not have is an error.
+func InsertBlock(
+	content string,
+	block string,
+	position InsertPosition,
+) (string, error) {
+	if block != "" && !strings.HasSuffix(block, "\n") {
+		block += "\n"
+	}
+
+	head, body, err := splitFrontmatter(content)
+	if err != nil {
+		return "", err
+	}
+	if head != "" && !strings.HasSuffix(head, "\n") {
+		head += "\n"
+	}
+
+	switch position.Mode {
+	case InsertAppend:
+		return head + withTrailingNewline(body) + block, nil
+	case InsertPrepend:
+		return head + block + body, nil
+	case InsertAtHeading:
+		lines := strings.SplitAfter(body, "\n")
+		at, err := sectionEnd(lines, position.Heading)
+		if err != nil {
+			return "", err
+		}
+		before := strings.Join(lines[:at], "")
+		after := strings.Join(lines[at:], "")
+		return head + withTrailingNewline(before) + block + after, nil
+	default:
+		return "", fmt.Errorf("unknown insert mode %d", position.Mode)
+	}
+}
+
+// splitFrontmatter splits content into its frontmatter block, delimiters
+// included, and the body that follows it. The frontmatter is empty when
+// content has none.
+func splitFrontmatter(content string) (string, string, error) {
+	lines := strings.SplitAfter(content, "\n")
+	if strings.TrimRight(lines[0], "\r\n") != frontmatterDelimiter {
+		return "", content, nil
+	}
+
+	offset := len(lines[0])
+	for _, line := range lines[1:] {
+		offset += len(line)
+		if strings.TrimRight(line, "\r\n") == frontmatterDelimiter {
+			return content[:offset], content[offset:], nil
+		}
+	}
+	return "", "", fmt.Errorf(
+		"frontmatter block is not closed with %s",
+		frontmatterDelimiter,
+	)
+}
+
+// sectionEnd returns the index of the line before which a block is inserted
+// into the section under heading: after the last non-blank line of the
+// section, which ends at the next heading of the same or a higher level.
+func sectionEnd(lines []string, heading string) (int, error) {
+	level, text := parseHeading(heading)
+	if level == 0 {
+		return 0, fmt.Errorf("%q is not a Markdown heading", heading)
+	}
+
+	start := -1
+	inFence := false
+	for i, line := range lines {
+		if isFence(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		lineLevel, lineText := parseHeading(line)
+		switch {
+		case start < 0 && lineLevel == level && lineText == text:
+			start = i
+		case start >= 0 && lineLevel > 0 && lineLevel <= level:
+			return trimBlankLines(lines, start+1, i), nil
+		}
+	}
+
+	if start < 0 {
+		return 0, fmt.Errorf("heading %q not found in note", heading)
+	}
+	return trimBlankLines(lines, start+1, len(lines)), nil
+}
+
+// trimBlankLines moves end back over blank lines, but not before start.
+func trimBlankLines(lines []string, start, end int) int {
+	for end > start && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	return end
+}
+
+// parseHeading returns the level and text of an ATX heading line, or a level
+// of 0 when line is not a heading.
+func parseHeading(line string) (int, string) {
+	line = strings.TrimRight(line, " \t\r\n")
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > maxHeadingLevel {
+		return 0, ""
+	}
+
+	rest := line[level:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return 0, ""
+	}
+	return level, strings.TrimSpace(rest)
+}
+
+// isFence reports whether line opens or closes a fenced code block.
+func isFence(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	for _, marker := range fenceMarkers {
+		if strings.HasPrefix(trimmed, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// withTrailingNewline returns text ending in a newline, leaving empty text
+// empty.
+func withTrailingNewline(text string) string {
+	if text == "" || strings.HasSuffix(text, "\n") {
+		return text
+	}
+	return text + "\n"
+}
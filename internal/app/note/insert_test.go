@@ -0,0 +1,138 @@
+package note
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInsertBlock(t *testing.T) {
+	const daily = "---\ntags: [daily]\n---\n# Today\n\n" +
+		"## Log\n- 09:00 start\n\n## Tasks\n- [ ] write\n"
+
+	tests := []struct {
+		name     string
+		content  string
+		block    string
+		position InsertPosition
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "append adds a trailing newline",
+			content:  "# Note\nbody",
+			block:    "- entry",
+			position: InsertPosition{Mode: InsertAppend},
+			want:     "# Note\nbody\n- entry\n",
+		},
+		{
+			name:     "append to frontmatter only",
+			content:  "---\na: 1\n---",
+			block:    "body\n",
+			position: InsertPosition{Mode: InsertAppend},
+			want:     "---\na: 1\n---\nbody\n",
+		},
+		{
+			name:     "prepend after frontmatter",
+			content:  daily,
+			block:    "> pinned\n",
+			position: InsertPosition{Mode: InsertPrepend},
+			want: "---\ntags: [daily]\n---\n> pinned\n# Today\n\n" +
+				"## Log\n- 09:00 start\n\n## Tasks\n- [ ] write\n",
+		},
+		{
+			name:     "prepend without frontmatter",
+			content:  "# Note\n",
+			block:    "> pinned\n",
+			position: InsertPosition{Mode: InsertPrepend},
+			want:     "> pinned\n# Note\n",
+		},
+		{
+			name:    "at heading before the next section",
+			content: daily,
+			block:   "- 10:00 review\n",
+			position: InsertPosition{
+				Mode:    InsertAtHeading,
+				Heading: "## Log",
+			},
+			want: "---\ntags: [daily]\n---\n# Today\n\n## Log\n" +
+				"- 09:00 start\n- 10:00 review\n\n## Tasks\n- [ ] write\n",
+		},
+		{
+			name:    "at last heading",
+			content: daily,
+			block:   "- [ ] ship",
+			position: InsertPosition{
+				Mode:    InsertAtHeading,
+				Heading: "## Tasks",
+			},
+			want: daily + "- [ ] ship\n",
+		},
+		{
+			name:    "section spans deeper headings",
+			content: "## Log\n### Morning\n- a\n## Next\n",
+			block:   "- b\n",
+			position: InsertPosition{
+				Mode:    InsertAtHeading,
+				Heading: "## Log",
+			},
+			want: "## Log\n### Morning\n- a\n- b\n## Next\n",
+		},
+		{
+			name:    "headings in code fences are ignored",
+			content: "```\n## Log\n```\n## Log\n",
+			block:   "- a\n",
+			position: InsertPosition{
+				Mode:    InsertAtHeading,
+				Heading: "## Log",
+			},
+			want: "```\n## Log\n```\n## Log\n- a\n",
+		},
+		{
+			name:    "heading of another level does not match",
+			content: "### Log\n",
+			block:   "- a\n",
+			position: InsertPosition{
+				Mode:    InsertAtHeading,
+				Heading: "## Log",
+			},
+			wantErr: `heading "## Log" not found`,
+		},
+		{
+			name:    "heading argument must be a heading",
+			content: "## Log\n",
+			block:   "- a\n",
+			position: InsertPosition{
+				Mode:    InsertAtHeading,
+				Heading: "Log",
+			},
+			wantErr: "not a Markdown heading",
+		},
+		{
+			name:     "unclosed frontmatter",
+			content:  "---\na: 1\n",
+			block:    "- a\n",
+			position: InsertPosition{Mode: InsertAppend},
+			wantErr:  "not closed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := InsertBlock(tt.content, tt.block, tt.position)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("InsertBlock() error = %v, want %q",
+						err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("InsertBlock() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("InsertBlock() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Package note provides domain services for creating notes in the vault.
+// This file contains the provenance stamp that records which template a note
+// was generated from.
+package note
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JackMatanky/lithos/internal/domain"
+	"go.yaml.in/yaml/v3"
+)
+
+// Frontmatter keys of the provenance stamp.
+const (
+	ProvenanceTemplateKey = "lithos_template"
+	ProvenanceHashKey     = "lithos_template_hash"
+	ProvenanceVersionKey  = "lithos_version"
+)
+
+// Provenance records the template a note was generated from, so that notes
+// can be found again once their template changes.
+type Provenance struct {
+	Template string // Template ID, e.g. "work/meeting"
+	Hash     string // Hex SHA-256 of the template source at generation
+	Version  string // Version of lithos that generated the note
+}
+
+// fields returns the stamp as frontmatter keys and values, in the order they
+// are written.
+func (p Provenance) fields() [][2]string {
+	return [][2]string{
+		{ProvenanceTemplateKey, p.Template},
+		{ProvenanceHashKey, p.Hash},
+		{ProvenanceVersionKey, p.Version},
+	}
+}
+
+// IsProvenanceKey reports whether key is one of the frontmatter keys of the
+// provenance stamp.
+func IsProvenanceKey(key string) bool {
+	switch key {
+	case ProvenanceTemplateKey, ProvenanceHashKey, ProvenanceVersionKey:
+		return true
+	default:
+		return false
+	}
+}
+
+// ReadProvenance returns the provenance stamp in fm. It reports false when
+// the note carries no stamp.
+func ReadProvenance(fm domain.Frontmatter) (Provenance, bool) {
+	text := func(key string) string {
+		value, _ := fm.Fields[key].(string)
+		return value
+	}
+	p := Provenance{
+		Template: text(ProvenanceTemplateKey),
+		Hash:     text(ProvenanceHashKey),
+		Version:  text(ProvenanceVersionKey),
+	}
+	return p, p.Template != ""
+}
+
+// StampProvenance adds the keys of p to the end of the frontmatter block of
+// content, replacing any earlier stamp. A note without frontmatter gets a
+// block holding just the stamp. The rest of the note is left as it is.
+func StampProvenance(content string, p Provenance) (string, error) {
+	frontmatter, body, err := splitFrontmatter(content)
+	if err != nil {
+		return "", err
+	}
+
+	var stamp strings.Builder
+	for _, field := range p.fields() {
+		value, err := yaml.Marshal(field[1])
+		if err != nil {
+			return "", fmt.Errorf("failed to encode %s: %w", field[0], err)
+		}
+		stamp.WriteString(field[0] + ": " + string(value))
+	}
+
+	if frontmatter == "" {
+		return frontmatterDelimiter + "\n" + stamp.String() +
+			frontmatterDelimiter + "\n" + body, nil
+	}
+
+	lines := strings.SplitAfter(frontmatter, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	closing := lines[len(lines)-1]
+	if !strings.HasSuffix(closing, "\n") {
+		closing += "\n"
+	}
+
+	var stamped strings.Builder
+	stamped.WriteString(lines[0])
+	for _, line := range lines[1 : len(lines)-1] {
+		key, _, _ := strings.Cut(line, ":")
+		if IsProvenanceKey(key) {
+			continue
+		}
+		stamped.WriteString(line)
+	}
+	stamped.WriteString(stamp.String())
+	stamped.WriteString(closing)
+	return stamped.String() + body, nil
+}
@@ -0,0 +1,74 @@
+package note
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStampProvenance(t *testing.T) {
+	p := Provenance{Template: "work/meeting", Hash: "12e4", Version: "0.1.0"}
+	const stamp = "lithos_template: work/meeting\n" +
+		"lithos_template_hash: \"12e4\"\n" +
+		"lithos_version: 0.1.0\n"
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "appended to the frontmatter",
+			content: "---\ntitle: Sync\n---\n# Sync\n",
+			want:    "---\ntitle: Sync\n" + stamp + "---\n# Sync\n",
+		},
+		{
+			name: "earlier stamp is replaced",
+			content: "---\nlithos_template: old\ntitle: Sync\n" +
+				"lithos_version: 0.0.1\n---\n# Sync\n",
+			want: "---\ntitle: Sync\n" + stamp + "---\n# Sync\n",
+		},
+		{
+			name:    "unterminated closing delimiter",
+			content: "---\ntitle: Sync\n---",
+			want:    "---\ntitle: Sync\n" + stamp + "---\n",
+		},
+		{
+			name:    "note without frontmatter",
+			content: "# Sync\n",
+			want:    "---\n" + stamp + "---\n# Sync\n",
+		},
+		{
+			name:    "unclosed frontmatter",
+			content: "---\ntitle: Sync\n",
+			wantErr: "not closed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := StampProvenance(tt.content, p)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("StampProvenance() error = %v, want %q",
+						err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("StampProvenance() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("StampProvenance() = %q, want %q", got, tt.want)
+			}
+
+			fm, _, err := ParseFrontmatter(got)
+			if err != nil {
+				t.Fatalf("ParseFrontmatter() unexpected error = %v", err)
+			}
+			if read, ok := ReadProvenance(fm); !ok || read != p {
+				t.Errorf("ReadProvenance() = %+v, %v, want %+v", read, ok, p)
+			}
+		})
+	}
+}
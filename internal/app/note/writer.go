@@ -0,0 +1,195 @@
+// Package note provides domain services for creating notes in the vault.
+// This package owns the rules for where a rendered note may be written,
+// including how collisions with existing files are resolved.
+package note
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+	"github.com/JackMatanky/lithos/internal/shared/clock"
+	"github.com/JackMatanky/lithos/internal/shared/errors"
+)
+
+// maxUniqueAttempts bounds the search for a free suffixed path.
+const maxUniqueAttempts = 1000
+
+// timestampSuffixLayout is the time layout used for timestamp suffixes.
+const timestampSuffixLayout = "20060102-150405"
+
+// CollisionPolicy decides what happens when a note targets a path that
+// already exists.
+type CollisionPolicy int
+
+const (
+	// CollisionFail refuses to write and returns an error wrapping
+	// fs.ErrExist. This is the default policy.
+	CollisionFail CollisionPolicy = iota
+
+	// CollisionOverwrite replaces the existing file.
+	CollisionOverwrite
+
+	// CollisionUniqueCounter appends an incrementing suffix ("-1", "-2", ...)
+	// until a free path is found.
+	CollisionUniqueCounter
+
+	// CollisionUniqueTimestamp appends a "-YYYYMMDD-HHMMSS" suffix, falling
+	// back to an additional counter if that path is also taken.
+	CollisionUniqueTimestamp
+)
+
+// Writer writes rendered notes through FileSystemPort while enforcing a
+// CollisionPolicy. The existence check lives here rather than in the CLI so
+// every command creating notes shares the same rules.
+type Writer struct {
+	fileSystemPort spi.FileSystemPort
+	clock          clock.Clock
+}
+
+// NewWriter creates a Writer backed by the given filesystem port that stamps
+// timestamp suffixes with the system time.
+func NewWriter(fileSystemPort spi.FileSystemPort) *Writer {
+	return NewWriterWithClock(fileSystemPort, clock.System())
+}
+
+// NewWriterWithClock creates a Writer whose timestamp suffixes use clk, so a
+// pinned render time also applies to collision suffixes.
+func NewWriterWithClock(
+	fileSystemPort spi.FileSystemPort,
+	clk clock.Clock,
+) *Writer {
+	return &Writer{
+		fileSystemPort: fileSystemPort,
+		clock:          clk,
+	}
+}
+
+// Write writes content to path according to policy and returns the path that
+// was actually written, which differs from path when a unique suffix was
+// applied. With CollisionFail, an existing target yields a ResourceError
+// wrapping fs.ErrExist. Context cancellation is checked before any I/O.
+func (w *Writer) Write(
+	ctx context.Context,
+	path string,
+	content []byte,
+	policy CollisionPolicy,
+) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	target, err := w.ResolveTarget(path, policy)
+	if err != nil {
+		return "", err
+	}
+
+	writeErr := w.fileSystemPort.WriteFileAtomic(target, content)
+	if writeErr != nil {
+		return "", errors.NewResourceError("note", "write", target, writeErr)
+	}
+
+	return target, nil
+}
+
+// ResolveTarget applies policy to path without writing anything and returns
+// the path a subsequent write should use.
+func (w *Writer) ResolveTarget(
+	path string,
+	policy CollisionPolicy,
+) (string, error) {
+	exists, err := w.exists(path)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return path, nil
+	}
+
+	switch policy {
+	case CollisionOverwrite:
+		return path, nil
+	case CollisionUniqueCounter:
+		return w.uniqueCounterPath(path)
+	case CollisionUniqueTimestamp:
+		return w.uniqueTimestampPath(path)
+	default:
+		return "", errors.NewResourceError(
+			"note",
+			"create",
+			path,
+			fs.ErrExist,
+		)
+	}
+}
+
+// exists reports whether anything exists at path. Errors other than
+// "not found" are propagated.
+func (w *Writer) exists(path string) (bool, error) {
+	_, err := w.fileSystemPort.Stat(path)
+	if err == nil {
+		return true, nil
+	}
+	if stderrors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return false, errors.NewResourceError("note", "stat", path, err)
+}
+
+// uniqueCounterPath returns the first free "<base>-N<ext>" path.
+func (w *Writer) uniqueCounterPath(path string) (string, error) {
+	base, ext := splitExt(path)
+	for i := 1; i <= maxUniqueAttempts; i++ {
+		candidate := fmt.Sprintf("%s-%d%s", base, i, ext)
+		exists, err := w.exists(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+	}
+
+	return "", errors.NewResourceError(
+		"note",
+		"create",
+		path,
+		fmt.Errorf(
+			"no free name after %d attempts: %w",
+			maxUniqueAttempts,
+			fs.ErrExist,
+		),
+	)
+}
+
+// uniqueTimestampPath returns "<base>-<timestamp><ext>", adding a counter when
+// that path is also taken.
+func (w *Writer) uniqueTimestampPath(path string) (string, error) {
+	base, ext := splitExt(path)
+	candidate := fmt.Sprintf(
+		"%s-%s%s",
+		base,
+		w.clock.Now().Format(timestampSuffixLayout),
+		ext,
+	)
+
+	exists, err := w.exists(candidate)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return candidate, nil
+	}
+	return w.uniqueCounterPath(candidate)
+}
+
+// splitExt splits path into everything before the extension and the
+// extension itself ("notes/a.md" → "notes/a", ".md").
+func splitExt(path string) (string, string) {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext), ext
+}
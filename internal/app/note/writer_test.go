@@ -0,0 +1,143 @@
+package note
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+	"github.com/JackMatanky/lithos/internal/shared/clock"
+	testutils "github.com/JackMatanky/lithos/tests/utils"
+)
+
+func newTestWriter(
+	existing ...string,
+) (*Writer, *testutils.MockFileSystemPort) {
+	mockFS := testutils.NewMockFileSystemPort()
+	for _, path := range existing {
+		mockFS.AddFile(path, []byte("existing"))
+	}
+
+	writer := NewWriterWithClock(
+		mockFS,
+		clock.Fixed(time.Date(2025, 3, 14, 9, 26, 53, 0, time.UTC)),
+	)
+	return writer, mockFS
+}
+
+func TestWriter_Write(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  []string
+		policy    CollisionPolicy
+		want      string
+		wantExist bool
+	}{
+		{
+			name:   "new file with fail policy",
+			policy: CollisionFail,
+			want:   "notes/a.md",
+		},
+		{
+			name:      "existing file with fail policy",
+			existing:  []string{"notes/a.md"},
+			policy:    CollisionFail,
+			wantExist: true,
+		},
+		{
+			name:     "existing file with overwrite policy",
+			existing: []string{"notes/a.md"},
+			policy:   CollisionOverwrite,
+			want:     "notes/a.md",
+		},
+		{
+			name:     "existing file with counter policy",
+			existing: []string{"notes/a.md", "notes/a-1.md"},
+			policy:   CollisionUniqueCounter,
+			want:     "notes/a-2.md",
+		},
+		{
+			name:     "existing file with timestamp policy",
+			existing: []string{"notes/a.md"},
+			policy:   CollisionUniqueTimestamp,
+			want:     "notes/a-20250314-092653.md",
+		},
+		{
+			name: "timestamp collision falls back to counter",
+			existing: []string{
+				"notes/a.md",
+				"notes/a-20250314-092653.md",
+			},
+			policy: CollisionUniqueTimestamp,
+			want:   "notes/a-20250314-092653-1.md",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer, mockFS := newTestWriter(tt.existing...)
+
+			got, err := writer.Write(
+				context.Background(),
+				"notes/a.md",
+				[]byte("new"),
+				tt.policy,
+			)
+
+			if tt.wantExist {
+				if !errors.Is(err, fs.ErrExist) {
+					t.Fatalf("Write() error = %v, want fs.ErrExist", err)
+				}
+				existing := string(mockFS.GetWrittenFiles()["notes/a.md"])
+				if existing != "existing" {
+					t.Error("Write() must not modify the existing file")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Write() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Write() path = %q, want %q", got, tt.want)
+			}
+			if string(mockFS.GetWrittenFiles()[tt.want]) != "new" {
+				t.Errorf("Write() did not write content to %q", tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_Write_StatError(t *testing.T) {
+	writer, mockFS := newTestWriter()
+	statErr := errors.New("permission denied")
+	mockFS.SetStatFunc(func(path string) (spi.FileInfo, error) {
+		return spi.FileInfo{}, statErr
+	})
+
+	_, err := writer.Write(
+		context.Background(),
+		"notes/a.md",
+		[]byte("new"),
+		CollisionOverwrite,
+	)
+	if !errors.Is(err, statErr) {
+		t.Errorf("Write() error = %v, want wrapping %v", err, statErr)
+	}
+}
+
+func TestWriter_Write_Canceled(t *testing.T) {
+	writer, mockFS := newTestWriter()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := writer.Write(ctx, "notes/a.md", []byte("new"), CollisionFail)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Write() error = %v, want context.Canceled", err)
+	}
+	if len(mockFS.GetWrittenFiles()) != 0 {
+		t.Error("Write() must not write after cancellation")
+	}
+}
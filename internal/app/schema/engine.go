@@ -150,6 +150,26 @@ func (e *SchemaEngine) LoadPropertyBank(
 	return lithoserrors.Ok[*domain.PropertyBank](propertyBank)
 }
 
+// LoadRegistry loads the schemas served by GetSchema when the registry loads
+// them on demand (see spi.SchemaRegistryLoaderPort). Registries that are
+// populated up front need no loading, so for them it does nothing.
+func (e *SchemaEngine) LoadRegistry(
+	ctx context.Context,
+) lithoserrors.Result[struct{}] {
+	// Check for context cancellation early
+	select {
+	case <-ctx.Done():
+		return lithoserrors.Err[struct{}](ctx.Err())
+	default:
+	}
+
+	loader, ok := e.registry.(spi.SchemaRegistryLoaderPort)
+	if !ok {
+		return lithoserrors.Ok(struct{}{})
+	}
+	return loader.Initialize(ctx)
+}
+
 // GetSchema retrieves a validated schema by name.
 // Returns Result[Schema] with the resolved schema.
 // Schema must be pre-validated by SchemaEngine before retrieval.
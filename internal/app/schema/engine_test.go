@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/JackMatanky/lithos/internal/domain"
+	"github.com/JackMatanky/lithos/internal/ports/spi"
 	lithoserrors "github.com/JackMatanky/lithos/internal/shared/errors"
 )
 
@@ -262,6 +263,78 @@ func TestSchemaEngine_LoadPropertyBank(t *testing.T) {
 	}
 }
 
+// mockSchemaRegistryLoaderPort implements SchemaRegistryLoaderPort for
+// testing, populating its schemas on Initialize.
+type mockSchemaRegistryLoaderPort struct {
+	mockSchemaRegistryPort
+	pending map[string]domain.Schema
+	initErr error
+}
+
+func (m *mockSchemaRegistryLoaderPort) Initialize(
+	ctx context.Context,
+) lithoserrors.Result[struct{}] {
+	if m.initErr != nil {
+		return lithoserrors.Err[struct{}](m.initErr)
+	}
+	m.schemas = m.pending
+	return lithoserrors.Ok(struct{}{})
+}
+
+func TestSchemaEngine_LoadRegistry(t *testing.T) {
+	testSchema := domain.Schema{Name: "test", Properties: []domain.Property{}}
+
+	tests := []struct {
+		name      string
+		registry  spi.SchemaRegistryPort
+		expectErr bool
+	}{
+		{
+			name: "registry loaded on demand",
+			registry: &mockSchemaRegistryLoaderPort{
+				pending: map[string]domain.Schema{"test": testSchema},
+			},
+		},
+		{
+			name: "registry populated up front",
+			registry: &mockSchemaRegistryPort{
+				schemas: map[string]domain.Schema{"test": testSchema},
+			},
+		},
+		{
+			name: "loading fails",
+			registry: &mockSchemaRegistryLoaderPort{
+				initErr: errors.New("schemas directory missing"),
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewSchemaEngine(
+				&mockSchemaLoaderPort{},
+				tt.registry,
+				NewSchemaValidator(),
+			)
+
+			result := engine.LoadRegistry(context.Background())
+			if tt.expectErr {
+				if !result.IsErr() {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if result.IsErr() {
+				t.Fatalf("unexpected error: %v", result.Error())
+			}
+
+			schema := engine.GetSchema(context.Background(), "test")
+			assertSchemaResult(t, schema, false)
+		})
+	}
+}
+
 func TestSchemaEngine_GetSchema(t *testing.T) {
 	testSchema := domain.Schema{Name: "test", Properties: []domain.Property{}}
 
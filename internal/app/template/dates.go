@@ -0,0 +1,364 @@
+// Package template provides domain services for template parsing and execution.
+// This file contains the date functions available to templates. Every function
+// works in the time zone the func map was created with.
+package template
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JackMatanky/lithos/internal/shared/clock"
+)
+
+// defaultNowLayout is the layout used by now when none is given.
+const defaultNowLayout = "2006-01-02 15:04:05"
+
+// dateInputLayouts lists the textual forms accepted wherever a template
+// function expects a date.
+var dateInputLayouts = []string{
+	"2006-01-02",
+	time.RFC3339,
+	defaultNowLayout,
+	"2006-01-02T15:04",
+}
+
+// offsetPattern matches date offsets such as "7d", "-1w", or "1d12h".
+var offsetPattern = regexp.MustCompile(`^[+-]?(?:\d+[yMwdhms])+$`)
+
+// offsetComponent matches a single component of a date offset.
+var offsetComponent = regexp.MustCompile(`(\d+)([yMwdhms])`)
+
+// weekdays maps full and abbreviated English weekday names to time.Weekday.
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// dateFuncs implements the date template functions for a fixed time zone.
+type dateFuncs struct {
+	location *time.Location
+	clock    clock.Clock
+}
+
+// newDateFuncs creates date functions working in location, or in time.Local
+// when location is nil, and reading the current time from clk, or from the
+// system clock when clk is nil.
+func newDateFuncs(location *time.Location, clk clock.Clock) dateFuncs {
+	if location == nil {
+		location = time.Local
+	}
+	if clk == nil {
+		clk = clock.System()
+	}
+	return dateFuncs{location: location, clock: clk}
+}
+
+// current returns the current time in the configured time zone.
+func (d dateFuncs) current() time.Time {
+	return d.clock.Now().In(d.location)
+}
+
+// now returns the current time formatted according to the provided layout.
+// If layout is empty, it uses "2006-01-02 15:04:05" format.
+// Uses Go's reference time format: "2006-01-02 15:04:05" (Mon Jan 2 15:04:05
+// -0700 MST 2006).
+func (d dateFuncs) now(layout string) string {
+	if layout == "" {
+		layout = defaultNowLayout
+	}
+	return d.current().Format(layout)
+}
+
+// today returns midnight at the start of the current day.
+func (d dateFuncs) today() time.Time {
+	return startOfDay(d.current())
+}
+
+// dateAdd shifts a date by offset, a signed sequence of amounts with units
+// y (years), M (months), w (weeks), d (days), h (hours), m (minutes), and
+// s (seconds), e.g. "7d", "-1w", or "1d12h".
+func (d dateFuncs) dateAdd(
+	offset string,
+	value interface{},
+) (time.Time, error) {
+	t, err := d.toTime(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return addOffset(t, offset)
+}
+
+// dateParse parses value with a Go reference layout in the configured time
+// zone.
+func (d dateFuncs) dateParse(layout, value string) (time.Time, error) {
+	t, err := time.ParseInLocation(layout, value, d.location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("dateParse: %w", err)
+	}
+	return t, nil
+}
+
+// dateFormat formats a date with a Go reference layout.
+func (d dateFuncs) dateFormat(
+	layout string,
+	value interface{},
+) (string, error) {
+	t, err := d.toTime(value)
+	if err != nil {
+		return "", err
+	}
+	return t.Format(layout), nil
+}
+
+// startOfWeek returns midnight on the Monday of the ISO week containing the
+// date.
+func (d dateFuncs) startOfWeek(value interface{}) (time.Time, error) {
+	t, err := d.toTime(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return weekStart(t), nil
+}
+
+// weekNumber returns the ISO 8601 week number (1-53) of the date.
+func (d dateFuncs) weekNumber(value interface{}) (int, error) {
+	t, err := d.toTime(value)
+	if err != nil {
+		return 0, err
+	}
+	_, week := t.ISOWeek()
+	return week, nil
+}
+
+// isoWeek returns the ISO 8601 week of the date as "YYYY-Www", e.g.
+// "2025-W03". The year is the ISO week-numbering year, which differs from the
+// calendar year around New Year.
+func (d dateFuncs) isoWeek(value interface{}) (string, error) {
+	t, err := d.toTime(value)
+	if err != nil {
+		return "", err
+	}
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%04d-W%02d", year, week), nil
+}
+
+// weekday returns the English name of the date's day of the week.
+func (d dateFuncs) weekday(value interface{}) (string, error) {
+	t, err := d.toTime(value)
+	if err != nil {
+		return "", err
+	}
+	return t.Weekday().String(), nil
+}
+
+// relativeDate resolves an English phrase relative to the current day:
+// "now", "today", "tomorrow", "yesterday", "next|last|this <weekday>",
+// "next|last|this week|month|year", "in N days|weeks|months|years", and
+// "N days|weeks|months|years ago". Day-level phrases resolve to midnight;
+// week, month, and year phrases resolve to the start of that period.
+func (d dateFuncs) relativeDate(phrase string) (time.Time, error) {
+	normalized := strings.Join(strings.Fields(strings.ToLower(phrase)), " ")
+	today := d.today()
+
+	switch normalized {
+	case "now":
+		return d.current(), nil
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	words := strings.Fields(normalized)
+	switch {
+	case len(words) == 2 && isRelativeDirection(words[0]):
+		if t, ok := relativeToDirection(today, words[0], words[1]); ok {
+			return t, nil
+		}
+	case len(words) == 3 && words[0] == "in":
+		if t, ok := relativeByAmount(today, words[1], words[2], 1); ok {
+			return t, nil
+		}
+	case len(words) == 3 && words[2] == "ago":
+		if t, ok := relativeByAmount(today, words[0], words[1], -1); ok {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf(
+		"relativeDate: unrecognised phrase %q",
+		phrase,
+	)
+}
+
+// toTime converts a template value to a time. Time values keep their own
+// zone; strings are parsed in the configured time zone.
+func (d dateFuncs) toTime(value interface{}) (time.Time, error) {
+	switch typed := value.(type) {
+	case time.Time:
+		return typed, nil
+	case string:
+		text := strings.TrimSpace(typed)
+		for _, layout := range dateInputLayouts {
+			t, err := time.ParseInLocation(layout, text, d.location)
+			if err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("cannot parse %q as a date", typed)
+	default:
+		return time.Time{}, fmt.Errorf("expected a date, got %T", value)
+	}
+}
+
+// addOffset applies a date offset such as "-1w" or "1d12h" to t.
+func addOffset(t time.Time, offset string) (time.Time, error) {
+	if !offsetPattern.MatchString(offset) {
+		return time.Time{}, fmt.Errorf(
+			`dateAdd: invalid offset %q (expected e.g. "7d", "-1w", "2h30m")`,
+			offset,
+		)
+	}
+
+	sign := 1
+	if strings.HasPrefix(offset, "-") {
+		sign = -1
+	}
+
+	for _, match := range offsetComponent.FindAllStringSubmatch(offset, -1) {
+		amount, err := strconv.Atoi(match[1])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("dateAdd: %w", err)
+		}
+		amount *= sign
+
+		switch match[2] {
+		case "y":
+			t = t.AddDate(amount, 0, 0)
+		case "M":
+			t = t.AddDate(0, amount, 0)
+		case "w":
+			t = t.AddDate(0, 0, 7*amount)
+		case "d":
+			t = t.AddDate(0, 0, amount)
+		case "h":
+			t = t.Add(time.Duration(amount) * time.Hour)
+		case "m":
+			t = t.Add(time.Duration(amount) * time.Minute)
+		case "s":
+			t = t.Add(time.Duration(amount) * time.Second)
+		}
+	}
+	return t, nil
+}
+
+// isRelativeDirection reports whether word starts a "next/last/this" phrase.
+func isRelativeDirection(word string) bool {
+	return word == "next" || word == "last" || word == "this"
+}
+
+// relativeToDirection resolves "next|last|this" followed by a weekday or by
+// "week", "month", or "year".
+func relativeToDirection(
+	today time.Time,
+	direction, unit string,
+) (time.Time, bool) {
+	step := map[string]int{"next": 1, "last": -1, "this": 0}[direction]
+
+	if day, ok := weekdays[unit]; ok {
+		return relativeWeekday(today, direction, day), true
+	}
+
+	switch unit {
+	case "week":
+		return weekStart(today).AddDate(0, 0, 7*step), true
+	case "month":
+		first := time.Date(
+			today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location(),
+		)
+		return first.AddDate(0, step, 0), true
+	case "year":
+		first := time.Date(today.Year(), 1, 1, 0, 0, 0, 0, today.Location())
+		return first.AddDate(step, 0, 0), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// relativeWeekday returns the next (strictly after today), last (strictly
+// before today), or this (within the current ISO week) occurrence of day.
+func relativeWeekday(
+	today time.Time,
+	direction string,
+	day time.Weekday,
+) time.Time {
+	switch direction {
+	case "next":
+		diff := (int(day) - int(today.Weekday()) + 7) % 7
+		if diff == 0 {
+			diff = 7
+		}
+		return today.AddDate(0, 0, diff)
+	case "last":
+		diff := (int(today.Weekday()) - int(day) + 7) % 7
+		if diff == 0 {
+			diff = 7
+		}
+		return today.AddDate(0, 0, -diff)
+	default:
+		return weekStart(today).AddDate(0, 0, isoWeekdayIndex(day))
+	}
+}
+
+// relativeByAmount resolves "N days|weeks|months|years" in direction sign.
+func relativeByAmount(
+	today time.Time,
+	amountText, unit string,
+	sign int,
+) (time.Time, bool) {
+	amount, err := strconv.Atoi(amountText)
+	if err != nil || amount < 0 {
+		return time.Time{}, false
+	}
+	amount *= sign
+
+	switch strings.TrimSuffix(unit, "s") {
+	case "day":
+		return today.AddDate(0, 0, amount), true
+	case "week":
+		return today.AddDate(0, 0, 7*amount), true
+	case "month":
+		return today.AddDate(0, amount, 0), true
+	case "year":
+		return today.AddDate(amount, 0, 0), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// startOfDay returns midnight at the start of t's day in t's zone.
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// weekStart returns midnight on the Monday of t's ISO week.
+func weekStart(t time.Time) time.Time {
+	return startOfDay(t).AddDate(0, 0, -isoWeekdayIndex(t.Weekday()))
+}
+
+// isoWeekdayIndex returns the zero-based position of day in an ISO week,
+// which starts on Monday.
+func isoWeekdayIndex(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
@@ -0,0 +1,285 @@
+package template
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JackMatanky/lithos/internal/shared/clock"
+)
+
+func TestDateFuncs(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+	dates := newDateFuncs(berlin, nil)
+	// Friday, 14 March 2025 in Berlin
+	friday := time.Date(2025, 3, 14, 18, 30, 0, 0, berlin)
+
+	tests := []struct {
+		name    string
+		call    func() (interface{}, error)
+		want    interface{}
+		wantErr string
+	}{
+		{
+			name: "dateAdd days",
+			call: func() (interface{}, error) {
+				return dates.dateAdd("7d", friday)
+			},
+			want: time.Date(2025, 3, 21, 18, 30, 0, 0, berlin),
+		},
+		{
+			name: "dateAdd negative weeks",
+			call: func() (interface{}, error) {
+				return dates.dateAdd("-1w", friday)
+			},
+			want: time.Date(2025, 3, 7, 18, 30, 0, 0, berlin),
+		},
+		{
+			name: "dateAdd months and compound units",
+			call: func() (interface{}, error) {
+				return dates.dateAdd("1M1d2h30m", friday)
+			},
+			want: time.Date(2025, 4, 15, 21, 0, 0, 0, berlin),
+		},
+		{
+			name: "dateAdd parses string dates in the configured zone",
+			call: func() (interface{}, error) {
+				return dates.dateAdd("1y", "2024-02-29")
+			},
+			want: time.Date(2025, 3, 1, 0, 0, 0, 0, berlin),
+		},
+		{
+			name: "dateAdd invalid offset",
+			call: func() (interface{}, error) {
+				return dates.dateAdd("7 days", friday)
+			},
+			wantErr: "invalid offset",
+		},
+		{
+			name: "dateAdd invalid date",
+			call: func() (interface{}, error) {
+				return dates.dateAdd("1d", 42)
+			},
+			wantErr: "expected a date, got int",
+		},
+		{
+			name: "dateParse",
+			call: func() (interface{}, error) {
+				return dates.dateParse("02/01/2006", "14/03/2025")
+			},
+			want: time.Date(2025, 3, 14, 0, 0, 0, 0, berlin),
+		},
+		{
+			name: "dateParse mismatch",
+			call: func() (interface{}, error) {
+				return dates.dateParse("2006-01-02", "14/03/2025")
+			},
+			wantErr: "dateParse",
+		},
+		{
+			name: "dateFormat",
+			call: func() (interface{}, error) {
+				return dates.dateFormat("Mon 2 Jan 2006", friday)
+			},
+			want: "Fri 14 Mar 2025",
+		},
+		{
+			name: "startOfWeek",
+			call: func() (interface{}, error) {
+				return dates.startOfWeek(friday)
+			},
+			want: time.Date(2025, 3, 10, 0, 0, 0, 0, berlin),
+		},
+		{
+			name: "startOfWeek on Sunday",
+			call: func() (interface{}, error) {
+				return dates.startOfWeek("2025-03-16")
+			},
+			want: time.Date(2025, 3, 10, 0, 0, 0, 0, berlin),
+		},
+		{
+			name: "weekNumber",
+			call: func() (interface{}, error) {
+				return dates.weekNumber(friday)
+			},
+			want: 11,
+		},
+		{
+			name: "isoWeek uses the week-numbering year",
+			call: func() (interface{}, error) {
+				return dates.isoWeek("2024-12-30")
+			},
+			want: "2025-W01",
+		},
+		{
+			name: "weekday",
+			call: func() (interface{}, error) {
+				return dates.weekday(friday)
+			},
+			want: "Friday",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.call()
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error = %v", err)
+			}
+
+			if want, ok := tt.want.(time.Time); ok {
+				if !got.(time.Time).Equal(want) {
+					t.Errorf("got %v, want %v", got, want)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelativeToDirection(t *testing.T) {
+	// Wednesday, 12 March 2025
+	today := time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		phrase string
+		want   time.Time
+	}{
+		{"next monday", time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"next wednesday", time.Date(2025, 3, 19, 0, 0, 0, 0, time.UTC)},
+		{"last friday", time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)},
+		{"last wed", time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{"this sunday", time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC)},
+		{"this monday", time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{"next week", time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"last week", time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)},
+		{"this month", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"next month", time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"last year", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phrase, func(t *testing.T) {
+			words := strings.Fields(tt.phrase)
+			got, ok := relativeToDirection(today, words[0], words[1])
+			if !ok {
+				t.Fatalf("relativeToDirection(%q) not recognised", tt.phrase)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("relativeToDirection(%q) = %v, want %v",
+					tt.phrase, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelativeByAmount(t *testing.T) {
+	today := time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		amount, unit string
+		sign         int
+		want         time.Time
+		wantOK       bool
+	}{
+		{"3", "days", 1, time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC), true},
+		{"1", "week", -1, time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), true},
+		{"2", "months", 1, time.Date(2025, 5, 12, 0, 0, 0, 0, time.UTC), true},
+		{"1", "year", -1, time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC), true},
+		{"two", "days", 1, time.Time{}, false},
+		{"2", "fortnights", 1, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.amount+" "+tt.unit, func(t *testing.T) {
+			got, ok := relativeByAmount(today, tt.amount, tt.unit, tt.sign)
+			if ok != tt.wantOK {
+				t.Fatalf("relativeByAmount() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && !got.Equal(tt.want) {
+				t.Errorf("relativeByAmount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelativeDate(t *testing.T) {
+	// Friday, 14 March 2025
+	now := time.Date(2025, 3, 14, 18, 30, 0, 0, time.UTC)
+	dates := newDateFuncs(time.UTC, clock.Fixed(now))
+
+	tests := []struct {
+		phrase string
+		want   time.Time
+	}{
+		{"now", now},
+		{"  Tomorrow ", time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"yesterday", time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC)},
+		{"2 days ago", time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC)},
+		{"next monday", time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"last friday", time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)},
+		{"this week", time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{"next month", time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"in 1 year", time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phrase, func(t *testing.T) {
+			got, err := dates.relativeDate(tt.phrase)
+			if err != nil {
+				t.Fatalf("relativeDate() unexpected error = %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("relativeDate(%q) = %v, want %v", tt.phrase, got,
+					tt.want)
+			}
+		})
+	}
+
+	if _, err := dates.relativeDate("the day after"); err == nil {
+		t.Error("relativeDate() expected error for unknown phrase")
+	}
+}
+
+func TestNewFuncMapWithOptions_Clock(t *testing.T) {
+	pinned := time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC)
+	funcs := NewFuncMapWithOptions(FuncMapOptions{
+		Location: time.UTC,
+		Clock:    clock.Fixed(pinned),
+	})
+
+	now := funcs["now"].(func(string) string)("2006-01-02 15:04")
+	if now != "2024-01-15 09:30" {
+		t.Errorf("now() = %q, want %q", now, "2024-01-15 09:30")
+	}
+	today := funcs["today"].(func() time.Time)()
+	if !today.Equal(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("today() = %v, want 2024-01-15 midnight", today)
+	}
+}
+
+func TestNewFuncMapWithOptions_Location(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+	funcs := NewFuncMapWithOptions(FuncMapOptions{Location: tokyo})
+
+	today := funcs["today"].(func() time.Time)()
+	if today.Location() != tokyo {
+		t.Errorf("today() location = %v, want %v", today.Location(), tokyo)
+	}
+}
@@ -4,26 +4,57 @@ package template
 import (
 	"bytes"
 	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
 
 	"github.com/JackMatanky/lithos/internal/domain"
 	"github.com/JackMatanky/lithos/internal/ports/spi"
 	"github.com/JackMatanky/lithos/internal/shared/errors"
 )
 
+// errOutputLimit is returned by limitedWriter once the output limit is hit.
+var errOutputLimit = stderrors.New("output limit exceeded")
+
+// ExecutorOptions configures the limits GoTemplateExecutor enforces. Zero
+// values disable the corresponding limit.
+type ExecutorOptions struct {
+	// Timeout bounds how long a single render may run.
+	Timeout time.Duration
+
+	// MaxOutputSize bounds the size of the rendered output in bytes.
+	MaxOutputSize int64
+}
+
 // GoTemplateExecutor implements spi.TemplateExecutor using Go's text/template
 // package.
 // It executes templates that have been parsed with custom function maps.
-type GoTemplateExecutor struct{}
+type GoTemplateExecutor struct {
+	options ExecutorOptions
+}
 
-// NewGoTemplateExecutor creates a new GoTemplateExecutor instance.
+// NewGoTemplateExecutor creates a new GoTemplateExecutor instance without
+// render limits.
 func NewGoTemplateExecutor() *GoTemplateExecutor {
-	return &GoTemplateExecutor{}
+	return NewGoTemplateExecutorWithOptions(ExecutorOptions{})
+}
+
+// NewGoTemplateExecutorWithOptions creates a GoTemplateExecutor enforcing the
+// limits in opts.
+func NewGoTemplateExecutorWithOptions(
+	opts ExecutorOptions,
+) *GoTemplateExecutor {
+	return &GoTemplateExecutor{options: opts}
 }
 
 // Execute executes the parsed template with the provided data and returns the
-// rendered content. For MVP, the data parameter is always nil as templates
-// receive no external data. The context parameter is accepted for future
-// cancellation support but not currently used.
+// rendered content. The data value becomes the template's root object (dot).
+//
+// Execution stops when ctx is done, when the configured timeout elapses, or
+// when the output grows beyond the configured maximum size; hitting a limit
+// yields a TemplateError naming it. text/template cannot be interrupted, so a
+// stopped render that produces no further output keeps running in the
+// background until it finishes.
 func (e *GoTemplateExecutor) Execute(
 	ctx context.Context,
 	tmpl *domain.Template,
@@ -33,11 +64,13 @@ func (e *GoTemplateExecutor) Execute(
 		return errors.Err[string](err)
 	}
 
-	if err := e.validateData(data, tmpl.Name); err != nil {
-		return errors.Err[string](err)
+	if e.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.options.Timeout)
+		defer cancel()
 	}
 
-	return e.executeTemplate(tmpl)
+	return e.executeTemplate(ctx, tmpl, data)
 }
 
 // validateTemplate performs validation checks on the template before execution.
@@ -45,39 +78,105 @@ func (e *GoTemplateExecutor) validateTemplate(tmpl *domain.Template) error {
 	return validateTemplateForExecution(tmpl)
 }
 
-// validateData ensures no external data is passed for MVP.
-func (e *GoTemplateExecutor) validateData(
+// executeTemplate performs the actual template execution into a buffer,
+// returning early when ctx is done.
+func (e *GoTemplateExecutor) executeTemplate(
+	ctx context.Context,
+	tmpl *domain.Template,
 	data interface{},
-	tmplName string,
-) error {
-	if data != nil {
-		return errors.Wrap(
-			errors.NewTemplateError(
-				tmplName,
-				0,
-				"external data not supported in MVP",
-				nil,
-			),
-			"template execution must use nil data parameter",
-		)
+) errors.Result[string] {
+	if err := ctx.Err(); err != nil {
+		return errors.Err[string](e.stoppedError(tmpl, ctx))
 	}
 
-	return nil
-}
+	out := &limitedWriter{ctx: ctx, limit: e.options.MaxOutputSize}
+	done := make(chan error, 1)
+	go func() {
+		done <- tmpl.Parsed.Execute(out, data)
+	}()
 
-// executeTemplate performs the actual template execution into a buffer.
-func (e *GoTemplateExecutor) executeTemplate(
-	tmpl *domain.Template,
-) errors.Result[string] {
-	var buf bytes.Buffer
-	if err := tmpl.Parsed.Execute(&buf, nil); err != nil {
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		return errors.Err[string](e.stoppedError(tmpl, ctx))
+	}
+
+	switch {
+	case err == nil:
+		return errors.Ok(out.buf.String())
+	case stderrors.Is(err, errOutputLimit):
+		return errors.Err[string](errors.NewTemplateLimitError(
+			tmpl.Name,
+			errors.TemplateLimitOutputSize,
+			fmt.Sprintf(
+				"output exceeds the maximum size of %d bytes",
+				e.options.MaxOutputSize,
+			),
+			err,
+		))
+	case ctx.Err() != nil:
+		return errors.Err[string](e.stoppedError(tmpl, ctx))
+	default:
 		return errors.Err[string](errors.Wrap(
-			errors.NewTemplateError(tmpl.Name, 0, err.Error(), err),
+			positionedError(err, tmpl.Name, templateSources(tmpl)),
 			"template execution failed",
 		))
 	}
+}
+
+// stoppedError describes a render stopped because ctx is done: a timeout
+// names the limit that was hit, while a cancellation is reported as such.
+func (e *GoTemplateExecutor) stoppedError(
+	tmpl *domain.Template,
+	ctx context.Context,
+) error {
+	cause := ctx.Err()
+	if !stderrors.Is(cause, context.DeadlineExceeded) {
+		return errors.NewTemplateError(tmpl.Name, 0, "render canceled", cause)
+	}
 
-	return errors.Ok(buf.String())
+	reason := "render deadline exceeded"
+	if e.options.Timeout > 0 {
+		reason = fmt.Sprintf("render timeout of %s exceeded", e.options.Timeout)
+	}
+	return errors.NewTemplateLimitError(
+		tmpl.Name,
+		errors.TemplateLimitTimeout,
+		reason,
+		cause,
+	)
+}
+
+// limitedWriter buffers rendered output, failing writes once ctx is done or
+// the output would exceed limit bytes (when limit is positive). A failed
+// write aborts template execution.
+type limitedWriter struct {
+	ctx   context.Context
+	limit int64
+	buf   bytes.Buffer
+}
+
+// Write implements io.Writer.
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if w.limit > 0 && int64(w.buf.Len()+len(p)) > w.limit {
+		return 0, errOutputLimit
+	}
+	return w.buf.Write(p)
+}
+
+// templateSources maps the parse names of the template body and its partials
+// to their source text, so execution errors can quote the offending line.
+func templateSources(tmpl *domain.Template) map[string]string {
+	sources := make(map[string]string, len(tmpl.Partials)+1)
+	for _, partial := range tmpl.Partials {
+		sources[partial.Name] = partial.Content
+	}
+	sources[rootParseName] = tmpl.Content
+	return sources
 }
 
 // Ensure GoTemplateExecutor implements spi.TemplateExecutor.
@@ -0,0 +1,114 @@
+package template
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JackMatanky/lithos/internal/domain"
+	"github.com/JackMatanky/lithos/internal/shared/errors"
+)
+
+// parsedTemplate parses content into a template named "limits".
+func parsedTemplate(t *testing.T, content string) *domain.Template {
+	t.Helper()
+	parsed := NewStaticTemplateParser().Parse(t.Context(), content)
+	if parsed.IsErr() {
+		t.Fatalf("Parse() error = %v", parsed.Error())
+	}
+	return &domain.Template{
+		Name:    "limits",
+		Content: content,
+		Parsed:  parsed.Value(),
+	}
+}
+
+func TestGoTemplateExecutor_Limits(t *testing.T) {
+	// A range over a channel nobody sends on blocks without producing output.
+	blocked := make(chan string)
+	t.Cleanup(func() { close(blocked) })
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name      string
+		options   ExecutorOptions
+		ctx       context.Context
+		content   string
+		data      interface{}
+		want      string
+		wantLimit string
+		wantErr   string
+	}{
+		{
+			name:    "output within the limit",
+			options: ExecutorOptions{MaxOutputSize: 10},
+			content: "{{range .}}ab{{end}}",
+			data:    []int{1, 2, 3, 4, 5},
+			want:    "ababababab",
+		},
+		{
+			name:      "output beyond the limit",
+			options:   ExecutorOptions{MaxOutputSize: 10},
+			content:   "{{range .}}ab{{end}}",
+			data:      make([]int, 1000),
+			wantLimit: errors.TemplateLimitOutputSize,
+			wantErr:   "output exceeds the maximum size of 10 bytes",
+		},
+		{
+			name:      "timeout stops a blocked render",
+			options:   ExecutorOptions{Timeout: 20 * time.Millisecond},
+			content:   "{{range .}}{{.}}{{end}}",
+			data:      blocked,
+			wantLimit: errors.TemplateLimitTimeout,
+			wantErr:   "render timeout of 20ms exceeded",
+		},
+		{
+			name:    "canceled context",
+			ctx:     canceled,
+			content: "static",
+			wantErr: "render canceled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = t.Context()
+			}
+
+			result := NewGoTemplateExecutorWithOptions(tt.options).Execute(
+				ctx,
+				parsedTemplate(t, tt.content),
+				tt.data,
+			)
+
+			if tt.wantErr == "" {
+				if result.IsErr() {
+					t.Fatalf("Execute() error = %v", result.Error())
+				}
+				if result.Value() != tt.want {
+					t.Errorf("Execute() = %q, want %q", result.Value(), tt.want)
+				}
+				return
+			}
+
+			var templateErr errors.TemplateError
+			if !stderrors.As(result.Error(), &templateErr) {
+				t.Fatalf("Execute() error = %v, want TemplateError",
+					result.Error())
+			}
+			if !strings.Contains(templateErr.Error(), tt.wantErr) {
+				t.Errorf("error = %q, want %q", templateErr.Error(), tt.wantErr)
+			}
+			if templateErr.Limit() != tt.wantLimit {
+				t.Errorf("Limit() = %q, want %q", templateErr.Limit(),
+					tt.wantLimit)
+			}
+		})
+	}
+}
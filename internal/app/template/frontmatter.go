@@ -0,0 +1,93 @@
+// Package template provides domain services for template processing.
+// This file reads the frontmatter a template emits without rendering it.
+package template
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+
+	"github.com/JackMatanky/lithos/internal/domain"
+	"go.yaml.in/yaml/v3"
+)
+
+// ValuePlaceholder stands in for the output of value actions in the
+// frontmatter returned by TemplateFrontmatter.
+const ValuePlaceholder = "__lithos_value__"
+
+// TemplateFrontmatter parses the frontmatter block that template content
+// emits, without rendering it. Value actions are replaced by
+// ValuePlaceholder and control actions and comments by nothing, honouring
+// trim markers, so the result has the shape of the rendered block. It
+// reports false when the template emits no frontmatter.
+func TemplateFrontmatter(content string) (map[string]interface{}, bool, error) {
+	skeleton := strings.TrimLeft(templateSkeleton(content), " \t\r\n")
+	if !strings.HasPrefix(skeleton, "---\n") &&
+		!strings.HasPrefix(skeleton, "---\r\n") {
+		return nil, false, nil
+	}
+
+	lines := strings.Split(skeleton, "\n")
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], "\r") == "---" {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil, true, stderrors.New(
+			"frontmatter block is not closed with ---",
+		)
+	}
+
+	block := strings.Join(lines[1:end], "\n")
+	fields := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(block), &fields); err != nil {
+		return nil, true, fmt.Errorf("frontmatter is not valid YAML: %w", err)
+	}
+	return fields, true, nil
+}
+
+// TemplateSchema returns the name of the schema tmpl creates notes of: the
+// schema named in its header or, failing that, the fileClass its frontmatter
+// sets, which is how the schema of a rendered note is found. A fileClass
+// computed by an action cannot be known before rendering and is ignored. It
+// returns "" when tmpl names no schema.
+func TemplateSchema(tmpl *domain.Template) string {
+	if tmpl.Header.Schema != "" {
+		return tmpl.Header.Schema
+	}
+	fields, _, err := TemplateFrontmatter(tmpl.Content)
+	if err != nil {
+		return ""
+	}
+	name := domain.NewFrontmatter(fields).SchemaName()
+	if !IsStaticValue(name) {
+		return ""
+	}
+	return name
+}
+
+// IsStaticValue reports whether a value returned by TemplateFrontmatter is
+// written out in the template, rather than produced in whole or in part by
+// actions such as {{ .title }} or {{ now "2006-01-02" }}.
+func IsStaticValue(value interface{}) bool {
+	switch typed := value.(type) {
+	case string:
+		return !strings.Contains(typed, ValuePlaceholder)
+	case []interface{}:
+		for _, item := range typed {
+			if !IsStaticValue(item) {
+				return false
+			}
+		}
+	case map[string]interface{}:
+		for _, item := range typed {
+			if !IsStaticValue(item) {
+				return false
+			}
+		}
+	}
+	return true
+}
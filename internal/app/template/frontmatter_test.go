@@ -0,0 +1,92 @@
+package template
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTemplateFrontmatter(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		want      map[string]interface{}
+		wantFound bool
+		wantErr   string
+	}{
+		{
+			name: "actions become placeholders",
+			content: "{{- /* lithos\noutput: x/{{ .title | slug }}.md\n" +
+				"*/ -}}\n---\nfileClass: task\ntitle: {{ .title }}\n" +
+				"{{- if .tag }}\ntags: [{{ .tag }}, b]\n{{- end }}\n---\nbody",
+			want: map[string]interface{}{
+				"fileClass": "task",
+				"title":     ValuePlaceholder,
+				"tags":      []interface{}{ValuePlaceholder, "b"},
+			},
+			wantFound: true,
+		},
+		{
+			name:    "no frontmatter",
+			content: "# {{ .title }}\n",
+		},
+		{
+			name:      "unclosed block",
+			content:   "---\ntitle: x\n",
+			wantFound: true,
+			wantErr:   "frontmatter block is not closed with ---",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found, err := TemplateFrontmatter(tt.content)
+			if found != tt.wantFound {
+				t.Errorf("found = %v, want %v", found, tt.wantFound)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TemplateFrontmatter() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsStaticValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  bool
+	}{
+		{name: "literal", value: "open", want: true},
+		{name: "number", value: 3, want: true},
+		{name: "action", value: "[[" + ValuePlaceholder + "]]", want: false},
+		{
+			name:  "action in a list",
+			value: []interface{}{"a", ValuePlaceholder},
+			want:  false,
+		},
+		{
+			name:  "literal map",
+			value: map[string]interface{}{"a": []interface{}{"b"}},
+			want:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsStaticValue(tt.value); got != tt.want {
+				t.Errorf("IsStaticValue(%v) = %v, want %v",
+					tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,20 +2,32 @@
 package template
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
 	"strings"
 	"text/template"
 	"time"
+	"unicode"
+
+	"github.com/JackMatanky/lithos/internal/shared/clock"
 )
 
-// now returns the current time formatted according to the provided layout.
-// If layout is empty, it uses "2006-01-02 15:04:05" format.
-// Uses Go's reference time format: "2006-01-02 15:04:05" (Mon Jan 2 15:04:05
-// -0700 MST 2006).
-func now(layout string) string {
-	if layout == "" {
-		layout = "2006-01-02 15:04:05"
-	}
-	return time.Now().Format(layout)
+// FuncMapOptions configures the environment-dependent template functions.
+type FuncMapOptions struct {
+	// Location is the time zone date functions work in. Nil means time.Local.
+	Location *time.Location
+
+	// Clock supplies the current time for now, today, and relativeDate. Nil
+	// means the system clock; pass a pinned clock for reproducible renders.
+	Clock clock.Clock
+
+	// Prompter answers prompt and suggester. Nil means templates that ask
+	// questions fail to render. A TemplateEngine must share the Prompter of
+	// its parser, since the engine asks the questions renders stop at.
+	Prompter *Prompter
 }
 
 // toLower converts the input string to lowercase.
@@ -30,20 +42,330 @@ func toUpper(s string) string {
 	return strings.ToUpper(s)
 }
 
+// slug converts the input string into a lowercase, hyphen-separated form that
+// is safe to use in file names (e.g. "Q3 Planning: Draft" → "q3-planning-draft").
+// Letters and digits are kept; every other run of characters becomes a single
+// hyphen, and leading/trailing hyphens are removed.
+func slug(s string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			pendingHyphen = false
+			continue
+		}
+		pendingHyphen = true
+	}
+	return b.String()
+}
+
+// titleCase upper-cases the first letter of every whitespace-separated word
+// and leaves the remaining letters unchanged ("q3 planning" → "Q3 Planning").
+func titleCase(s string) string {
+	var b strings.Builder
+	atWordStart := true
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			atWordStart = true
+			b.WriteRune(r)
+			continue
+		}
+		if atWordStart {
+			r = unicode.ToUpper(r)
+			atWordStart = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// trim removes leading and trailing whitespace.
+func trim(s string) string {
+	return strings.TrimSpace(s)
+}
+
+// truncate shortens s to at most length characters, replacing the last kept
+// character with "…" when anything was cut. Lengths below one yield "".
+func truncate(length int, s string) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+	if length < 1 {
+		return ""
+	}
+	return string(runes[:length-1]) + "…"
+}
+
+// replace replaces every occurrence of old in s with replacement.
+func replace(old, replacement, s string) string {
+	return strings.ReplaceAll(s, old, replacement)
+}
+
+// regexReplace replaces every match of the regular expression pattern in s
+// with replacement, which may refer to capture groups as $1 or ${name}.
+func regexReplace(pattern, replacement, s string) (string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("regexReplace: %w", err)
+	}
+	return re.ReplaceAllString(s, replacement), nil
+}
+
+// split splits s around each occurrence of sep.
+func split(sep, s string) []string {
+	return strings.Split(s, sep)
+}
+
+// join concatenates the elements of a list with sep between them. Elements
+// are formatted with fmt.Sprint.
+func join(sep string, items interface{}) (string, error) {
+	values, err := toSlice("join", items)
+	if err != nil {
+		return "", err
+	}
+
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = fmt.Sprint(value)
+	}
+	return strings.Join(parts, sep), nil
+}
+
+// defaultValue returns value, or fallback when value is empty (see isEmpty).
+// It is registered as "default" so `{{.status | default "draft"}}` reads
+// naturally.
+func defaultValue(fallback, value interface{}) interface{} {
+	if isEmpty(value) {
+		return fallback
+	}
+	return value
+}
+
+// coalesce returns the first non-empty value, or nil when all are empty.
+func coalesce(values ...interface{}) interface{} {
+	for _, value := range values {
+		if !isEmpty(value) {
+			return value
+		}
+	}
+	return nil
+}
+
+// list returns its arguments as a list.
+func list(values ...interface{}) []interface{} {
+	if values == nil {
+		return []interface{}{}
+	}
+	return values
+}
+
+// dict builds a map from alternating string keys and values, e.g.
+// `dict "title" .title "tags" (list "a" "b")`.
+func dict(pairs ...interface{}) (map[string]interface{}, error) {
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("dict: expected key/value pairs, got %d args",
+			len(pairs))
+	}
+
+	result := make(map[string]interface{}, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict: key %v is %T, not a string",
+				pairs[i], pairs[i])
+		}
+		result[key] = pairs[i+1]
+	}
+	return result, nil
+}
+
+// contains reports whether haystack contains needle: a substring check for
+// strings, an element check for lists, and a key check for maps.
+func contains(needle, haystack interface{}) (bool, error) {
+	if text, ok := haystack.(string); ok {
+		return strings.Contains(text, fmt.Sprint(needle)), nil
+	}
+
+	v := reflect.ValueOf(haystack)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if reflect.DeepEqual(v.Index(i).Interface(), needle) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case reflect.Map:
+		key := reflect.ValueOf(needle)
+		if !key.IsValid() || !key.Type().AssignableTo(v.Type().Key()) {
+			return false, nil
+		}
+		return v.MapIndex(key).IsValid(), nil
+	case reflect.Invalid:
+		return false, nil
+	default:
+		return false, fmt.Errorf("contains: cannot search %T", haystack)
+	}
+}
+
+// wikilink formats an Obsidian wiki link to target, with an optional display
+// alias: `wikilink "Projects/Q3"` → "[[Projects/Q3]]",
+// `wikilink "Projects/Q3" "Q3"` → "[[Projects/Q3|Q3]]".
+func wikilink(target string, alias ...string) (string, error) {
+	if len(alias) > 1 {
+		return "", fmt.Errorf("wikilink: expected at most one alias, got %d",
+			len(alias))
+	}
+	if len(alias) == 1 && alias[0] != "" && alias[0] != target {
+		return "[[" + target + "|" + alias[0] + "]]", nil
+	}
+	return "[[" + target + "]]", nil
+}
+
+// quoteYAML renders value as a YAML flow scalar that is safe to place after a
+// frontmatter key. Strings are always double-quoted with special characters
+// escaped, so input such as "yes", "null", "a: b", or "#tag" keeps its
+// literal meaning; numbers and booleans are emitted bare; lists and maps
+// become flow collections. The output is JSON, which YAML accepts as-is.
+func quoteYAML(value interface{}) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return "", fmt.Errorf("quoteYAML: %w", err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// isEmpty reports whether value is nil, a zero value, or an empty string,
+// list, or map.
+func isEmpty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
+
+// toSlice converts any list value to []interface{}.
+func toSlice(funcName string, items interface{}) ([]interface{}, error) {
+	switch typed := items.(type) {
+	case []interface{}:
+		return typed, nil
+	case []string:
+		values := make([]interface{}, len(typed))
+		for i, item := range typed {
+			values[i] = item
+		}
+		return values, nil
+	}
+
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("%s: expected a list, got %T", funcName, items)
+	}
+	values := make([]interface{}, v.Len())
+	for i := range values {
+		values[i] = v.Index(i).Interface()
+	}
+	return values, nil
+}
+
 // NewFuncMap creates and returns a template.FuncMap containing all available
-// template functions. This function map can be used with template.New().Funcs()
-// to register functions for template execution.
+// template functions, with date functions working in the local time zone.
+// This function map can be used with template.New().Funcs() to register
+// functions for template execution.
+func NewFuncMap() template.FuncMap {
+	return NewFuncMapWithOptions(FuncMapOptions{})
+}
+
+// NewFuncMapWithOptions creates the template function map configured by opts.
 //
-// The function map includes:
+// Dates (arguments come last so functions chain in pipelines, e.g.
+// `{{today | dateAdd "-1d" | dateFormat "2006-01-02"}}`; dates may be time
+// values or strings such as "2025-03-14"):
 //   - now: Format current time with optional layout
+//   - today: Midnight at the start of the current day
+//   - dateAdd: Shift a date by an offset such as "7d", "-1w", or "1M"
+//   - dateParse: Parse a date string with a Go reference layout
+//   - dateFormat: Format a date with a Go reference layout
+//   - startOfWeek: Midnight on the Monday of a date's ISO week
+//   - weekNumber: ISO week number of a date
+//   - isoWeek: ISO week of a date as "YYYY-Www"
+//   - weekday: English weekday name of a date
+//   - relativeDate: Resolve phrases such as "next monday" or "3 days ago"
+//
+// Strings (the string operated on comes last, e.g. `{{.title | trim}}`):
 //   - toLower: Convert string to lowercase
 //   - toUpper: Convert string to uppercase
+//   - titleCase: Upper-case the first letter of every word
+//   - slug: Convert string to a file-name-safe, hyphenated form
+//   - trim: Remove leading and trailing whitespace
+//   - truncate: Shorten to N characters, ending in "…" when cut
+//   - replace: Replace every occurrence of a substring
+//   - regexReplace: Replace every match of a regular expression
+//   - split: Split a string into a list around a separator
+//   - join: Join a list into a string with a separator
+//   - wikilink: Format an Obsidian [[link]] with an optional alias
+//   - quoteYAML: Render a value as a YAML-safe scalar for frontmatter
+//
+// Values and collections:
+//   - default: Fall back to a value when the piped value is empty
+//   - coalesce: First non-empty argument
+//   - list: Build a list from arguments
+//   - dict: Build a map from alternating keys and values
+//   - contains: Substring, list element, or map key check
+//
+// Questions (see Prompter):
+//   - prompt: Ask for a value, with an optional default
+//   - suggester: Ask to choose an item of a list or a key of a map
 //
 // This design allows for easy extension by adding new functions to this map.
-func NewFuncMap() template.FuncMap {
+func NewFuncMapWithOptions(opts FuncMapOptions) template.FuncMap {
+	dates := newDateFuncs(opts.Location, opts.Clock)
+
 	return template.FuncMap{
-		"now":     now,
-		"toLower": toLower,
-		"toUpper": toUpper,
+		"now":          dates.now,
+		"today":        dates.today,
+		"dateAdd":      dates.dateAdd,
+		"dateParse":    dates.dateParse,
+		"dateFormat":   dates.dateFormat,
+		"startOfWeek":  dates.startOfWeek,
+		"weekNumber":   dates.weekNumber,
+		"isoWeek":      dates.isoWeek,
+		"weekday":      dates.weekday,
+		"relativeDate": dates.relativeDate,
+		"toLower":      toLower,
+		"toUpper":      toUpper,
+		"titleCase":    titleCase,
+		"slug":         slug,
+		"trim":         trim,
+		"truncate":     truncate,
+		"replace":      replace,
+		"regexReplace": regexReplace,
+		"split":        split,
+		"join":         join,
+		"wikilink":     wikilink,
+		"quoteYAML":    quoteYAML,
+		"default":      defaultValue,
+		"coalesce":     coalesce,
+		"list":         list,
+		"dict":         dict,
+		"contains":     contains,
+		"prompt":       opts.Prompter.prompt,
+		"suggester":    opts.Prompter.suggester,
 	}
 }
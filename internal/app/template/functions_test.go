@@ -0,0 +1,353 @@
+package template
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"text/template"
+
+	"go.yaml.in/yaml/v3"
+)
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "empty", input: "", want: ""},
+		{name: "simple words", input: "Weekly Sync", want: "weekly-sync"},
+		{
+			name:  "punctuation collapses",
+			input: "Q3 Planning: Draft!",
+			want:  "q3-planning-draft",
+		},
+		{
+			name:  "leading and trailing separators",
+			input: "  --Hello World--  ",
+			want:  "hello-world",
+		},
+		{name: "unicode letters kept", input: "Café Ümlaut", want: "café-ümlaut"},
+		{name: "only separators", input: "!!!", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slug(tt.input); got != tt.want {
+				t.Errorf("slug(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStringHelpers(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{
+			name: "titleCase",
+			got:  titleCase("q3 planning notes"),
+			want: "Q3 Planning Notes",
+		},
+		{
+			name: "titleCase keeps inner case",
+			got:  titleCase("an API guide"),
+			want: "An API Guide",
+		},
+		{name: "trim", got: trim("  padded \n"), want: "padded"},
+		{
+			name: "truncate short input",
+			got:  truncate(10, "short"),
+			want: "short",
+		},
+		{
+			name: "truncate cuts with ellipsis",
+			got:  truncate(6, "Weekly Sync"),
+			want: "Weekl…",
+		},
+		{
+			name: "truncate counts characters",
+			got:  truncate(3, "Ümlaut"),
+			want: "Üm…",
+		},
+		{name: "truncate zero", got: truncate(0, "text"), want: ""},
+		{name: "replace", got: replace("/", "-", "a/b/c"), want: "a-b-c"},
+		{
+			name: "wikilink",
+			got:  mustString(t)(wikilink("Projects/Q3")),
+			want: "[[Projects/Q3]]",
+		},
+		{
+			name: "wikilink with alias",
+			got:  mustString(t)(wikilink("Projects/Q3", "Q3")),
+			want: "[[Projects/Q3|Q3]]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegexReplace(t *testing.T) {
+	got, err := regexReplace(`(\d{4})-(\d{2})`, "$2/$1", "due 2025-03")
+	if err != nil {
+		t.Fatalf("regexReplace() error = %v", err)
+	}
+	if got != "due 03/2025" {
+		t.Errorf("regexReplace() = %q, want %q", got, "due 03/2025")
+	}
+
+	if _, err := regexReplace("(", "", "x"); err == nil {
+		t.Error("regexReplace() with invalid pattern should fail")
+	}
+}
+
+func TestSplitJoin(t *testing.T) {
+	parts := split(",", "a,b,c")
+	if !reflect.DeepEqual(parts, []string{"a", "b", "c"}) {
+		t.Errorf("split() = %v", parts)
+	}
+
+	joined, err := join(", ", []interface{}{"a", 2, true})
+	if err != nil {
+		t.Fatalf("join() error = %v", err)
+	}
+	if joined != "a, 2, true" {
+		t.Errorf("join() = %q, want %q", joined, "a, 2, true")
+	}
+
+	if _, err := join(",", "not a list"); err == nil {
+		t.Error("join() with a string should fail")
+	}
+}
+
+func TestDefaultAndCoalesce(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  interface{}
+	}{
+		{name: "nil", value: nil, want: "fallback"},
+		{name: "empty string", value: "", want: "fallback"},
+		{name: "empty list", value: []interface{}{}, want: "fallback"},
+		{name: "zero number", value: 0, want: "fallback"},
+		{name: "false", value: false, want: "fallback"},
+		{name: "set string", value: "draft", want: "draft"},
+		{name: "set number", value: 3, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := defaultValue("fallback", tt.value); !reflect.DeepEqual(
+				got,
+				tt.want,
+			) {
+				t.Errorf("default() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := coalesce(nil, "", "second", "third"); got != "second" {
+		t.Errorf("coalesce() = %v, want second", got)
+	}
+	if got := coalesce(nil, ""); got != nil {
+		t.Errorf("coalesce() of empty values = %v, want nil", got)
+	}
+}
+
+func TestListDictContains(t *testing.T) {
+	items := list("a", "b")
+	if !reflect.DeepEqual(items, []interface{}{"a", "b"}) {
+		t.Errorf("list() = %v", items)
+	}
+	if got := list(); got == nil || len(got) != 0 {
+		t.Errorf("list() with no args = %#v, want empty list", got)
+	}
+
+	m, err := dict("title", "Weekly", "count", 2)
+	if err != nil {
+		t.Fatalf("dict() error = %v", err)
+	}
+	want := map[string]interface{}{"title": "Weekly", "count": 2}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("dict() = %v, want %v", m, want)
+	}
+	if _, err := dict("odd"); err == nil {
+		t.Error("dict() with odd args should fail")
+	}
+	if _, err := dict(1, "x"); err == nil {
+		t.Error("dict() with a non-string key should fail")
+	}
+
+	tests := []struct {
+		name     string
+		needle   interface{}
+		haystack interface{}
+		want     bool
+	}{
+		{name: "substring", needle: "ek", haystack: "weekly", want: true},
+		{name: "missing substring", needle: "x", haystack: "weekly"},
+		{name: "list element", needle: "b", haystack: items, want: true},
+		{
+			name:     "string slice",
+			needle:   "c",
+			haystack: []string{"c"},
+			want:     true,
+		},
+		{name: "missing element", needle: "z", haystack: items},
+		{name: "map key", needle: "title", haystack: m, want: true},
+		{name: "missing map key", needle: "x", haystack: m},
+		{name: "nil haystack", needle: "x", haystack: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := contains(tt.needle, tt.haystack)
+			if err != nil {
+				t.Fatalf("contains() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := contains("x", 42); err == nil {
+		t.Error("contains() on a number should fail")
+	}
+}
+
+func TestQuoteYAML(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{name: "plain string", value: "Weekly", want: `"Weekly"`},
+		{name: "yaml boolean word", value: "yes", want: `"yes"`},
+		{name: "colon and hash", value: "a: b #c", want: `"a: b #c"`},
+		{
+			name:  "quotes and newline",
+			value: "say \"hi\"\nbye",
+			want:  `"say \"hi\"\nbye"`,
+		},
+		{name: "html characters kept", value: "<a & b>", want: `"<a & b>"`},
+		{name: "number", value: 42, want: `42`},
+		{name: "bool", value: true, want: `true`},
+		{name: "nil", value: nil, want: `null`},
+		{name: "list", value: []string{"a", "b: c"}, want: `["a","b: c"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := quoteYAML(tt.value)
+			if err != nil {
+				t.Fatalf("quoteYAML() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("quoteYAML() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteYAML_RoundTripsThroughFrontmatter(t *testing.T) {
+	inputs := []string{
+		"yes", "null", "~", "- item", "key: value", "#tag", "[link]",
+		"{brace}", "'single'", "\"double\"", "multi\nline", "tab\tend", "@at",
+		"100", "2025-03-14", "*alias", "&anchor", "!tag", "% percent", "",
+	}
+
+	for _, input := range inputs {
+		quoted, err := quoteYAML(input)
+		if err != nil {
+			t.Fatalf("quoteYAML(%q) error = %v", input, err)
+		}
+
+		var decoded map[string]interface{}
+		frontmatter := []byte("title: " + quoted + "\n")
+		if err := yaml.Unmarshal(frontmatter, &decoded); err != nil {
+			t.Fatalf("frontmatter for %q does not parse: %v", input, err)
+		}
+		if decoded["title"] != input {
+			t.Errorf("round trip of %q = %#v", input, decoded["title"])
+		}
+	}
+}
+
+func TestFuncMap_StringPipelines(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		data     map[string]interface{}
+		want     string
+	}{
+		{
+			name:     "default with pipeline",
+			template: `{{.status | default "draft"}}`,
+			data:     map[string]interface{}{},
+			want:     "draft",
+		},
+		{
+			name:     "trim then titleCase",
+			template: `{{.title | trim | titleCase}}`,
+			data:     map[string]interface{}{"title": "  weekly sync "},
+			want:     "Weekly Sync",
+		},
+		{
+			name:     "split and join",
+			template: `{{.tags | split "," | join " #"}}`,
+			data:     map[string]interface{}{"tags": "a,b"},
+			want:     "a #b",
+		},
+		{
+			name:     "dict and contains",
+			template: `{{$m := dict "k" 1}}{{contains "k" $m}}`,
+			want:     "true",
+		},
+		{
+			name:     "quoted frontmatter",
+			template: `title: {{.title | quoteYAML}}`,
+			data:     map[string]interface{}{"title": "Note: draft"},
+			want:     `title: "Note: draft"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := template.New("t").
+				Funcs(NewFuncMap()).
+				Parse(tt.template)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			var out strings.Builder
+			if err := tmpl.Execute(&out, tt.data); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("got %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+// mustString returns a function that unwraps a (string, error) result,
+// failing the test on error.
+func mustString(t *testing.T) func(string, error) string {
+	t.Helper()
+	return func(s string, err error) string {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return s
+	}
+}
@@ -0,0 +1,657 @@
+// Package template provides domain services for template parsing and execution.
+// This file contains the static template linter behind
+// "lithos templates check".
+package template
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template/parse"
+
+	"github.com/JackMatanky/lithos/internal/domain"
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+	"github.com/JackMatanky/lithos/internal/shared/errors"
+)
+
+// builtinFuncs lists the functions text/template provides to every template.
+var builtinFuncs = map[string]bool{
+	"and": true, "call": true, "html": true, "index": true, "slice": true,
+	"js": true, "len": true, "not": true, "or": true, "print": true,
+	"printf": true, "println": true, "urlquery": true, "eq": true,
+	"ge": true, "gt": true, "le": true, "lt": true, "ne": true,
+}
+
+// actionPattern matches a template action with its optional trim markers.
+// Comments are matched as a whole, so actions quoted inside them, such as the
+// output pattern of a header, do not end the comment early.
+var actionPattern = regexp.MustCompile(
+	`(?s)\{\{(-\s)?(/\*.*?\*/|.*?)(\s-)?\}\}`,
+)
+
+// assignmentPattern matches actions that declare or assign a variable.
+var assignmentPattern = regexp.MustCompile(`^\$\w*\s*:?=`)
+
+// controlKeywords lists the actions that produce no output of their own.
+var controlKeywords = []string{
+	"if", "else", "end", "range", "with", "define", "block", "template",
+	"break", "continue",
+}
+
+// LintIssue describes a single problem found in a template or partial.
+type LintIssue struct {
+	Path    string // File the problem was found in
+	Line    int    // 1-based line, or 0 when unknown
+	Column  int    // 1-based column, or 0 when unknown
+	Message string // Description of the problem
+}
+
+// String formats the issue as "path:line:column: message", omitting an
+// unknown position.
+func (i LintIssue) String() string {
+	location := i.Path
+	if i.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, i.Line)
+		if i.Column > 0 {
+			location = fmt.Sprintf("%s:%d", location, i.Column)
+		}
+	}
+	return location + ": " + i.Message
+}
+
+// Linter statically checks templates without rendering them. It reports
+// syntax errors, calls to unknown functions, partials no template uses,
+// field references the template's schema does not define, and frontmatter
+// blocks that are not valid YAML.
+type Linter struct {
+	templateRepo   spi.TemplateRepositoryPort
+	fileSystemPort spi.FileSystemPort
+	schemas        spi.SchemaRegistryLoaderPort
+	funcNames      map[string]bool
+	schemasLoaded  bool
+}
+
+// NewLinter creates a Linter reading templates through templateRepo and
+// fileSystemPort. schemas is initialized the first time a template names a
+// schema; it may be nil when no schemas are available.
+func NewLinter(
+	templateRepo spi.TemplateRepositoryPort,
+	fileSystemPort spi.FileSystemPort,
+	schemas spi.SchemaRegistryLoaderPort,
+) *Linter {
+	funcNames := make(map[string]bool)
+	for name := range NewFuncMap() {
+		funcNames[name] = true
+	}
+	for name := range builtinFuncs {
+		funcNames[name] = true
+	}
+
+	return &Linter{
+		templateRepo:   templateRepo,
+		fileSystemPort: fileSystemPort,
+		schemas:        schemas,
+		funcNames:      funcNames,
+	}
+}
+
+// lintSource is a template or partial to check.
+type lintSource struct {
+	path    string
+	content string
+}
+
+// Check lints the templates at paths, or every template in the repository
+// when paths is empty. Partials are always checked; unused partials are only
+// reported when every template is checked, since a partial may be used by a
+// template outside paths. Issues are sorted by path and position. An error
+// is returned only when templates cannot be enumerated or read.
+func (l *Linter) Check(
+	ctx context.Context,
+	paths []string,
+) ([]LintIssue, error) {
+	partials, err := l.templateRepo.ListPartials(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load partials")
+	}
+
+	sources, err := l.templateSources(ctx, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	issues, partialTrees := l.checkPartials(partials)
+	partialsValid := len(issues) == 0
+
+	references := make(map[string]bool)
+	for _, source := range sources {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		sourceIssues, err := l.checkTemplate(
+			ctx,
+			source,
+			partialsValid,
+			references,
+		)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, sourceIssues...)
+	}
+
+	if len(paths) == 0 && partialsValid {
+		issues = append(issues, unusedPartials(
+			partials,
+			partialTrees,
+			references,
+		)...)
+	}
+
+	sortIssues(issues)
+	return issues, nil
+}
+
+// checkPartials checks the syntax of every partial and returns the issues
+// found with the parse trees of each partial, keyed by partial name.
+// Partials are parsed into every template, so a broken partial would make
+// every template fail to load; it is reported once here instead.
+func (l *Linter) checkPartials(
+	partials []domain.Partial,
+) ([]LintIssue, map[string]map[string]*parse.Tree) {
+	var issues []LintIssue
+	partialTrees := make(map[string]map[string]*parse.Tree, len(partials))
+	for _, partial := range partials {
+		trees, sourceIssues := l.checkSource(
+			partial.Path,
+			partial.Name,
+			partial.Content,
+		)
+		issues = append(issues, sourceIssues...)
+		partialTrees[partial.Name] = trees
+	}
+	return issues, partialTrees
+}
+
+// checkTemplate lints a single template and adds the partials it uses,
+// including its layout, to references. Templates with syntax errors, or
+// checked alongside broken partials, are not loaded, so their fields are
+// not checked against their schema.
+func (l *Linter) checkTemplate(
+	ctx context.Context,
+	source lintSource,
+	partialsValid bool,
+	references map[string]bool,
+) ([]LintIssue, error) {
+	trees, issues := l.checkSource(
+		source.path,
+		rootParseName,
+		source.content,
+	)
+	syntaxValid := len(issues) == 0
+	issues = append(issues, checkFrontmatter(source)...)
+	addReferences(references, trees)
+
+	if !syntaxValid || !partialsValid {
+		return issues, nil
+	}
+
+	tmpl, err := l.templateRepo.GetByPath(ctx, source.path)
+	if err != nil {
+		return append(issues, issueFromError(source.path, err)), nil
+	}
+	if tmpl.Header.Layout != "" {
+		references[tmpl.Header.Layout] = true
+	}
+
+	schemaIssues, err := l.checkSchemaFields(
+		ctx,
+		source,
+		tmpl,
+		trees[rootParseName],
+	)
+	if err != nil {
+		return nil, err
+	}
+	return append(issues, schemaIssues...), nil
+}
+
+// templateSources returns the sources to lint: the files at paths, or every
+// template in the repository when paths is empty.
+func (l *Linter) templateSources(
+	ctx context.Context,
+	paths []string,
+) ([]lintSource, error) {
+	if len(paths) == 0 {
+		templates, err := l.templateRepo.List(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list templates")
+		}
+		sources := make([]lintSource, 0, len(templates))
+		for _, tmpl := range templates {
+			sources = append(sources, lintSource{
+				path:    tmpl.FilePath,
+				content: tmpl.Content,
+			})
+		}
+		return sources, nil
+	}
+
+	sources := make([]lintSource, 0, len(paths))
+	for _, path := range paths {
+		content, err := l.fileSystemPort.ReadFile(path)
+		if err != nil {
+			return nil, errors.NewResourceError("template", "read", path, err)
+		}
+		sources = append(sources, lintSource{
+			path:    path,
+			content: string(content),
+		})
+	}
+	return sources, nil
+}
+
+// checkSource parses content without resolving functions and reports syntax
+// errors and calls to unknown functions. It returns the parsed trees keyed by
+// name: the body under name, plus every {{define}} and {{block}}.
+func (l *Linter) checkSource(
+	path, name, content string,
+) (map[string]*parse.Tree, []LintIssue) {
+	trees := make(map[string]*parse.Tree)
+	tree := parse.New(name)
+	tree.Mode = parse.SkipFuncCheck
+	if _, err := tree.Parse(content, "", "", trees); err != nil {
+		templateErr := positionedError(
+			err,
+			"",
+			map[string]string{name: content},
+		)
+		return nil, []LintIssue{{
+			Path:    path,
+			Line:    templateErr.Line(),
+			Column:  templateErr.Column(),
+			Message: "syntax error: " + lintReason(templateErr),
+		}}
+	}
+
+	var issues []LintIssue
+	for _, tree := range trees {
+		walkNodes(tree.Root, func(node parse.Node) {
+			ident, ok := node.(*parse.IdentifierNode)
+			if !ok || l.funcNames[ident.Ident] {
+				return
+			}
+			line, column := position(content, ident.Position())
+			issues = append(issues, LintIssue{
+				Path:    path,
+				Line:    line,
+				Column:  column,
+				Message: fmt.Sprintf("unknown function %q", ident.Ident),
+			})
+		})
+	}
+	return trees, issues
+}
+
+// checkSchemaFields reports references to root fields, such as {{.title}} or
+// {{$.title}}, that are neither declared as params nor defined by the schema
+// of the template (see TemplateSchema). Fields inside {{range}} and {{with}}
+// refer to a different dot and are not checked, nor are the render context
+// keys such as {{.Target}} that every template receives.
+func (l *Linter) checkSchemaFields(
+	ctx context.Context,
+	source lintSource,
+	tmpl *domain.Template,
+	body *parse.Tree,
+) ([]LintIssue, error) {
+	schemaName := TemplateSchema(tmpl)
+	if schemaName == "" || body == nil {
+		return nil, nil
+	}
+
+	schema, found, err := l.lookupSchema(ctx, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return []LintIssue{{
+			Path:    source.path,
+			Message: fmt.Sprintf("schema %q not found", schemaName),
+		}}, nil
+	}
+
+	known := make(map[string]bool)
+	for _, property := range schema.GetResolvedProperties() {
+		known[property.Name] = true
+	}
+	for _, param := range tmpl.Header.Params {
+		known[param.Name] = true
+	}
+
+	var issues []LintIssue
+	reported := make(map[string]bool)
+	walkRootFields(body.Root, true, func(field string, pos parse.Pos) {
+		if known[field] || reported[field] || domain.IsRenderKey(field) {
+			return
+		}
+		reported[field] = true
+		line, column := position(source.content, pos)
+		issues = append(issues, LintIssue{
+			Path:   source.path,
+			Line:   line,
+			Column: column,
+			Message: fmt.Sprintf(
+				"field %q is not defined by schema %q",
+				field,
+				schemaName,
+			),
+		})
+	})
+	return issues, nil
+}
+
+// lookupSchema returns the named schema, initializing the registry on first
+// use.
+func (l *Linter) lookupSchema(
+	ctx context.Context,
+	name string,
+) (domain.Schema, bool, error) {
+	if l.schemas == nil {
+		return domain.Schema{}, false, nil
+	}
+	if !l.schemasLoaded {
+		if result := l.schemas.Initialize(ctx); result.IsErr() {
+			return domain.Schema{}, false, errors.Wrap(
+				result.Error(),
+				"failed to load schemas",
+			)
+		}
+		l.schemasLoaded = true
+	}
+
+	schema, found := l.schemas.Get(name)
+	return schema, found, nil
+}
+
+// checkFrontmatter checks that the frontmatter block a template emits is
+// valid YAML (see TemplateFrontmatter).
+func checkFrontmatter(source lintSource) []LintIssue {
+	if _, _, err := TemplateFrontmatter(source.content); err != nil {
+		return []LintIssue{{Path: source.path, Message: err.Error()}}
+	}
+	return nil
+}
+
+// templateSkeleton replaces the actions in content with the text they stand
+// for when checking frontmatter structure.
+func templateSkeleton(content string) string {
+	var out strings.Builder
+	trimNext := false
+	last := 0
+	matches := actionPattern.FindAllStringSubmatchIndex(content, -1)
+	for _, match := range matches {
+		text := content[last:match[0]]
+		if trimNext {
+			text = strings.TrimLeft(text, " \t\r\n")
+		}
+		if match[2] >= 0 {
+			text = strings.TrimRight(text, " \t\r\n")
+		}
+		out.WriteString(text)
+
+		action := strings.TrimSpace(content[match[4]:match[5]])
+		if !isSilentAction(action) {
+			out.WriteString(ValuePlaceholder)
+		}
+
+		trimNext = match[6] >= 0
+		last = match[1]
+	}
+
+	text := content[last:]
+	if trimNext {
+		text = strings.TrimLeft(text, " \t\r\n")
+	}
+	out.WriteString(text)
+	return out.String()
+}
+
+// isSilentAction reports whether action produces no output of its own:
+// comments, control structures, and variable declarations.
+func isSilentAction(action string) bool {
+	if strings.HasPrefix(action, "/*") {
+		return true
+	}
+	for _, keyword := range controlKeywords {
+		if action == keyword || strings.HasPrefix(action, keyword+" ") {
+			return true
+		}
+	}
+	return assignmentPattern.MatchString(action)
+}
+
+// unusedPartials reports partials that no template refers to, directly, as a
+// layout, or through another used partial. A partial counts as used when its
+// name or any template it {{define}}s is referenced.
+func unusedPartials(
+	partials []domain.Partial,
+	trees map[string]map[string]*parse.Tree,
+	references map[string]bool,
+) []LintIssue {
+	used := make(map[string]bool, len(partials))
+	for changed := true; changed; {
+		changed = false
+		for _, partial := range partials {
+			if used[partial.Name] || !partialReferenced(
+				partial.Name,
+				trees[partial.Name],
+				references,
+			) {
+				continue
+			}
+			used[partial.Name] = true
+			addReferences(references, trees[partial.Name])
+			changed = true
+		}
+	}
+
+	var issues []LintIssue
+	for _, partial := range partials {
+		if !used[partial.Name] {
+			issues = append(issues, LintIssue{
+				Path: partial.Path,
+				Message: fmt.Sprintf(
+					"partial %q is not used by any template",
+					partial.Name,
+				),
+			})
+		}
+	}
+	return issues
+}
+
+// partialReferenced reports whether the partial or a template it defines is
+// referenced.
+func partialReferenced(
+	name string,
+	trees map[string]*parse.Tree,
+	references map[string]bool,
+) bool {
+	if references[name] {
+		return true
+	}
+	for defined := range trees {
+		if defined != name && references[defined] {
+			return true
+		}
+	}
+	return false
+}
+
+// addReferences records the names of the templates invoked by trees.
+func addReferences(references map[string]bool, trees map[string]*parse.Tree) {
+	for _, tree := range trees {
+		walkNodes(tree.Root, func(node parse.Node) {
+			if invoked, ok := node.(*parse.TemplateNode); ok {
+				references[invoked.Name] = true
+			}
+		})
+	}
+}
+
+// walkNodes calls visit for node and every node beneath it.
+func walkNodes(node parse.Node, visit func(parse.Node)) {
+	if node == nil {
+		return
+	}
+	visit(node)
+
+	for _, child := range childNodes(node) {
+		walkNodes(child, visit)
+	}
+}
+
+// childNodes returns the nodes directly beneath node: the pipeline and both
+// lists of an if, range, or with, and the contents of any other node that
+// holds further nodes.
+func childNodes(node parse.Node) []parse.Node {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n != nil {
+			return n.Nodes
+		}
+	case *parse.ActionNode:
+		return []parse.Node{n.Pipe}
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		children := make([]parse.Node, 0, len(n.Cmds))
+		for _, cmd := range n.Cmds {
+			children = append(children, cmd)
+		}
+		return children
+	case *parse.CommandNode:
+		return n.Args
+	case *parse.ChainNode:
+		return []parse.Node{n.Node}
+	case *parse.IfNode:
+		return branchNodes(&n.BranchNode)
+	case *parse.RangeNode:
+		return branchNodes(&n.BranchNode)
+	case *parse.WithNode:
+		return branchNodes(&n.BranchNode)
+	case *parse.TemplateNode:
+		return []parse.Node{n.Pipe}
+	}
+	return nil
+}
+
+// branchNodes returns the pipeline and both lists of an if, range, or with.
+func branchNodes(branch *parse.BranchNode) []parse.Node {
+	return []parse.Node{branch.Pipe, branch.List, branch.ElseList}
+}
+
+// walkRootFields calls visit with the first identifier of every field read
+// from the root data: {{.title}} while dot is the root, and {{$.title}}
+// anywhere.
+func walkRootFields(
+	node parse.Node,
+	dotIsRoot bool,
+	visit func(field string, pos parse.Pos),
+) {
+	if node == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *parse.FieldNode:
+		if dotIsRoot {
+			visit(n.Ident[0], n.Position())
+		}
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			visit(n.Ident[1], n.Position())
+		}
+	case *parse.IfNode:
+		walkRootBranch(&n.BranchNode, dotIsRoot, dotIsRoot, visit)
+	case *parse.RangeNode:
+		walkRootBranch(&n.BranchNode, dotIsRoot, false, visit)
+	case *parse.WithNode:
+		walkRootBranch(&n.BranchNode, dotIsRoot, false, visit)
+	default:
+		for _, child := range childNodes(node) {
+			walkRootFields(child, dotIsRoot, visit)
+		}
+	}
+}
+
+// walkRootBranch walks the root fields of an if, range, or with. Its
+// pipeline and else branch see the dot of the enclosing action, and its body
+// sees the root as dot when bodyDotIsRoot is set: range and with move dot to
+// the value they act on.
+func walkRootBranch(
+	branch *parse.BranchNode,
+	dotIsRoot bool,
+	bodyDotIsRoot bool,
+	visit func(field string, pos parse.Pos),
+) {
+	walkRootFields(branch.Pipe, dotIsRoot, visit)
+	walkRootFields(branch.List, bodyDotIsRoot, visit)
+	walkRootFields(branch.ElseList, dotIsRoot, visit)
+}
+
+// position converts a byte offset in content to a 1-based line and column.
+func position(content string, pos parse.Pos) (line, column int) {
+	offset := int(pos)
+	if offset > len(content) {
+		offset = len(content)
+	}
+	before := content[:offset]
+	line = 1 + strings.Count(before, "\n")
+	column = offset - strings.LastIndex(before, "\n")
+	return line, column
+}
+
+// issueFromError converts an error from loading a template into an issue,
+// keeping the position of template errors.
+func issueFromError(path string, err error) LintIssue {
+	var templateErr errors.TemplateError
+	if stderrors.As(err, &templateErr) {
+		return LintIssue{
+			Path:    path,
+			Line:    templateErr.Line(),
+			Column:  templateErr.Column(),
+			Message: lintReason(templateErr),
+		}
+	}
+	return LintIssue{Path: path, Message: err.Error()}
+}
+
+// lintReason returns the message of a template error without its
+// "template 'x' line n" prefix, which LintIssue reports separately.
+func lintReason(err errors.TemplateError) string {
+	message := err.Error()
+	if _, reason, found := strings.Cut(message, ": "); found {
+		return reason
+	}
+	return message
+}
+
+// sortIssues orders issues by path, line, and column.
+func sortIssues(issues []LintIssue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
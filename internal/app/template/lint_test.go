@@ -0,0 +1,328 @@
+package template
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/JackMatanky/lithos/internal/domain"
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+	"github.com/JackMatanky/lithos/internal/shared/errors"
+	testutils "github.com/JackMatanky/lithos/tests/utils"
+)
+
+// lintRepo is an in-memory TemplateRepositoryPort for linter tests.
+type lintRepo struct {
+	templates map[string]string
+	headers   map[string]domain.TemplateHeader
+	partials  []domain.Partial
+}
+
+func (r *lintRepo) List(context.Context) ([]spi.TemplateMetadata, error) {
+	var metadata []spi.TemplateMetadata
+	for path, content := range r.templates {
+		metadata = append(metadata, spi.TemplateMetadata{
+			FilePath: path,
+			Content:  content,
+		})
+	}
+	return metadata, nil
+}
+
+func (r *lintRepo) Get(_ context.Context, id string) (*domain.Template, error) {
+	return nil, errors.NewTemplateNotFoundError(id)
+}
+
+func (r *lintRepo) GetByPath(
+	_ context.Context,
+	path string,
+) (*domain.Template, error) {
+	content, ok := r.templates[path]
+	if !ok {
+		return nil, errors.NewTemplateNotFoundError(path)
+	}
+	return &domain.Template{
+		FilePath: path,
+		Content:  content,
+		Header:   r.headers[path],
+	}, nil
+}
+
+func (r *lintRepo) ListPartials(context.Context) ([]domain.Partial, error) {
+	return r.partials, nil
+}
+
+// lintSchemas is an in-memory SchemaRegistryLoaderPort for linter tests.
+type lintSchemas struct {
+	schemas     map[string]domain.Schema
+	initialized int
+}
+
+func (s *lintSchemas) Get(name string) (domain.Schema, bool) {
+	schema, ok := s.schemas[name]
+	return schema, ok
+}
+
+func (s *lintSchemas) Initialize(context.Context) errors.Result[struct{}] {
+	s.initialized++
+	return errors.Ok(struct{}{})
+}
+
+func projectSchemas() *lintSchemas {
+	return &lintSchemas{schemas: map[string]domain.Schema{
+		"project": domain.NewSchema("project", []domain.Property{
+			domain.NewProperty("title", true, false, nil),
+			domain.NewProperty("status", false, false, nil),
+		}),
+	}}
+}
+
+func TestLinterCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates map[string]string
+		headers   map[string]domain.TemplateHeader
+		partials  []domain.Partial
+		want      []string
+	}{
+		{
+			name: "clean template has no issues",
+			templates: map[string]string{
+				"note.md": "---\ntitle: {{ .title | quoteYAML }}\n---\n" +
+					"{{ template \"footer\" . }}",
+			},
+			partials: []domain.Partial{{
+				Name:    "footer",
+				Path:    "partials/footer.md",
+				Content: "-- {{ now \"2006\" }}",
+			}},
+		},
+		{
+			name: "unknown function reports its position",
+			templates: map[string]string{
+				"note.md": "# Title\n{{ .title | shout }}",
+			},
+			want: []string{`note.md:2:13: unknown function "shout"`},
+		},
+		{
+			name: "syntax error reports its line",
+			templates: map[string]string{
+				"note.md": "ok\n{{ if .x }}unclosed",
+			},
+			want: []string{"note.md:2: syntax error: unexpected EOF"},
+		},
+		{
+			name: "unused partial is reported",
+			templates: map[string]string{
+				"note.md": "{{ template \"used\" . }}",
+			},
+			partials: []domain.Partial{
+				{
+					Name:    "used",
+					Path:    "p/used.md",
+					Content: "{{ template \"inner\" }}",
+				},
+				{Name: "inner", Path: "p/inner.md", Content: "inner"},
+				{Name: "stale", Path: "p/stale.md", Content: "stale"},
+			},
+			want: []string{
+				`p/stale.md: partial "stale" is not used by any template`,
+			},
+		},
+		{
+			name: "partial used through its defines or as a layout",
+			templates: map[string]string{
+				"note.md": "{{ template \"card\" . }}",
+				"page.md": "body",
+			},
+			headers: map[string]domain.TemplateHeader{
+				"page.md": {Layout: "base"},
+			},
+			partials: []domain.Partial{
+				{
+					Name:    "cards",
+					Path:    "p/cards.md",
+					Content: "{{ define \"card\" }}card{{ end }}",
+				},
+				{
+					Name:    "base",
+					Path:    "p/base.md",
+					Content: "{{ block \"content\" . }}{{ end }}",
+				},
+			},
+		},
+		{
+			name: "invalid frontmatter YAML",
+			templates: map[string]string{
+				"note.md": "---\ntitle: {{ .title }}\n  tags: [a\n---\nbody",
+			},
+			want: []string{"note.md: frontmatter is not valid YAML"},
+		},
+		{
+			name: "control actions and trim markers keep frontmatter valid",
+			templates: map[string]string{
+				"note.md": "{{- /* comment */ -}}\n---\n" +
+					"{{- if .title }}\ntitle: {{ .title }}\n{{- end }}\n" +
+					"{{- $x := 1 }}\ntags: [{{ .tag }}]\n---\n",
+			},
+		},
+		{
+			name: "invalid frontmatter YAML after a header with actions",
+			templates: map[string]string{
+				"note.md": "{{- /* lithos\n" +
+					"output: x/{{ .title | slug }}.md\n*/ -}}\n" +
+					"---\ntitle: [unclosed\n---\nbody",
+			},
+			want: []string{"note.md: frontmatter is not valid YAML"},
+		},
+		{
+			name: "fields missing from the schema are reported",
+			templates: map[string]string{
+				"project.md": "{{ .title }} {{ .staus }} {{ .owner }}\n" +
+					"{{ range .items }}{{ .name }}{{ $.ghost }}{{ end }}",
+			},
+			headers: map[string]domain.TemplateHeader{
+				"project.md": {
+					Schema: "project",
+					Params: []domain.TemplateParam{{Name: "owner"}},
+				},
+			},
+			want: []string{
+				`project.md:1:17: field "staus" is not defined`,
+				`project.md:2:10: field "items" is not defined`,
+				`project.md:2:34: field "ghost" is not defined`,
+			},
+		},
+		{
+			name: "schema of the frontmatter fileClass is checked",
+			templates: map[string]string{
+				"project.md": "---\nfileClass: project\n" +
+					"title: {{ .title }}\n---\n{{ .staus }}",
+			},
+			want: []string{
+				`project.md:5:4: field "staus" is not defined by ` +
+					`schema "project"`,
+			},
+		},
+		{
+			name: "fileClass set by an action is not checked",
+			templates: map[string]string{
+				"project.md": "---\nfileClass: {{ .kind }}\n---\n",
+			},
+		},
+		{
+			name: "render context keys are not schema fields",
+			templates: map[string]string{
+				"project.md": "{{ .title }} {{ .Target.Basename }} " +
+					"{{ .Now.Year }} {{ .Data.title }}",
+			},
+			headers: map[string]domain.TemplateHeader{
+				"project.md": {Schema: "project"},
+			},
+		},
+		{
+			name: "unknown schema is reported",
+			templates: map[string]string{
+				"project.md": "{{ .title }}",
+			},
+			headers: map[string]domain.TemplateHeader{
+				"project.md": {Schema: "missing"},
+			},
+			want: []string{`project.md: schema "missing" not found`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &lintRepo{
+				templates: tt.templates,
+				headers:   tt.headers,
+				partials:  tt.partials,
+			}
+			linter := NewLinter(repo, nil, projectSchemas())
+
+			issues, err := linter.Check(context.Background(), nil)
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			assertIssues(t, issues, tt.want)
+		})
+	}
+}
+
+func TestLinterCheckPaths(t *testing.T) {
+	fs := testutils.NewMockFileSystemPort()
+	fs.AddFile("draft.md", []byte("{{ .title | nope }}"))
+	repo := &lintRepo{
+		templates: map[string]string{"draft.md": "{{ .title | nope }}"},
+		partials: []domain.Partial{
+			{Name: "stale", Path: "p/stale.md", Content: "stale"},
+		},
+	}
+
+	issues, err := NewLinter(repo, fs, nil).
+		Check(context.Background(), []string{"draft.md"})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	// Unused partials are not reported when only some templates are checked.
+	assertIssues(t, issues, []string{`draft.md:1:13: unknown function "nope"`})
+
+	if _, err := NewLinter(repo, fs, nil).
+		Check(context.Background(), []string{"absent.md"}); err == nil {
+		t.Fatalf("expected an error for an unreadable path")
+	}
+}
+
+func TestLinterInitializesSchemasOnce(t *testing.T) {
+	schemas := projectSchemas()
+	repo := &lintRepo{
+		templates: map[string]string{"a.md": "{{ .title }}", "b.md": "x"},
+		headers: map[string]domain.TemplateHeader{
+			"a.md": {Schema: "project"},
+			"b.md": {Schema: "project"},
+		},
+	}
+
+	if _, err := NewLinter(repo, nil, schemas).
+		Check(context.Background(), nil); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if schemas.initialized != 1 {
+		t.Fatalf("Initialize called %d times, want 1", schemas.initialized)
+	}
+}
+
+func TestLintIssueString(t *testing.T) {
+	tests := []struct {
+		issue LintIssue
+		want  string
+	}{
+		{
+			LintIssue{Path: "a.md", Line: 3, Column: 7, Message: "m"},
+			"a.md:3:7: m",
+		},
+		{LintIssue{Path: "a.md", Line: 3, Message: "m"}, "a.md:3: m"},
+		{LintIssue{Path: "a.md", Message: "m"}, "a.md: m"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.issue.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+// assertIssues checks that each issue starts with the expected text, in
+// order.
+func assertIssues(t *testing.T, issues []LintIssue, want []string) {
+	t.Helper()
+	if len(issues) != len(want) {
+		t.Fatalf("got issues %v, want %v", issues, want)
+	}
+	for i, issue := range issues {
+		if !strings.HasPrefix(issue.String(), want[i]) {
+			t.Errorf("issue %d = %q, want prefix %q", i, issue, want[i])
+		}
+	}
+}
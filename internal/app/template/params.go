@@ -0,0 +1,227 @@
+// Package template provides template processing services for the Lithos
+// application.
+// This file checks render data against the parameters a template declares.
+package template
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JackMatanky/lithos/internal/domain"
+	"github.com/JackMatanky/lithos/internal/shared/errors"
+)
+
+// paramDateLayout is the textual form accepted and produced for date params.
+const paramDateLayout = "2006-01-02"
+
+// ResolveParams checks rc against the parameters declared in the template
+// header and returns a copy of rc in which declared values are converted to
+// their declared types and missing optional values are filled from defaults.
+// Undeclared data passes through unchanged, so templates without params
+// accept anything. Every problem is reported together in a
+// TemplateParamsError.
+func (e *TemplateEngine) ResolveParams(
+	tmpl *domain.Template,
+	rc domain.RenderContext,
+) (domain.RenderContext, error) {
+	if tmpl == nil || len(tmpl.Header.Params) == 0 {
+		return rc, nil
+	}
+
+	resolved := rc
+	resolved.Data = make(map[string]interface{}, len(rc.Data))
+	resolved.Merge(rc.Data)
+
+	var problems []errors.ValidationError
+	for _, param := range tmpl.Header.Params {
+		value, fromDefault, ok := paramValue(param, rc.Data)
+		if !ok {
+			if param.Required {
+				problems = append(problems, errors.NewValidationError(
+					param.Name,
+					"is required but missing",
+					nil,
+				))
+			}
+			continue
+		}
+
+		converted, err := convertParamValue(param.Type, value, e.location)
+		if err != nil {
+			reason := err.Error()
+			if fromDefault {
+				reason = "invalid default: " + reason
+			}
+			problems = append(problems, errors.NewValidationError(
+				param.Name,
+				reason,
+				value,
+			))
+			continue
+		}
+		resolved.Data[param.Name] = converted
+	}
+
+	if len(problems) > 0 {
+		return rc, errors.NewTemplateParamsError(tmpl.Name, problems)
+	}
+	return resolved, nil
+}
+
+// paramValue returns the value to use for param: the supplied value, or its
+// default when nothing (or an empty string) was supplied. ok is false when
+// neither is available.
+func paramValue(
+	param domain.TemplateParam,
+	data map[string]interface{},
+) (value interface{}, fromDefault, ok bool) {
+	if supplied, exists := data[param.Name]; exists &&
+		supplied != nil && supplied != "" {
+		return supplied, false, true
+	}
+	if param.Default != nil {
+		return param.Default, true, true
+	}
+	return nil, false, false
+}
+
+// convertParamValue converts value to the Go representation of paramType.
+// Strings are parsed, so values from key=value pairs satisfy typed params.
+// Dates without a zone are read in location.
+func convertParamValue(
+	paramType string,
+	value interface{},
+	location *time.Location,
+) (interface{}, error) {
+	switch paramType {
+	case domain.ParamTypeInteger:
+		return toInteger(value)
+	case domain.ParamTypeNumber:
+		return toNumber(value)
+	case domain.ParamTypeBool:
+		return toBool(value)
+	case domain.ParamTypeDate:
+		return toDate(value, location)
+	case domain.ParamTypeList:
+		return toList(value)
+	default:
+		return toString(value)
+	}
+}
+
+// toString accepts any scalar and renders it as a string.
+func toString(value interface{}) (interface{}, error) {
+	switch typed := value.(type) {
+	case string:
+		return typed, nil
+	case time.Time:
+		return typed.Format(paramDateLayout), nil
+	case bool, int, int64, uint64, float64:
+		return fmt.Sprint(typed), nil
+	default:
+		return nil, fmt.Errorf("must be a string")
+	}
+}
+
+// toInteger accepts whole numbers and strings holding them.
+func toInteger(value interface{}) (interface{}, error) {
+	switch typed := value.(type) {
+	case int:
+		return typed, nil
+	case int64:
+		return int(typed), nil
+	case uint64:
+		return int(typed), nil
+	case float64:
+		if typed == float64(int(typed)) {
+			return int(typed), nil
+		}
+	case string:
+		if parsed, err := strconv.Atoi(strings.TrimSpace(typed)); err == nil {
+			return parsed, nil
+		}
+	}
+	return nil, fmt.Errorf("must be an integer")
+}
+
+// toNumber accepts any number and strings holding one.
+func toNumber(value interface{}) (interface{}, error) {
+	switch typed := value.(type) {
+	case int:
+		return float64(typed), nil
+	case int64:
+		return float64(typed), nil
+	case uint64:
+		return float64(typed), nil
+	case float64:
+		return typed, nil
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(typed), 64)
+		if err == nil {
+			return parsed, nil
+		}
+	}
+	return nil, fmt.Errorf("must be a number")
+}
+
+// toBool accepts booleans and the strings understood by strconv.ParseBool.
+func toBool(value interface{}) (interface{}, error) {
+	switch typed := value.(type) {
+	case bool:
+		return typed, nil
+	case string:
+		parsed, err := strconv.ParseBool(strings.TrimSpace(typed))
+		if err == nil {
+			return parsed, nil
+		}
+	}
+	return nil, fmt.Errorf("must be true or false")
+}
+
+// toDate accepts time values and YYYY-MM-DD or RFC 3339 strings. Plain dates
+// are midnight in location, the zone the date functions work in. The result
+// is a time.Time, so templates print it with dateFormat rather than directly.
+func toDate(
+	value interface{},
+	location *time.Location,
+) (interface{}, error) {
+	switch typed := value.(type) {
+	case time.Time:
+		return typed, nil
+	case string:
+		text := strings.TrimSpace(typed)
+		for _, layout := range []string{paramDateLayout, time.RFC3339} {
+			parsed, err := time.ParseInLocation(layout, text, location)
+			if err == nil {
+				return parsed, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("must be a date (YYYY-MM-DD)")
+}
+
+// toList accepts lists and comma-separated strings.
+func toList(value interface{}) (interface{}, error) {
+	switch typed := value.(type) {
+	case []interface{}:
+		return typed, nil
+	case []string:
+		items := make([]interface{}, len(typed))
+		for i, item := range typed {
+			items[i] = item
+		}
+		return items, nil
+	case string:
+		items := make([]interface{}, 0)
+		for _, item := range strings.Split(typed, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("must be a list")
+	}
+}
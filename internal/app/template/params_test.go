@@ -0,0 +1,223 @@
+package template
+
+import (
+	stderrors "errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JackMatanky/lithos/internal/domain"
+	"github.com/JackMatanky/lithos/internal/shared/errors"
+)
+
+func TestTemplateEngine_ResolveParams(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	params := []domain.TemplateParam{
+		{Name: "title", Type: domain.ParamTypeString, Required: true},
+		{Name: "count", Type: domain.ParamTypeInteger, Default: 1},
+		{Name: "ratio", Type: domain.ParamTypeNumber},
+		{Name: "draft", Type: domain.ParamTypeBool, Default: false},
+		{Name: "due", Type: domain.ParamTypeDate},
+		{Name: "tags", Type: domain.ParamTypeList},
+	}
+
+	tests := []struct {
+		name     string
+		params   []domain.TemplateParam
+		data     map[string]interface{}
+		want     map[string]interface{}
+		wantErrs []string
+	}{
+		{
+			name:   "no declared params passes data through",
+			params: nil,
+			data:   map[string]interface{}{"anything": "goes"},
+			want:   map[string]interface{}{"anything": "goes"},
+		},
+		{
+			name:   "strings are converted and defaults applied",
+			params: params,
+			data: map[string]interface{}{
+				"title": "Sync",
+				"ratio": "0.5",
+				"due":   "2025-03-14",
+				"tags":  "a, b,,c",
+				"extra": "kept",
+			},
+			want: map[string]interface{}{
+				"title": "Sync",
+				"count": 1,
+				"ratio": 0.5,
+				"draft": false,
+				"due":   time.Date(2025, 3, 14, 0, 0, 0, 0, tokyo),
+				"tags":  []interface{}{"a", "b", "c"},
+				"extra": "kept",
+			},
+		},
+		{
+			name:   "decoded JSON and YAML values are accepted",
+			params: params,
+			data: map[string]interface{}{
+				"title": 2025,
+				"count": float64(3),
+				"draft": true,
+				"tags":  []interface{}{"x"},
+			},
+			want: map[string]interface{}{
+				"title": "2025",
+				"count": 3,
+				"draft": true,
+				"tags":  []interface{}{"x"},
+			},
+		},
+		{
+			name:   "empty string falls back to default",
+			params: params,
+			data:   map[string]interface{}{"title": "Sync", "count": ""},
+			want: map[string]interface{}{
+				"title": "Sync",
+				"count": 1,
+				"draft": false,
+			},
+		},
+		{
+			name:   "all problems are reported",
+			params: params,
+			data: map[string]interface{}{
+				"count": "many",
+				"draft": "maybe",
+				"due":   "14/03/2025",
+				"tags":  map[string]interface{}{},
+			},
+			wantErrs: []string{
+				"title: is required but missing",
+				"count: must be an integer (got many)",
+				"draft: must be true or false (got maybe)",
+				"due: must be a date (YYYY-MM-DD) (got 14/03/2025)",
+				"tags: must be a list",
+			},
+		},
+		{
+			name: "invalid default",
+			params: []domain.TemplateParam{
+				{Name: "count", Type: domain.ParamTypeInteger, Default: "x"},
+			},
+			data:     map[string]interface{}{},
+			wantErrs: []string{"count: invalid default: must be an integer"},
+		},
+	}
+
+	engine := NewTemplateEngineWithOptions(
+		NewStaticTemplateParser(),
+		NewGoTemplateExecutor(),
+		FuncMapOptions{Location: tokyo},
+	)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := &domain.Template{
+				Name:   "meeting",
+				Header: domain.TemplateHeader{Params: tt.params},
+			}
+			rc := domain.NewRenderContext()
+			rc.Merge(tt.data)
+
+			resolved, err := engine.ResolveParams(tmpl, rc)
+
+			if len(tt.wantErrs) > 0 {
+				var paramsErr errors.TemplateParamsError
+				if !stderrors.As(err, &paramsErr) {
+					t.Fatalf("ResolveParams() error = %v, want params error",
+						err)
+				}
+				if len(paramsErr.Problems()) != len(tt.wantErrs) {
+					t.Errorf("got %d problems, want %d:\n%v",
+						len(paramsErr.Problems()), len(tt.wantErrs), err)
+				}
+				for _, want := range tt.wantErrs {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("error %q missing %q", err, want)
+					}
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ResolveParams() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(resolved.Data, tt.want) {
+				t.Errorf("ResolveParams() = %#v, want %#v",
+					resolved.Data, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateEngine_ExecuteParsedTemplate_ValidatesParams(t *testing.T) {
+	engine := NewTemplateEngine(
+		NewStaticTemplateParser(),
+		NewGoTemplateExecutor(),
+	)
+	parsed := NewStaticTemplateParser().Parse(t.Context(), "{{.count}}")
+	tmpl := &domain.Template{
+		Name:   "counter",
+		Parsed: parsed.Value(),
+		Header: domain.TemplateHeader{Params: []domain.TemplateParam{
+			{Name: "count", Type: domain.ParamTypeInteger, Default: 2},
+		}},
+	}
+
+	got, err := engine.ExecuteParsedTemplate(
+		t.Context(),
+		tmpl,
+		domain.NewRenderContext(),
+	)
+	if err != nil {
+		t.Fatalf("ExecuteParsedTemplate() unexpected error = %v", err)
+	}
+	if got != "2" {
+		t.Errorf("ExecuteParsedTemplate() = %q, want %q", got, "2")
+	}
+
+	rc := domain.NewRenderContext()
+	rc.Merge(map[string]interface{}{"count": "two"})
+	if _, err = engine.ExecuteParsedTemplate(t.Context(), tmpl, rc); err == nil {
+		t.Error("ExecuteParsedTemplate() expected params error, got nil")
+	}
+}
+
+func TestTemplateEngine_ExecuteParsedTemplate_RendersDateParams(t *testing.T) {
+	opts := FuncMapOptions{Location: time.FixedZone("JST", 9*60*60)}
+	parser := NewStaticTemplateParserWithOptions(opts)
+	engine := NewTemplateEngineWithOptions(
+		parser,
+		NewGoTemplateExecutor(),
+		opts,
+	)
+	parsed := parser.Parse(
+		t.Context(),
+		`{{ .due | dateFormat "2006-01-02" }} `+
+			`{{ .due | dateAdd "1d" | dateFormat "Mon" }}`,
+	)
+	if parsed.IsErr() {
+		t.Fatalf("Parse() unexpected error = %v", parsed.Error())
+	}
+	tmpl := &domain.Template{
+		Name:   "deadline",
+		Parsed: parsed.Value(),
+		Header: domain.TemplateHeader{Params: []domain.TemplateParam{
+			{Name: "due", Type: domain.ParamTypeDate, Required: true},
+		}},
+	}
+
+	rc := domain.NewRenderContext()
+	rc.Merge(map[string]interface{}{"due": "2025-03-14"})
+	got, err := engine.ExecuteParsedTemplate(t.Context(), tmpl, rc)
+	if err != nil {
+		t.Fatalf("ExecuteParsedTemplate() unexpected error = %v", err)
+	}
+	if want := "2025-03-14 Sat"; got != want {
+		t.Errorf("ExecuteParsedTemplate() = %q, want %q", got, want)
+	}
+}
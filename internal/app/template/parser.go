@@ -5,17 +5,29 @@ import (
 	"context"
 	"text/template"
 
+	"github.com/JackMatanky/lithos/internal/domain"
 	"github.com/JackMatanky/lithos/internal/ports/spi"
 	"github.com/JackMatanky/lithos/internal/shared/errors"
 )
 
 // StaticTemplateParser implements spi.TemplateParser using Go's text/template
 // engine with custom functions for enhanced template capabilities.
-type StaticTemplateParser struct{}
+type StaticTemplateParser struct {
+	funcOptions FuncMapOptions
+}
 
-// NewStaticTemplateParser creates a new StaticTemplateParser instance.
+// NewStaticTemplateParser creates a new StaticTemplateParser instance whose
+// date functions work in the local time zone.
 func NewStaticTemplateParser() *StaticTemplateParser {
-	return &StaticTemplateParser{}
+	return NewStaticTemplateParserWithOptions(FuncMapOptions{})
+}
+
+// NewStaticTemplateParserWithOptions creates a StaticTemplateParser whose
+// template functions are configured by opts.
+func NewStaticTemplateParserWithOptions(
+	opts FuncMapOptions,
+) *StaticTemplateParser {
+	return &StaticTemplateParser{funcOptions: opts}
 }
 
 // Parse parses the template content using Go's text/template engine.
@@ -24,6 +36,18 @@ func NewStaticTemplateParser() *StaticTemplateParser {
 func (p *StaticTemplateParser) Parse(
 	ctx context.Context,
 	content string,
+) errors.Result[*template.Template] {
+	return p.ParseWithPartials(ctx, content, nil)
+}
+
+// ParseWithPartials parses each partial into a shared namespace under its own
+// name and then parses the template content into the same namespace. Because
+// the content is parsed last, its {{define}}s replace {{block}} defaults
+// declared by partials.
+func (p *StaticTemplateParser) ParseWithPartials(
+	ctx context.Context,
+	content string,
+	partials []domain.Partial,
 ) errors.Result[*template.Template] {
 	// Check for context cancellation before starting
 	if p.checkContextCancellation(ctx) {
@@ -33,6 +57,11 @@ func (p *StaticTemplateParser) Parse(
 	// Create a new template with custom functions registered
 	tmpl := p.createTemplate()
 
+	// Register partials before the template so it can override their blocks
+	if err := p.parsePartials(tmpl, partials); err != nil {
+		return errors.Err[*template.Template](err)
+	}
+
 	// Parse the template content
 	parsedTemplate, err := p.parseTemplate(tmpl, content)
 	if err != nil {
@@ -58,16 +87,45 @@ func (p *StaticTemplateParser) checkContextCancellation(
 // createTemplate creates a new template with custom functions registered.
 // Returns a template ready for parsing.
 func (p *StaticTemplateParser) createTemplate() *template.Template {
-	return template.New("template").Funcs(NewFuncMap())
+	return template.New(rootParseName).Funcs(
+		NewFuncMapWithOptions(p.funcOptions),
+	)
+}
+
+// parsePartials parses each partial into the namespace of tmpl.
+func (p *StaticTemplateParser) parsePartials(
+	tmpl *template.Template,
+	partials []domain.Partial,
+) error {
+	for _, partial := range partials {
+		if _, err := tmpl.New(partial.Name).Parse(partial.Content); err != nil {
+			return positionedError(
+				err,
+				"",
+				map[string]string{partial.Name: partial.Content},
+			)
+		}
+	}
+	return nil
 }
 
 // parseTemplate parses the given content using the provided template.
-// Returns the parsed template or an error if parsing fails.
+// Returns the parsed template or a TemplateError locating the problem. The
+// parser does not know the template's name, so the error is left unnamed for
+// the caller to label with TemplateError.WithTemplate.
 func (p *StaticTemplateParser) parseTemplate(
 	tmpl *template.Template,
 	content string,
 ) (*template.Template, error) {
-	return tmpl.Parse(content)
+	parsed, err := tmpl.Parse(content)
+	if err != nil {
+		return nil, positionedError(
+			err,
+			"",
+			map[string]string{rootParseName: content},
+		)
+	}
+	return parsed, nil
 }
 
 // Ensure StaticTemplateParser implements spi.TemplateParser.
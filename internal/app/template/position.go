@@ -0,0 +1,76 @@
+// Package template provides domain services for template parsing and execution.
+// This file turns text/template errors into TemplateErrors that point at the
+// offending line and column.
+package template
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/JackMatanky/lithos/internal/shared/errors"
+)
+
+// rootParseName is the name the parser gives the template body. Positions
+// reported against it belong to the template itself rather than a partial.
+const rootParseName = "template"
+
+// templateErrorPattern matches the location text/template puts in front of
+// parse errors ("template: NAME:LINE: reason") and execution errors
+// ("template: NAME:LINE:COL: reason"). COL is a 0-based byte offset.
+var templateErrorPattern = regexp.MustCompile(
+	`(?s)^template: ([^:]+):(\d+):(?:(\d+):)? (.*)$`,
+)
+
+// executingPattern matches the `executing "NAME" at ` preamble of execution
+// errors, which repeats the internal template name.
+var executingPattern = regexp.MustCompile(`^executing "[^"]*" at `)
+
+// positionedError converts a text/template error into a TemplateError with
+// the line, column, and source line of the problem. rootName labels errors
+// in the template body (it may be empty when the caller labels them later);
+// errors in partials are labelled with the partial name. sources maps parse
+// names to their source text, with the body under rootParseName. Errors
+// without a recognisable position yield a TemplateError without one.
+func positionedError(
+	err error,
+	rootName string,
+	sources map[string]string,
+) errors.TemplateError {
+	match := templateErrorPattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return errors.NewTemplateErrorAt(rootName, 0, 0, "", err.Error(), err)
+	}
+
+	parseName := match[1]
+	line, _ := strconv.Atoi(match[2])
+	column := 0
+	if match[3] != "" {
+		offset, _ := strconv.Atoi(match[3])
+		column = offset + 1
+	}
+	reason := executingPattern.ReplaceAllString(match[4], "")
+
+	name := parseName
+	if parseName == rootParseName {
+		name = rootName
+	}
+
+	return errors.NewTemplateErrorAt(
+		name,
+		line,
+		column,
+		sourceLine(sources[parseName], line),
+		reason,
+		err,
+	)
+}
+
+// sourceLine returns the 1-based line of source, or "" when out of range.
+func sourceLine(source string, line int) string {
+	lines := strings.Split(source, "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return strings.TrimRight(lines[line-1], "\r")
+}
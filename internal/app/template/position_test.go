@@ -0,0 +1,143 @@
+package template
+
+import (
+	stderrors "errors"
+	"testing"
+
+	"github.com/JackMatanky/lithos/internal/domain"
+	"github.com/JackMatanky/lithos/internal/shared/errors"
+)
+
+func TestParse_ErrorPosition(t *testing.T) {
+	content := "# Title\n\n{{ .title | nope }}\n"
+
+	result := NewStaticTemplateParser().Parse(t.Context(), content)
+	if !result.IsErr() {
+		t.Fatal("Parse() expected error for undefined function")
+	}
+
+	var templateErr errors.TemplateError
+	if !stderrors.As(result.Error(), &templateErr) {
+		t.Fatalf("Parse() error = %T, want TemplateError", result.Error())
+	}
+	if templateErr.Template() != "" || templateErr.Line() != 3 {
+		t.Errorf("position = %q line %d, want unnamed line 3",
+			templateErr.Template(), templateErr.Line())
+	}
+	want := "   3 | {{ .title | nope }}"
+	if got := templateErr.Excerpt(); got != want {
+		t.Errorf("Excerpt() = %q, want %q", got, want)
+	}
+}
+
+func TestParseWithPartials_PartialErrorPosition(t *testing.T) {
+	partials := []domain.Partial{
+		{Name: "header", Content: "# Header\n{{ if }}\n"},
+	}
+
+	result := NewStaticTemplateParser().ParseWithPartials(
+		t.Context(),
+		"body",
+		partials,
+	)
+
+	var templateErr errors.TemplateError
+	if !stderrors.As(result.Error(), &templateErr) {
+		t.Fatalf("ParseWithPartials() error = %v, want TemplateError",
+			result.Error())
+	}
+	if templateErr.Template() != "header" || templateErr.Line() != 2 {
+		t.Errorf("position = %q line %d, want header line 2",
+			templateErr.Template(), templateErr.Line())
+	}
+}
+
+func TestExecute_ErrorPosition(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		partials     []domain.Partial
+		wantTemplate string
+		wantLine     int
+		wantColumn   int
+		wantExcerpt  string
+	}{
+		{
+			name:         "error in template body",
+			content:      "# {{.title}}\n\n\tItems: {{index .items 5}}\n",
+			wantTemplate: "meeting",
+			wantLine:     3,
+			wantColumn:   11,
+			wantExcerpt: "   3 | \tItems: {{index .items 5}}\n" +
+				"     | \t         ^",
+		},
+		{
+			name:    "error in partial",
+			content: `{{template "footer" .}}`,
+			partials: []domain.Partial{
+				{Name: "footer", Content: "---\n{{index .items 5}}"},
+			},
+			wantTemplate: "footer",
+			wantLine:     2,
+			wantColumn:   3,
+			wantExcerpt: "   2 | {{index .items 5}}\n" +
+				"     |   ^",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed := NewStaticTemplateParser().ParseWithPartials(
+				t.Context(),
+				tt.content,
+				tt.partials,
+			)
+			if parsed.IsErr() {
+				t.Fatalf("ParseWithPartials() error = %v", parsed.Error())
+			}
+			tmpl := &domain.Template{
+				Name:     "meeting",
+				Content:  tt.content,
+				Parsed:   parsed.Value(),
+				Partials: tt.partials,
+			}
+
+			result := NewGoTemplateExecutor().Execute(
+				t.Context(),
+				tmpl,
+				map[string]interface{}{"items": []string{"a"}},
+			)
+
+			var templateErr errors.TemplateError
+			if !stderrors.As(result.Error(), &templateErr) {
+				t.Fatalf("Execute() error = %v, want TemplateError",
+					result.Error())
+			}
+			if templateErr.Template() != tt.wantTemplate ||
+				templateErr.Line() != tt.wantLine ||
+				templateErr.Column() != tt.wantColumn {
+				t.Errorf("position = %q %d:%d, want %q %d:%d",
+					templateErr.Template(), templateErr.Line(),
+					templateErr.Column(), tt.wantTemplate, tt.wantLine,
+					tt.wantColumn)
+			}
+			if got := templateErr.Excerpt(); got != tt.wantExcerpt {
+				t.Errorf("Excerpt() =\n%s\nwant\n%s", got, tt.wantExcerpt)
+			}
+		})
+	}
+}
+
+func TestPositionedError_WithoutPosition(t *testing.T) {
+	cause := stderrors.New("something odd")
+
+	err := positionedError(cause, "meeting", nil)
+
+	if err.Line() != 0 || err.Excerpt() != "" {
+		t.Errorf("positionedError() = line %d excerpt %q, want no position",
+			err.Line(), err.Excerpt())
+	}
+	if !stderrors.Is(err, cause) {
+		t.Error("positionedError() should wrap the original error")
+	}
+}
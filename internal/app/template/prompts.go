@@ -0,0 +1,193 @@
+// Package template provides domain services for template processing.
+// This file contains the prompt and suggester template functions, which ask
+// the user for values while a template renders.
+package template
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+)
+
+// errUnanswered stops a render at a question that has not been answered yet.
+var errUnanswered = stderrors.New("waiting for an answer")
+
+// Prompter answers the prompt and suggester template functions through a
+// PromptPort, remembering every answer so each question is asked once per
+// run, however often templates ask it.
+//
+// Questions are not asked while a template executes, where time spent
+// waiting for the user would count against the render timeout. Instead a
+// render stops at the first question without an answer, the engine asks it,
+// and the render starts over with the answer known.
+type Prompter struct {
+	port    spi.PromptPort
+	mu      sync.Mutex
+	answers map[string]string
+	pending *question
+}
+
+// question is a question a render stopped at. Exactly one of its fields is
+// set.
+type question struct {
+	prompt    *spi.PromptConfig
+	suggester *spi.SuggesterConfig
+}
+
+// label returns the label the question is asked, and its answer kept, under.
+func (q question) label() string {
+	if q.prompt != nil {
+		return q.prompt.Label
+	}
+	return q.suggester.Label
+}
+
+// NewPrompter creates a Prompter asking its questions through port.
+func NewPrompter(port spi.PromptPort) *Prompter {
+	return &Prompter{port: port, answers: make(map[string]string)}
+}
+
+// prompt implements the prompt template function: `{{prompt "Title"}}` or,
+// with a default for an empty answer, `{{prompt "Title" "Untitled"}}`.
+func (p *Prompter) prompt(label string, defaults ...string) (string, error) {
+	if len(defaults) > 1 {
+		return "", fmt.Errorf(
+			"prompt %q: expected at most one default, got %d",
+			label,
+			len(defaults),
+		)
+	}
+	cfg := spi.PromptConfig{Label: label}
+	if len(defaults) == 1 {
+		cfg.Default = defaults[0]
+	}
+	return p.answer(question{prompt: &cfg})
+}
+
+// suggester implements the suggester template function:
+// `{{suggester "Status" .options}}` offers every item of a list, or every
+// key of a map, and returns the chosen item, or the value of the chosen key.
+func (p *Prompter) suggester(label string, items interface{}) (string, error) {
+	options, err := suggesterOptions(items)
+	if err != nil {
+		return "", fmt.Errorf("suggester %q: %w", label, err)
+	}
+	cfg := spi.SuggesterConfig{Label: label, Options: options}
+	return p.answer(question{suggester: &cfg})
+}
+
+// answer returns the known answer to q, or records q as pending and stops
+// the render to have it asked.
+func (p *Prompter) answer(q question) (string, error) {
+	if p == nil || p.port == nil {
+		return "", fmt.Errorf(
+			"%q cannot be asked: interactive input is not available",
+			q.label(),
+		)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if answer, ok := p.answers[q.label()]; ok {
+		return answer, nil
+	}
+	p.pending = &q
+	return "", errUnanswered
+}
+
+// askPending asks the question the last render stopped at and remembers the
+// answer. It reports false when no question is pending, meaning the render
+// failed for another reason.
+func (p *Prompter) askPending(ctx context.Context) (bool, error) {
+	if p == nil {
+		return false, nil
+	}
+
+	p.mu.Lock()
+	q := p.pending
+	p.pending = nil
+	p.mu.Unlock()
+	if q == nil {
+		return false, nil
+	}
+
+	var answer string
+	var err error
+	if q.prompt != nil {
+		answer, err = p.port.Prompt(ctx, *q.prompt)
+	} else {
+		answer, err = p.port.Suggester(ctx, *q.suggester)
+	}
+	if err != nil {
+		return true, fmt.Errorf("failed to ask %q: %w", q.label(), err)
+	}
+
+	p.mu.Lock()
+	p.answers[q.label()] = answer
+	p.mu.Unlock()
+	return true, nil
+}
+
+// dropPending forgets the question the last render stopped at without
+// asking it, and returns its label. It reports false when no question is
+// pending.
+func (p *Prompter) dropPending() (string, bool) {
+	if p == nil {
+		return "", false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	q := p.pending
+	p.pending = nil
+	if q == nil {
+		return "", false
+	}
+	return q.label(), true
+}
+
+// suggesterOptions converts the items of a suggester into options: a list
+// offers its items, and a map offers its keys, in sorted order, standing for
+// their values.
+func suggesterOptions(items interface{}) ([]spi.SuggesterOption, error) {
+	var options []spi.SuggesterOption
+	if v := reflect.ValueOf(items); v.Kind() == reflect.Map {
+		labels := make([]string, 0, v.Len())
+		values := make(map[string]string, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			label := fmt.Sprint(iter.Key().Interface())
+			labels = append(labels, label)
+			values[label] = fmt.Sprint(iter.Value().Interface())
+		}
+		sort.Strings(labels)
+		for _, label := range labels {
+			options = append(options, spi.SuggesterOption{
+				Label: label,
+				Value: values[label],
+			})
+		}
+	} else {
+		list, err := toSlice("options", items)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list {
+			text := fmt.Sprint(item)
+			options = append(options, spi.SuggesterOption{
+				Label: text,
+				Value: text,
+			})
+		}
+	}
+
+	if len(options) == 0 {
+		return nil, stderrors.New("no options to choose from")
+	}
+	return options, nil
+}
@@ -0,0 +1,226 @@
+package template
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"testing"
+
+	"github.com/JackMatanky/lithos/internal/domain"
+	"github.com/JackMatanky/lithos/internal/ports/spi"
+)
+
+// fakePromptPort answers every question from answers, counting how often
+// each label is asked.
+type fakePromptPort struct {
+	answers map[string]string
+	asked   map[string]int
+}
+
+func newFakePromptPort(answers map[string]string) *fakePromptPort {
+	return &fakePromptPort{answers: answers, asked: make(map[string]int)}
+}
+
+func (p *fakePromptPort) Prompt(
+	_ context.Context,
+	cfg spi.PromptConfig,
+) (string, error) {
+	p.asked[cfg.Label]++
+	answer, ok := p.answers[cfg.Label]
+	if !ok {
+		return "", stderrors.New("no answer")
+	}
+	if answer == "" {
+		return cfg.Default, nil
+	}
+	return answer, nil
+}
+
+func (p *fakePromptPort) Suggester(
+	_ context.Context,
+	cfg spi.SuggesterConfig,
+) (string, error) {
+	p.asked[cfg.Label]++
+	for _, option := range cfg.Options {
+		if option.Label == p.answers[cfg.Label] {
+			return option.Value, nil
+		}
+	}
+	return "", stderrors.New("no such option")
+}
+
+func (p *fakePromptPort) Confirm(
+	_ context.Context,
+	cfg spi.ConfirmConfig,
+) (bool, error) {
+	p.asked[cfg.Label]++
+	return p.answers[cfg.Label] == "yes", nil
+}
+
+func TestTemplateEngine_Prompts(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		data      map[string]interface{}
+		answers   map[string]string
+		want      string
+		wantAsked map[string]int
+		wantErr   string
+	}{
+		{
+			name:      "prompt",
+			content:   `# {{prompt "Title"}}`,
+			answers:   map[string]string{"Title": "Weekly Sync"},
+			want:      "# Weekly Sync",
+			wantAsked: map[string]int{"Title": 1},
+		},
+		{
+			name:      "prompt default",
+			content:   `{{prompt "Status" "draft"}}`,
+			answers:   map[string]string{"Status": ""},
+			want:      "draft",
+			wantAsked: map[string]int{"Status": 1},
+		},
+		{
+			name: "each question is asked once",
+			content: `{{prompt "Title"}} {{prompt "Owner"}} ` +
+				`{{prompt "Title" | toUpper}}`,
+			answers:   map[string]string{"Title": "sync", "Owner": "Ann"},
+			want:      "sync Ann SYNC",
+			wantAsked: map[string]int{"Title": 1, "Owner": 1},
+		},
+		{
+			name:      "suggester over a list",
+			content:   `{{suggester "Pick" .options}}`,
+			data:      map[string]interface{}{"options": []string{"a", "b"}},
+			answers:   map[string]string{"Pick": "b"},
+			want:      "b",
+			wantAsked: map[string]int{"Pick": 1},
+		},
+		{
+			name:    "suggester over a map",
+			content: `{{suggester "Status" .options}}`,
+			data: map[string]interface{}{
+				"options": map[string]string{"Done": "done", "Open": "open"},
+			},
+			answers:   map[string]string{"Status": "Open"},
+			want:      "open",
+			wantAsked: map[string]int{"Status": 1},
+		},
+		{
+			name:    "suggester without options",
+			content: `{{suggester "Pick" .options}}`,
+			data:    map[string]interface{}{"options": []string{}},
+			wantErr: `suggester "Pick": no options to choose from`,
+		},
+		{
+			name:      "failed question",
+			content:   `{{prompt "Title"}}`,
+			wantAsked: map[string]int{"Title": 1},
+			wantErr:   `failed to ask "Title": no answer`,
+		},
+		{
+			name:    "too many defaults",
+			content: `{{prompt "Title" "a" "b"}}`,
+			wantErr: "expected at most one default, got 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port := newFakePromptPort(tt.answers)
+			opts := FuncMapOptions{Prompter: NewPrompter(port)}
+			parser := NewStaticTemplateParserWithOptions(opts)
+			engine := NewTemplateEngineWithOptions(
+				parser,
+				NewGoTemplateExecutor(),
+				opts,
+			)
+
+			rc := domain.NewRenderContext()
+			rc.Merge(tt.data)
+			got, err := engine.ProcessTemplate(
+				context.Background(),
+				tt.content,
+				"note",
+				rc,
+			)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ProcessTemplate() error = %v, want %q",
+						err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("ProcessTemplate() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ProcessTemplate() = %q, want %q", got, tt.want)
+			}
+			for label, want := range tt.wantAsked {
+				if port.asked[label] != want {
+					t.Errorf("%q asked %d times, want %d",
+						label, port.asked[label], want)
+				}
+			}
+		})
+	}
+}
+
+func TestTemplateEngine_PromptsWithoutPrompter(t *testing.T) {
+	parser := NewStaticTemplateParser()
+	engine := NewTemplateEngine(parser, NewGoTemplateExecutor())
+
+	_, err := engine.ProcessTemplate(
+		context.Background(),
+		`{{prompt "Title"}}`,
+		"note",
+		domain.NewRenderContext(),
+	)
+	want := `"Title" cannot be asked: interactive input is not available`
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("ProcessTemplate() error = %v, want %q", err, want)
+	}
+}
+
+func TestTemplateEngine_ExecuteParsedTemplateUnattended(t *testing.T) {
+	port := newFakePromptPort(map[string]string{"Title": "Sync"})
+	opts := FuncMapOptions{Prompter: NewPrompter(port)}
+	parser := NewStaticTemplateParserWithOptions(opts)
+	engine := NewTemplateEngineWithOptions(
+		parser,
+		NewGoTemplateExecutor(),
+		opts,
+	)
+	parsed := parser.Parse(context.Background(), `# {{prompt "Title"}}`)
+	if parsed.IsErr() {
+		t.Fatalf("Parse() unexpected error = %v", parsed.Error())
+	}
+	tmpl := &domain.Template{Name: "note", Parsed: parsed.Value()}
+
+	_, err := engine.ExecuteParsedTemplateUnattended(
+		context.Background(),
+		tmpl,
+		domain.NewRenderContext(),
+	)
+	want := `template asks "Title", which cannot be answered here`
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("ExecuteParsedTemplateUnattended() error = %v, want %q",
+			err, want)
+	}
+	if port.asked["Title"] != 0 {
+		t.Errorf("%q asked %d times, want 0", "Title", port.asked["Title"])
+	}
+
+	// The question is not left pending for the next render
+	got, err := engine.ExecuteParsedTemplate(
+		context.Background(),
+		tmpl,
+		domain.NewRenderContext(),
+	)
+	if err != nil {
+		t.Fatalf("ExecuteParsedTemplate() unexpected error = %v", err)
+	}
+	if got != "# Sync" {
+		t.Errorf("ExecuteParsedTemplate() = %q, want %q", got, "# Sync")
+	}
+}
@@ -0,0 +1,140 @@
+// Package template provides domain services for template processing.
+// This file contains the generation of starter templates from schemas.
+package template
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/JackMatanky/lithos/internal/domain"
+)
+
+// fileClassKey is the frontmatter key naming the schema a note follows.
+const fileClassKey = "fileClass"
+
+// paramTypes maps the types of properties filled in from template data to
+// the header param types they are declared with, so that values given with
+// --set are converted before they are written to the frontmatter.
+var paramTypes = map[string]string{
+	"string": domain.ParamTypeString,
+	"file":   domain.ParamTypeString,
+	"number": domain.ParamTypeNumber,
+	"bool":   domain.ParamTypeBool,
+}
+
+// Scaffold returns a starter template for notes of schema. The template
+// declares the schema in its header and opens with a frontmatter block that
+// sets fileClass and lists every resolved property, required ones first and
+// each group by name, so scaffolding a schema twice gives the same template.
+// Date properties are pre-filled with the current time in their format and
+// lists start empty. Every other property is declared as a header param of
+// its type and filled in from template data, so the rendered note matches
+// the schema; optional ones are left out of the note when no value is given.
+// The allowed values of enum properties are noted in comments.
+func Scaffold(schema domain.Schema) (string, error) {
+	properties := schema.OrderedProperties()
+
+	var params, fields strings.Builder
+	for _, property := range properties {
+		if property.Name == fileClassKey {
+			continue
+		}
+		typeName, err := property.TypeName()
+		if err != nil {
+			return "", fmt.Errorf(
+				"cannot scaffold property %q of schema %q: %w",
+				property.Name,
+				schema.Name,
+				err,
+			)
+		}
+		if paramType, ok := paramTypes[typeName]; ok && !property.Array {
+			fmt.Fprintf(&params, "  - name: %s\n", property.Name)
+			fmt.Fprintf(&params, "    type: %s\n", paramType)
+			if property.Required {
+				params.WriteString("    required: true\n")
+			}
+		}
+		fields.WriteString(scaffoldProperty(property, typeName))
+	}
+
+	var b strings.Builder
+	b.WriteString("{{- /* lithos\n")
+	fmt.Fprintf(&b, "description: Scaffolded from the %s schema\n", schema.Name)
+	fmt.Fprintf(&b, "schema: %s\n", schema.Name)
+	if params.Len() > 0 {
+		b.WriteString("params:\n")
+		b.WriteString(params.String())
+	}
+	b.WriteString("*/ -}}\n")
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "%s: %s\n", fileClassKey, schema.Name)
+	b.WriteString(fields.String())
+	b.WriteString("---\n")
+	return b.String(), nil
+}
+
+// scaffoldProperty returns the frontmatter line for property of the given
+// type, including its trailing newline.
+func scaffoldProperty(property domain.Property, typeName string) string {
+	var value string
+	switch {
+	case property.Array:
+		value = " []"
+	case typeName == "date":
+		value = fmt.Sprintf(" {{ now %q }}", dateLayout(property.Spec))
+	default:
+		value = fmt.Sprintf(" {{ %s | quoteYAML }}", dataField(property.Name))
+	}
+
+	var notes []string
+	if property.Required {
+		notes = append(notes, "required")
+	}
+	if enum := enumValues(property.Spec); len(enum) > 0 {
+		notes = append(notes, "one of: "+strings.Join(enum, ", "))
+	}
+
+	line := property.Name + ":" + value
+	if len(notes) > 0 {
+		line += " # " + strings.Join(notes, "; ")
+	}
+	if _, filled := paramTypes[typeName]; filled &&
+		!property.Array && !property.Required {
+		return fmt.Sprintf(
+			"{{- if %s }}\n%s\n{{- end }}\n",
+			dataField(property.Name),
+			line,
+		)
+	}
+	return line + "\n"
+}
+
+// dateLayout returns the Go time layout of a date property, defaulting to
+// RFC 3339 as frontmatter validation does.
+func dateLayout(spec domain.PropertySpec) string {
+	var format string
+	switch typed := spec.(type) {
+	case domain.DatePropertySpec:
+		format = typed.Format
+	case *domain.DatePropertySpec:
+		format = typed.Format
+	}
+	if format == "" {
+		return time.RFC3339
+	}
+	return format
+}
+
+// enumValues returns the allowed values of a string property with an enum.
+func enumValues(spec domain.PropertySpec) []string {
+	switch typed := spec.(type) {
+	case domain.StringPropertySpec:
+		return typed.Enum
+	case *domain.StringPropertySpec:
+		return typed.Enum
+	default:
+		return nil
+	}
+}
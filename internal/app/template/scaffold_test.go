@@ -0,0 +1,168 @@
+package template
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/JackMatanky/lithos/internal/domain"
+)
+
+func TestScaffold(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  domain.Schema
+		want    string
+		wantErr string
+	}{
+		{
+			name: "required properties first, then by name",
+			schema: domain.NewSchema("contact", []domain.Property{
+				domain.NewProperty(
+					"tags", false, true, domain.StringPropertySpec{},
+				),
+				domain.NewProperty(
+					"status", true, false, domain.StringPropertySpec{
+						Enum: []string{"active", "archived"},
+					},
+				),
+				domain.NewProperty(
+					"created", true, false, domain.DatePropertySpec{
+						Format: "2006-01-02",
+					},
+				),
+				domain.NewProperty(
+					"updated", false, false, &domain.DatePropertySpec{},
+				),
+				domain.NewProperty(
+					"fileClass", true, false, domain.StringPropertySpec{},
+				),
+			}),
+			want: "{{- /* lithos\n" +
+				"description: Scaffolded from the contact schema\n" +
+				"schema: contact\n" +
+				"params:\n" +
+				"  - name: status\n" +
+				"    type: string\n" +
+				"    required: true\n" +
+				"*/ -}}\n" +
+				"---\n" +
+				"fileClass: contact\n" +
+				"created: {{ now \"2006-01-02\" }} # required\n" +
+				"status: {{ .status | quoteYAML }} " +
+				"# required; one of: active, archived\n" +
+				"tags: []\n" +
+				"updated: {{ now \"2006-01-02T15:04:05Z07:00\" }}\n" +
+				"---\n",
+		},
+		{
+			name: "resolved properties are used",
+			schema: domain.Schema{
+				Name:       "meeting",
+				Properties: []domain.Property{},
+				ResolvedProperties: []domain.Property{
+					domain.NewProperty(
+						"done", false, false, domain.BoolPropertySpec{},
+					),
+					domain.NewProperty(
+						"due-in", true, false, domain.NumberPropertySpec{},
+					),
+				},
+			},
+			want: "{{- /* lithos\n" +
+				"description: Scaffolded from the meeting schema\n" +
+				"schema: meeting\n" +
+				"params:\n" +
+				"  - name: due-in\n" +
+				"    type: number\n" +
+				"    required: true\n" +
+				"  - name: done\n" +
+				"    type: bool\n" +
+				"*/ -}}\n" +
+				"---\n" +
+				"fileClass: meeting\n" +
+				"due-in: {{ index . \"due-in\" | quoteYAML }} # required\n" +
+				"{{- if .done }}\n" +
+				"done: {{ .done | quoteYAML }}\n" +
+				"{{- end }}\n" +
+				"---\n",
+		},
+		{
+			name: "property without spec",
+			schema: domain.NewSchema("broken", []domain.Property{
+				domain.NewProperty("title", true, false, nil),
+			}),
+			wantErr: `cannot scaffold property "title" of schema "broken"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Scaffold(tt.schema)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Scaffold() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Scaffold() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Scaffold() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScaffold_IsDeterministic(t *testing.T) {
+	resolved := map[string]domain.Property{}
+	for _, property := range []domain.Property{
+		domain.NewProperty("title", true, false, domain.StringPropertySpec{}),
+		domain.NewProperty("status", true, false, domain.StringPropertySpec{}),
+		domain.NewProperty("due", false, false, domain.DatePropertySpec{}),
+		domain.NewProperty("tags", false, true, domain.StringPropertySpec{}),
+		domain.NewProperty("rating", false, false, domain.NumberPropertySpec{}),
+	} {
+		resolved[property.Name] = property
+	}
+	scaffold := func() string {
+		// Map iteration order differs between runs, like the registry's
+		// resolved properties.
+		schema := domain.Schema{Name: "task"}
+		for _, property := range resolved {
+			schema.ResolvedProperties = append(
+				schema.ResolvedProperties,
+				property,
+			)
+		}
+		got, err := Scaffold(schema)
+		if err != nil {
+			t.Fatalf("Scaffold() unexpected error = %v", err)
+		}
+		return got
+	}
+
+	first := scaffold()
+	for range 10 {
+		if got := scaffold(); got != first {
+			t.Fatalf("Scaffold() =\n%s\nwant\n%s", got, first)
+		}
+	}
+}
+
+func TestScaffold_RendersWithTemplateFunctions(t *testing.T) {
+	schema := domain.NewSchema("daily", []domain.Property{
+		domain.NewProperty("date", true, false, domain.DatePropertySpec{
+			Format: "2006-01-02",
+		}),
+	})
+
+	content, err := Scaffold(schema)
+	if err != nil {
+		t.Fatalf("Scaffold() unexpected error = %v", err)
+	}
+	parsed := NewStaticTemplateParser().Parse(t.Context(), content)
+	if parsed.IsErr() {
+		t.Fatalf("Parse() error = %v", parsed.Error())
+	}
+}
@@ -6,11 +6,18 @@ package template
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	stderrors "errors"
 	"fmt"
+	"path/filepath"
+	"strings"
 	"text/template"
+	"time"
 
 	"github.com/JackMatanky/lithos/internal/domain"
 	"github.com/JackMatanky/lithos/internal/ports/spi"
+	"github.com/JackMatanky/lithos/internal/shared/clock"
 	"github.com/JackMatanky/lithos/internal/shared/errors"
 )
 
@@ -20,6 +27,9 @@ import (
 type TemplateEngine struct {
 	parser   spi.TemplateParser
 	executor spi.TemplateExecutor
+	clock    clock.Clock
+	location *time.Location
+	prompter *Prompter
 }
 
 // NewTemplateEngine creates a new TemplateEngine instance with
@@ -30,9 +40,32 @@ func NewTemplateEngine(
 	parser spi.TemplateParser,
 	executor spi.TemplateExecutor,
 ) *TemplateEngine {
+	return NewTemplateEngineWithOptions(parser, executor, FuncMapOptions{})
+}
+
+// NewTemplateEngineWithOptions creates a TemplateEngine whose renders see
+// the current time of opts.Clock in opts.Location as {{.Now}}, matching the
+// date functions of a parser created with the same options, and whose
+// renders ask the questions of opts.Prompter.
+func NewTemplateEngineWithOptions(
+	parser spi.TemplateParser,
+	executor spi.TemplateExecutor,
+	opts FuncMapOptions,
+) *TemplateEngine {
+	location := opts.Location
+	if location == nil {
+		location = time.Local
+	}
+	clk := opts.Clock
+	if clk == nil {
+		clk = clock.System()
+	}
 	return &TemplateEngine{
 		parser:   parser,
 		executor: executor,
+		clock:    clk,
+		location: location,
+		prompter: opts.Prompter,
 	}
 }
 
@@ -40,37 +73,112 @@ func NewTemplateEngine(
 // This is the main entry point for template processing in the domain layer.
 // It handles parsing the template content and executing it to produce final
 // output.
+//
+// The render context supplies the data the template is executed against.
 func (e *TemplateEngine) ProcessTemplate(
 	ctx context.Context,
 	content string,
 	templateName string,
+	rc domain.RenderContext,
 ) (string, error) {
 	if err := e.validateContent(content, templateName); err != nil {
 		return "", err
 	}
 
-	parsed, err := e.parseTemplate(ctx, content)
+	parsed, err := e.parseTemplate(ctx, templateName, content)
 	if err != nil {
 		return "", err
 	}
 
 	tmpl := e.createDomainTemplate(templateName, content, parsed)
 
-	return e.executeTemplate(ctx, tmpl)
+	return e.executeTemplate(ctx, tmpl, rc, true)
 }
 
-// ExecuteParsedTemplate executes a pre-parsed template.
+// ExecuteParsedTemplate executes a pre-parsed template against the given
+// render context.
 // This method is used when the template has already been parsed by the
-// repository.
+// repository. The render context is checked against the template's declared
+// params first (see ResolveParams).
 func (e *TemplateEngine) ExecuteParsedTemplate(
 	ctx context.Context,
 	tmpl *domain.Template,
+	rc domain.RenderContext,
+) (string, error) {
+	if err := e.validateTemplate(tmpl); err != nil {
+		return "", err
+	}
+
+	rc, err := e.ResolveParams(tmpl, rc)
+	if err != nil {
+		return "", err
+	}
+
+	return e.executeTemplate(ctx, tmpl, rc, true)
+}
+
+// ExecuteParsedTemplateUnattended executes a pre-parsed template like
+// ExecuteParsedTemplate, for renders nobody is there to answer: a template
+// that asks a question not answered yet fails instead of asking it.
+func (e *TemplateEngine) ExecuteParsedTemplateUnattended(
+	ctx context.Context,
+	tmpl *domain.Template,
+	rc domain.RenderContext,
+) (string, error) {
+	if err := e.validateTemplate(tmpl); err != nil {
+		return "", err
+	}
+
+	rc, err := e.ResolveParams(tmpl, rc)
+	if err != nil {
+		return "", err
+	}
+
+	return e.executeTemplate(ctx, tmpl, rc, false)
+}
+
+// RenderOutputPath renders the output path pattern declared in the template
+// header against the render context, after resolving declared params.
+// It returns an empty string when the template declares no pattern, leaving
+// path selection to the caller.
+// Returns an error if the pattern fails to render or renders to an empty path.
+func (e *TemplateEngine) RenderOutputPath(
+	ctx context.Context,
+	tmpl *domain.Template,
+	rc domain.RenderContext,
 ) (string, error) {
 	if err := e.validateTemplate(tmpl); err != nil {
 		return "", err
 	}
 
-	return e.executeTemplate(ctx, tmpl)
+	pattern := tmpl.Header.Output
+	if strings.TrimSpace(pattern) == "" {
+		return "", nil
+	}
+
+	rc, err := e.ResolveParams(tmpl, rc)
+	if err != nil {
+		return "", err
+	}
+
+	// The pattern renders on behalf of the template it belongs to
+	rc = e.describeRender(tmpl, rc)
+	rendered, err := e.ProcessTemplate(ctx, pattern, tmpl.Name+":output", rc)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to render output path pattern")
+	}
+
+	outputPath := strings.TrimSpace(rendered)
+	if outputPath == "" {
+		return "", errors.NewTemplateError(
+			tmpl.Name,
+			0,
+			fmt.Sprintf("output pattern %q rendered an empty path", pattern),
+			nil,
+		)
+	}
+
+	return filepath.Clean(outputPath), nil
 }
 
 // ProcessTemplateFromPath processes a template from a file path.
@@ -103,17 +211,21 @@ func (e *TemplateEngine) validateContent(
 	return nil
 }
 
-// parseTemplate parses the template content using the injected parser.
+// parseTemplate parses the template content using the injected parser,
+// attributing parse errors to templateName.
 func (e *TemplateEngine) parseTemplate(
 	ctx context.Context,
+	templateName string,
 	content string,
 ) (*template.Template, error) {
 	parseResult := e.parser.Parse(ctx, content)
 	if parseResult.IsErr() {
-		return nil, errors.Wrap(
-			parseResult.Error(),
-			"failed to parse template content",
-		)
+		err := parseResult.Error()
+		var templateErr errors.TemplateError
+		if stderrors.As(err, &templateErr) && templateErr.Template() == "" {
+			err = templateErr.WithTemplate(templateName)
+		}
+		return nil, errors.Wrap(err, "failed to parse template content")
 	}
 	return parseResult.Value(), nil
 }
@@ -129,6 +241,7 @@ func (e *TemplateEngine) createDomainTemplate(
 		Name:     templateName,
 		Content:  content,
 		Parsed:   parsed,
+		Header:   domain.TemplateHeader{},
 	}
 }
 
@@ -159,16 +272,91 @@ func (e *TemplateEngine) validateTemplate(tmpl *domain.Template) error {
 }
 
 // executeTemplate executes the template using the injected executor.
+// The root object of the render context is passed as the template's root
+// object, after filling in the render time and the template. A render that
+// stops at an unanswered question is run again once it has been asked, or
+// fails when ask is false.
 func (e *TemplateEngine) executeTemplate(
 	ctx context.Context,
 	tmpl *domain.Template,
+	rc domain.RenderContext,
+	ask bool,
 ) (string, error) {
-	executeResult := e.executor.Execute(ctx, tmpl, nil)
-	if executeResult.IsErr() {
-		return "", errors.Wrap(
-			executeResult.Error(),
-			"failed to execute parsed template",
-		)
+	rc = e.describeRender(tmpl, rc)
+	for {
+		executeResult := e.executor.Execute(ctx, tmpl, rc.Root())
+		if executeResult.IsOk() {
+			return executeResult.Value(), nil
+		}
+
+		if !ask {
+			if label, pending := e.prompter.dropPending(); pending {
+				return "", fmt.Errorf(
+					"template asks %q, which cannot be answered here",
+					label,
+				)
+			}
+			return "", errors.Wrap(
+				executeResult.Error(),
+				"failed to execute parsed template",
+			)
+		}
+
+		asked, err := e.prompter.askPending(ctx)
+		if err != nil {
+			return "", err
+		}
+		if !asked {
+			return "", errors.Wrap(
+				executeResult.Error(),
+				"failed to execute parsed template",
+			)
+		}
+	}
+}
+
+// describeRender returns rc with the render time and the description of tmpl
+// filled in, unless the caller already set them.
+func (e *TemplateEngine) describeRender(
+	tmpl *domain.Template,
+	rc domain.RenderContext,
+) domain.RenderContext {
+	if rc.Now.IsZero() {
+		rc.Now = e.clock.Now().In(e.location)
+	}
+	if rc.Template == (domain.TemplateInfo{}) {
+		rc.Template = DescribeTemplate(tmpl)
+	}
+	return rc
+}
+
+// DescribeTemplate returns the description of tmpl that its renders see as
+// {{.Template}}. Templates without an ID, such as inline ones, are identified
+// by their name.
+func DescribeTemplate(tmpl *domain.Template) domain.TemplateInfo {
+	id := tmpl.ID
+	if id == "" {
+		id = tmpl.Name
+	}
+	return domain.TemplateInfo{
+		ID:   id,
+		Path: tmpl.FilePath,
+		Hash: sourceHash(tmpl),
+	}
+}
+
+// sourceHash hashes the content of tmpl together with the name and content
+// of every partial parsed into it, its layout included, so that editing a
+// partial changes the hash of the templates rendered with it. Templates
+// without partials hash to the SHA-256 of their content.
+func sourceHash(tmpl *domain.Template) string {
+	h := sha256.New()
+	h.Write([]byte(tmpl.Content))
+	for _, partial := range tmpl.Partials {
+		h.Write([]byte{0})
+		h.Write([]byte(partial.Name))
+		h.Write([]byte{0})
+		h.Write([]byte(partial.Content))
 	}
-	return executeResult.Value(), nil
+	return hex.EncodeToString(h.Sum(nil))
 }
@@ -4,8 +4,10 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/JackMatanky/lithos/internal/domain"
+	"github.com/JackMatanky/lithos/internal/shared/clock"
 )
 
 func TestTemplateEngine_ProcessTemplate(t *testing.T) {
@@ -30,6 +32,34 @@ func TestTemplateEngine_ProcessTemplate(t *testing.T) {
 			want:         "", // Will be validated in test logic
 			wantErr:      false,
 		},
+		{
+			name:         "successful processing with user data",
+			content:      "# {{.title}} by {{.author | toLower}}",
+			templateName: "data",
+			data: map[string]interface{}{
+				"title":  "Weekly Sync",
+				"author": "ADA",
+			},
+			want:    "# Weekly Sync by ada",
+			wantErr: false,
+		},
+		{
+			name: "date functions chain in pipelines",
+			content: "{{.due | dateAdd \"-1w\" | startOfWeek | " +
+				"dateFormat \"2006-01-02\"}} {{isoWeek .due}} " +
+				"{{weekday .due}}",
+			templateName: "dates",
+			data:         map[string]interface{}{"due": "2025-03-14"},
+			want:         "2025-03-03 2025-W11 Friday",
+			wantErr:      false,
+		},
+		{
+			name:         "missing data key renders no value",
+			content:      "Hello, {{.name}}!",
+			templateName: "missing",
+			want:         "Hello, <no value>!",
+			wantErr:      false,
+		},
 		{
 			name:         "empty content",
 			content:      "",
@@ -59,6 +89,7 @@ type templateTestCase struct {
 	name         string
 	content      string
 	templateName string
+	data         map[string]interface{}
 	want         string
 	wantErr      bool
 	errContains  string
@@ -70,7 +101,12 @@ func runTemplateEngineTest(
 	ctx context.Context,
 	tt *templateTestCase,
 ) {
-	got, err := engine.ProcessTemplate(ctx, tt.content, tt.templateName)
+	got, err := engine.ProcessTemplate(
+		ctx,
+		tt.content,
+		tt.templateName,
+		domain.RenderContext{Data: tt.data},
+	)
 
 	if tt.wantErr {
 		if err == nil {
@@ -138,7 +174,11 @@ func TestTemplateEngine_ExecuteParsedTemplate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := engine.ExecuteParsedTemplate(ctx, tt.template)
+			got, err := engine.ExecuteParsedTemplate(
+				ctx,
+				tt.template,
+				domain.NewRenderContext(),
+			)
 
 			if tt.wantErr {
 				if err == nil {
@@ -168,6 +208,162 @@ func TestTemplateEngine_ExecuteParsedTemplate(t *testing.T) {
 	}
 }
 
+func TestTemplateEngine_ExecuteParsedTemplate_WithData(t *testing.T) {
+	parser := NewStaticTemplateParser()
+	executor := NewGoTemplateExecutor()
+	engine := NewTemplateEngine(parser, executor)
+	ctx := context.Background()
+
+	parsed := parser.Parse(ctx, "{{.title}} ({{.status | toUpper}})")
+	if parsed.IsErr() {
+		t.Fatalf("Parse() unexpected error = %v", parsed.Error())
+	}
+
+	tmpl := &domain.Template{
+		FilePath: "",
+		Name:     "with-data",
+		Content:  "{{.title}} ({{.status | toUpper}})",
+		Parsed:   parsed.Value(),
+	}
+	rc := domain.NewRenderContext()
+	rc.Merge(map[string]interface{}{"title": "Plan", "status": "draft"})
+
+	got, err := engine.ExecuteParsedTemplate(ctx, tmpl, rc)
+	if err != nil {
+		t.Fatalf("ExecuteParsedTemplate() unexpected error = %v", err)
+	}
+
+	if want := "Plan (DRAFT)"; got != want {
+		t.Errorf("ExecuteParsedTemplate() = %q, want %q", got, want)
+	}
+}
+
+func TestTemplateEngine_ExecuteParsedTemplate_RenderContext(t *testing.T) {
+	now := time.Date(2025, 1, 2, 9, 30, 0, 0, time.UTC)
+	opts := FuncMapOptions{Location: time.UTC, Clock: clock.Fixed(now)}
+	parser := NewStaticTemplateParserWithOptions(opts)
+	engine := NewTemplateEngineWithOptions(
+		parser,
+		NewGoTemplateExecutor(),
+		opts,
+	)
+	ctx := context.Background()
+
+	content := "{{.title}}={{.Data.title}} {{.Now.Format \"15:04\"}} " +
+		"{{.Template.ID}} {{len .Template.Hash}} {{.Target.Basename}} " +
+		"{{.Vault.Root}}"
+	parsed := parser.Parse(ctx, content)
+	if parsed.IsErr() {
+		t.Fatalf("Parse() unexpected error = %v", parsed.Error())
+	}
+
+	tmpl := &domain.Template{
+		ID:      "work/meeting",
+		Name:    "meeting",
+		Content: content,
+		Parsed:  parsed.Value(),
+	}
+	rc := domain.NewRenderContext()
+	rc.Merge(map[string]interface{}{"title": "Plan"})
+	rc.Target = domain.TargetInfo{Basename: "plan-1"}
+	rc.Vault = domain.VaultInfo{Root: "/vault"}
+
+	got, err := engine.ExecuteParsedTemplate(ctx, tmpl, rc)
+	if err != nil {
+		t.Fatalf("ExecuteParsedTemplate() unexpected error = %v", err)
+	}
+
+	want := "Plan=Plan 09:30 work/meeting 64 plan-1 /vault"
+	if got != want {
+		t.Errorf("ExecuteParsedTemplate() = %q, want %q", got, want)
+	}
+}
+
+func TestTemplateEngine_RenderOutputPath(t *testing.T) {
+	parser := NewStaticTemplateParser()
+	executor := NewGoTemplateExecutor()
+	engine := NewTemplateEngine(parser, executor)
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		pattern     string
+		data        map[string]interface{}
+		want        string
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:    "no pattern",
+			pattern: "",
+			want:    "",
+		},
+		{
+			name:    "pattern with data and slug",
+			pattern: "projects/{{.title | slug}}.md",
+			data:    map[string]interface{}{"title": "Q3 Planning"},
+			want:    "projects/q3-planning.md",
+		},
+		{
+			name:    "surrounding whitespace trimmed and path cleaned",
+			pattern: " notes//{{.name}}.md \n",
+			data:    map[string]interface{}{"name": "daily"},
+			want:    "notes/daily.md",
+		},
+		{
+			name:        "pattern renders empty path",
+			pattern:     "{{if .missing}}notes/x.md{{end}}",
+			wantErr:     true,
+			errContains: "rendered an empty path",
+		},
+		{
+			name:        "pattern syntax error",
+			pattern:     "projects/{{.title",
+			wantErr:     true,
+			errContains: "failed to render output path pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := &domain.Template{
+				FilePath: "",
+				Name:     "project",
+				Content:  "body",
+				Parsed:   parser.Parse(ctx, "body").Value(),
+				Header:   domain.TemplateHeader{Output: tt.pattern},
+			}
+
+			got, err := engine.RenderOutputPath(
+				ctx,
+				tmpl,
+				domain.RenderContext{Data: tt.data},
+			)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("RenderOutputPath() expected error, got nil")
+				}
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf(
+						"RenderOutputPath() error = %v, want to contain %q",
+						err,
+						tt.errContains,
+					)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("RenderOutputPath() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderOutputPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestTemplateEngine_ProcessTemplateFromPath(t *testing.T) {
 	parser := NewStaticTemplateParser()
 	executor := NewGoTemplateExecutor()
@@ -198,6 +394,73 @@ func TestNewTemplateEngine(t *testing.T) {
 	}
 }
 
+func TestDescribeTemplate_Hash(t *testing.T) {
+	const content = "{{ template \"card\" . }}"
+	layout := domain.Partial{
+		Name:    "page",
+		Content: "<{{ block \"body\" . }}{{ end }}>",
+	}
+	card := domain.Partial{Name: "card", Content: "card"}
+
+	base := DescribeTemplate(&domain.Template{
+		Content:  content,
+		Partials: []domain.Partial{card, layout},
+	}).Hash
+
+	tests := []struct {
+		name     string
+		content  string
+		partials []domain.Partial
+		wantSame bool
+	}{
+		{
+			name:     "same source",
+			content:  content,
+			partials: []domain.Partial{card, layout},
+			wantSame: true,
+		},
+		{
+			name:     "edited template",
+			content:  content + "\n",
+			partials: []domain.Partial{card, layout},
+		},
+		{
+			name:    "edited partial",
+			content: content,
+			partials: []domain.Partial{
+				{Name: "card", Content: "new card"},
+				layout,
+			},
+		},
+		{
+			name:    "edited layout",
+			content: content,
+			partials: []domain.Partial{
+				card,
+				{Name: "page", Content: "[{{ block \"body\" . }}{{ end }}]"},
+			},
+		},
+		{
+			name:     "partial removed",
+			content:  content,
+			partials: []domain.Partial{card},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DescribeTemplate(&domain.Template{
+				Content:  tt.content,
+				Partials: tt.partials,
+			}).Hash
+			if (got == base) != tt.wantSame {
+				t.Errorf("Hash = %s, base %s, want same = %v",
+					got, base, tt.wantSame)
+			}
+		})
+	}
+}
+
 // validateTemplateEngineCustomFunctionsOutput validates the output of custom
 // functions test.
 func validateTemplateEngineCustomFunctionsOutput(t *testing.T, got string) {
@@ -0,0 +1,541 @@
+// Package template provides domain services for template processing.
+// This file contains the conversion of Obsidian Templater templates into
+// lithos templates behind "lithos templates import".
+package template
+
+import (
+	stderrors "errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template/parse"
+)
+
+// Templater tag delimiters and the markers that may follow or precede them.
+const (
+	templaterOpen        = "<%"
+	templaterClose       = "%>"
+	templaterTrimMarkers = "-_"
+	templaterExecMarkers = "*+"
+)
+
+// Default moment.js formats of the Templater date functions.
+const (
+	templaterDateFormat     = "YYYY-MM-DD"
+	templaterFileDateFormat = "YYYY-MM-DD HH:mm"
+)
+
+// templaterCallPattern matches a tp.* property access or call.
+var templaterCallPattern = regexp.MustCompile(
+	`(?s)^(tp(?:\.\w+)+)\s*(?:\((.*)\))?$`,
+)
+
+// templaterIndexPattern matches tp.frontmatter["key"].
+var templaterIndexPattern = regexp.MustCompile(
+	`(?s)^tp\.frontmatter\[\s*(.+?)\s*\]$`,
+)
+
+// identifierPattern matches keys usable as a field in a template action.
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// momentLayouts maps moment.js format tokens to Go reference layouts.
+var momentLayouts = map[string]string{
+	"YYYY": "2006", "YY": "06",
+	"MMMM": "January", "MMM": "Jan", "MM": "01", "M": "1",
+	"DDDD": "002", "DD": "02", "D": "2",
+	"dddd": "Monday", "ddd": "Mon",
+	"HH": "15", "hh": "03", "h": "3",
+	"mm": "04", "m": "4",
+	"ss": "05", "s": "5",
+	"A": "PM", "a": "pm",
+	"ZZ": "-0700", "Z": "-07:00",
+}
+
+// momentTokenLetters lists the letters moment.js treats as format tokens.
+// Letters outside this set are copied as they are.
+const momentTokenLetters = "YMDdHhmsAaZSQWwEeXxkgGNo"
+
+// goLayoutWords lists words Go would read as layout elements in literal
+// text.
+var goLayoutWords = []string{"Jan", "Mon", "MST", "PM", "pm"}
+
+// TemplaterImport is the result of converting a Templater template.
+type TemplaterImport struct {
+	Content string      // The converted lithos template
+	Issues  []LintIssue // Constructs left unconverted, by position
+}
+
+// templaterConverter converts a single Templater template.
+type templaterConverter struct {
+	path   string
+	source string
+	issues []LintIssue
+}
+
+// ImportTemplater converts the Templater template source, read from path,
+// into a lithos template. Common tp.date, tp.file, tp.frontmatter, and
+// tp.system.prompt calls become template actions, with moment.js formats
+// translated to Go layouts.
+// Anything else, including every JavaScript execution block, is kept as
+// written and reported as an issue for manual conversion. The note title of
+// tp.file.title is the basename of the note being created.
+func ImportTemplater(path, source string) TemplaterImport {
+	c := &templaterConverter{path: path, source: source}
+	body := c.convert()
+
+	var b strings.Builder
+	b.WriteString("{{- /* lithos\n")
+	fmt.Fprintf(&b, "description: Imported from Templater (%s)\n", path)
+	b.WriteString("*/ -}}\n")
+	b.WriteString(body)
+
+	return TemplaterImport{Content: b.String(), Issues: c.issues}
+}
+
+// convert rewrites every Templater tag of the source and escapes text that
+// Go templates would otherwise read as actions.
+func (c *templaterConverter) convert() string {
+	var b strings.Builder
+	rest := c.source
+	offset := 0
+	for {
+		start := strings.Index(rest, templaterOpen)
+		if start < 0 {
+			b.WriteString(escapeActions(rest))
+			return b.String()
+		}
+		end := strings.Index(rest[start:], templaterClose)
+		if end < 0 {
+			c.report(offset+start, "unclosed Templater tag")
+			b.WriteString(escapeActions(rest))
+			return b.String()
+		}
+		end += start + len(templaterClose)
+
+		b.WriteString(escapeActions(rest[:start]))
+		b.WriteString(c.convertTag(offset+start, rest[start:end]))
+		offset += end
+		rest = rest[end:]
+	}
+}
+
+// convertTag converts the Templater tag found at offset into a template
+// action, or returns it unchanged after reporting it.
+func (c *templaterConverter) convertTag(offset int, tag string) string {
+	inner := strings.TrimSuffix(
+		strings.TrimPrefix(tag, templaterOpen),
+		templaterClose,
+	)
+
+	trimLeft, trimRight, command := false, false, false
+	for inner != "" && strings.ContainsRune(
+		templaterTrimMarkers+templaterExecMarkers,
+		rune(inner[0]),
+	) {
+		if strings.ContainsRune(templaterTrimMarkers, rune(inner[0])) {
+			trimLeft = true
+		} else {
+			command = inner[0] == '*'
+		}
+		inner = inner[1:]
+	}
+	if inner != "" &&
+		strings.ContainsRune(templaterTrimMarkers, rune(inner[len(inner)-1])) {
+		trimRight = true
+		inner = inner[:len(inner)-1]
+	}
+
+	expression := strings.TrimSuffix(strings.TrimSpace(inner), ";")
+	if command {
+		c.report(offset, "JavaScript execution blocks cannot be converted")
+		return tag
+	}
+
+	action, err := c.convertExpression(strings.TrimSpace(expression))
+	if err != nil {
+		c.report(offset, fmt.Sprintf(
+			"cannot convert %q: %v",
+			strings.TrimSpace(expression),
+			err,
+		))
+		return tag
+	}
+	return templateAction(action, trimLeft, trimRight)
+}
+
+// convertExpression converts a Templater expression into the body of a
+// template action. An empty body means the expression produces nothing.
+func (c *templaterConverter) convertExpression(expr string) (string, error) {
+	if match := templaterIndexPattern.FindStringSubmatch(expr); match != nil {
+		key, ok := parseJSString(match[1])
+		if !ok {
+			return "", stderrors.New("frontmatter key must be a string literal")
+		}
+		return dataField(key), nil
+	}
+
+	match := templaterCallPattern.FindStringSubmatch(expr)
+	if match == nil {
+		return "", stderrors.New("not a tp.* call")
+	}
+	name := match[1]
+	called := strings.HasSuffix(expr, ")")
+	args, ok := parseJSArgs(match[2])
+	if !ok {
+		return "", stderrors.New("arguments must be string or integer literals")
+	}
+
+	return convertCall(name, called, args)
+}
+
+// convertCall converts the tp.* property or function name, called with args
+// when called is set, into the body of a template action.
+func convertCall(
+	name string,
+	called bool,
+	args []interface{},
+) (string, error) {
+	if key, found := strings.CutPrefix(name, "tp.frontmatter."); found {
+		if called {
+			return "", stderrors.New("frontmatter values cannot be called")
+		}
+		return dataField(key), nil
+	}
+
+	switch name {
+	case "tp.file.title":
+		if called {
+			return "", stderrors.New("tp.file.title is not a function")
+		}
+		return ".Target.Basename", nil
+	case "tp.file.cursor":
+		return "", nil
+	case "tp.file.include":
+		return convertInclude(args)
+	case "tp.date.now":
+		return convertDateNow(args)
+	case "tp.date.tomorrow":
+		return convertRelativeDate("tomorrow", args)
+	case "tp.date.yesterday":
+		return convertRelativeDate("yesterday", args)
+	case "tp.file.creation_date", "tp.file.last_modified_date":
+		return convertFileDate(args)
+	case "tp.system.prompt":
+		return convertPrompt(args)
+	default:
+		return "", fmt.Errorf("%s has no lithos equivalent", name)
+	}
+}
+
+// report records an unconverted construct at offset in the source.
+func (c *templaterConverter) report(offset int, message string) {
+	line, column := position(c.source, parse.Pos(offset))
+	c.issues = append(c.issues, LintIssue{
+		Path:    c.path,
+		Line:    line,
+		Column:  column,
+		Message: message,
+	})
+}
+
+// convertDateNow converts tp.date.now(format, offset), where offset is a
+// number of days.
+func convertDateNow(args []interface{}) (string, error) {
+	if len(args) > 2 {
+		return "", stderrors.New("reference dates are not supported")
+	}
+	layout, err := dateLayoutArg(args, templaterDateFormat)
+	if err != nil {
+		return "", err
+	}
+	if len(args) < 2 {
+		return "now " + strconv.Quote(layout), nil
+	}
+
+	days, ok := args[1].(int)
+	if !ok {
+		return "", stderrors.New("offset must be a number of days")
+	}
+	return fmt.Sprintf(
+		`relativeDate "now" | dateAdd "%dd" | dateFormat %s`,
+		days,
+		strconv.Quote(layout),
+	), nil
+}
+
+// convertRelativeDate converts tp.date.tomorrow and tp.date.yesterday.
+func convertRelativeDate(phrase string, args []interface{}) (string, error) {
+	if len(args) > 1 {
+		return "", stderrors.New("expected at most a format")
+	}
+	layout, err := dateLayoutArg(args, templaterDateFormat)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(
+		"relativeDate %s | dateFormat %s",
+		strconv.Quote(phrase),
+		strconv.Quote(layout),
+	), nil
+}
+
+// convertFileDate converts the file date functions. A note created from a
+// template is created, and last modified, at render time.
+func convertFileDate(args []interface{}) (string, error) {
+	if len(args) > 1 {
+		return "", stderrors.New("expected at most a format")
+	}
+	layout, err := dateLayoutArg(args, templaterFileDateFormat)
+	if err != nil {
+		return "", err
+	}
+	return "now " + strconv.Quote(layout), nil
+}
+
+// convertPrompt converts tp.system.prompt(label, default) into the prompt
+// function. Templater's cancel and multiline flags have no equivalent.
+func convertPrompt(args []interface{}) (string, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return "", stderrors.New("expected a label and an optional default")
+	}
+	action := "prompt"
+	for _, arg := range args {
+		text, ok := arg.(string)
+		if !ok {
+			return "", stderrors.New("label and default must be strings")
+		}
+		action += " " + strconv.Quote(text)
+	}
+	return action, nil
+}
+
+// convertInclude converts tp.file.include("[[Name]]") into an invocation of
+// the partial of the same name.
+func convertInclude(args []interface{}) (string, error) {
+	if len(args) != 1 {
+		return "", stderrors.New("expected a single link")
+	}
+	link, ok := args[0].(string)
+	if !ok {
+		return "", stderrors.New("expected a link string")
+	}
+	name := strings.TrimSuffix(strings.TrimPrefix(link, "[["), "]]")
+	name, _, _ = strings.Cut(name, "|")
+	if strings.Contains(name, "#") {
+		return "", stderrors.New("including a section is not supported")
+	}
+	return fmt.Sprintf("template %s .", strconv.Quote(name)), nil
+}
+
+// dateLayoutArg returns the Go layout of the moment.js format in the first
+// argument, or of fallback when there are no arguments.
+func dateLayoutArg(args []interface{}, fallback string) (string, error) {
+	format := fallback
+	if len(args) > 0 {
+		value, ok := args[0].(string)
+		if !ok {
+			return "", stderrors.New("format must be a string")
+		}
+		format = value
+	}
+	return momentLayout(format)
+}
+
+// momentLayout translates a moment.js format into a Go reference layout.
+// Tokens without a Go equivalent, such as ordinals and week numbers, are an
+// error, as are digits and Go layout words in literal text.
+func momentLayout(format string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(format); {
+		ch := format[i]
+		switch {
+		case ch == '[':
+			literal, err := momentLiteral(format[i:])
+			if err != nil {
+				return "", fmt.Errorf("%w in format %q", err, format)
+			}
+			b.WriteString(literal)
+			i += len(literal) + 2
+		case ch >= '0' && ch <= '9':
+			return "", fmt.Errorf(
+				"digits in format %q must be escaped with []",
+				format,
+			)
+		case strings.IndexByte(momentTokenLetters, ch) >= 0:
+			layout, n, err := momentToken(format[i:], b.String())
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(layout)
+			i += n
+		default:
+			b.WriteByte(ch)
+			i++
+		}
+	}
+	return b.String(), nil
+}
+
+// momentLiteral returns the literal text of the [escaped] section that
+// format starts with.
+func momentLiteral(format string) (string, error) {
+	end := strings.IndexByte(format, ']')
+	if end < 0 {
+		return "", stderrors.New("unclosed [")
+	}
+	literal := format[1:end]
+	if err := checkLayoutLiteral(literal); err != nil {
+		return "", err
+	}
+	return literal, nil
+}
+
+// momentToken translates the moment.js token that format starts with, a run
+// of one repeated letter, into a Go layout. It returns the layout and the
+// length of the token. Fractional seconds are recognised by the "." ending
+// the layout translated so far.
+func momentToken(format, translated string) (string, int, error) {
+	ch := format[0]
+	n := 1
+	for n < len(format) && format[n] == ch {
+		n++
+	}
+	token := format[:n]
+	layout, ok := momentLayouts[token]
+	if ch == 'S' && strings.HasSuffix(translated, ".") {
+		layout, ok = strings.Repeat("0", n), true
+	}
+	if !ok || (n < len(format) && format[n] == 'o') {
+		return "", 0, fmt.Errorf("moment.js token %q has no Go layout", token)
+	}
+	return layout, n, nil
+}
+
+// checkLayoutLiteral rejects literal text Go would read as layout elements.
+func checkLayoutLiteral(literal string) error {
+	if strings.ContainsAny(literal, "0123456789") {
+		return fmt.Errorf("literal %q contains digits", literal)
+	}
+	for _, word := range goLayoutWords {
+		if strings.Contains(literal, word) {
+			return fmt.Errorf("literal %q contains %q", literal, word)
+		}
+	}
+	return nil
+}
+
+// parseJSArgs parses a comma-separated list of JavaScript string and integer
+// literals.
+func parseJSArgs(source string) ([]interface{}, bool) {
+	var args []interface{}
+	source = strings.TrimSpace(source)
+	for source != "" {
+		var (
+			arg  interface{}
+			rest string
+		)
+		if strings.ContainsRune("\"'`", rune(source[0])) {
+			end := closingQuote(source)
+			if end < 0 {
+				return nil, false
+			}
+			value, ok := parseJSString(source[:end+1])
+			if !ok {
+				return nil, false
+			}
+			arg, rest = value, source[end+1:]
+		} else {
+			literal, after, _ := strings.Cut(source, ",")
+			n, err := strconv.Atoi(strings.TrimSpace(literal))
+			if err != nil {
+				return nil, false
+			}
+			arg, rest = n, ","+after
+			if !strings.Contains(source, ",") {
+				rest = ""
+			}
+		}
+		args = append(args, arg)
+
+		rest = strings.TrimSpace(rest)
+		if rest == "" {
+			break
+		}
+		if rest[0] != ',' {
+			return nil, false
+		}
+		source = strings.TrimSpace(rest[1:])
+	}
+	return args, true
+}
+
+// closingQuote returns the index of the quote closing the string literal
+// that source starts with, or -1.
+func closingQuote(source string) int {
+	quote := source[0]
+	for i := 1; i < len(source); i++ {
+		switch source[i] {
+		case '\\':
+			i++
+		case quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// parseJSString decodes a quoted JavaScript string literal. Template
+// literals with substitutions are not plain strings.
+func parseJSString(literal string) (string, bool) {
+	if len(literal) < 2 || literal[0] != literal[len(literal)-1] ||
+		!strings.ContainsRune("\"'`", rune(literal[0])) {
+		return "", false
+	}
+	body := literal[1 : len(literal)-1]
+	if literal[0] == '`' && strings.Contains(body, "${") {
+		return "", false
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(body); i++ {
+		if body[i] == '\\' && i+1 < len(body) {
+			i++
+		}
+		b.WriteByte(body[i])
+	}
+	return b.String(), true
+}
+
+// dataField returns the action body reading key from the template data.
+func dataField(key string) string {
+	if identifierPattern.MatchString(key) {
+		return "." + key
+	}
+	return "index . " + strconv.Quote(key)
+}
+
+// templateAction wraps body in action delimiters with the given trim
+// markers. An empty body becomes a comment so that trimming still applies.
+func templateAction(body string, trimLeft, trimRight bool) string {
+	if body == "" {
+		if !trimLeft && !trimRight {
+			return ""
+		}
+		body = "/* */"
+	}
+
+	open, close := "{{ ", " }}"
+	if trimLeft {
+		open = "{{- "
+	}
+	if trimRight {
+		close = " -}}"
+	}
+	return open + body + close
+}
+
+// escapeActions escapes text that Go templates would read as an action.
+func escapeActions(text string) string {
+	return strings.ReplaceAll(text, "{{", `{{"{{"}}`)
+}
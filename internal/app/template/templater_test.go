@@ -0,0 +1,148 @@
+package template
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImportTemplater(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		wantBody   string
+		wantIssues []string
+	}{
+		{
+			name:   "title and dates",
+			source: "# <% tp.file.title %>\nCreated: <% tp.date.now() %>\n",
+			wantBody: "# {{ .Target.Basename }}\n" +
+				"Created: {{ now \"2006-01-02\" }}\n",
+		},
+		{
+			name:   "date formats and offsets",
+			source: `<% tp.date.now("dddd, MMMM D [at] HH:mm", -1) %>`,
+			wantBody: `{{ relativeDate "now" | dateAdd "-1d" | ` +
+				`dateFormat "Monday, January 2 at 15:04" }}`,
+		},
+		{
+			name: "tomorrow, yesterday, and file dates",
+			source: "<% tp.date.tomorrow('YYYY-MM-DD') %> " +
+				"<% tp.date.yesterday() %> <% tp.file.creation_date() %>",
+			wantBody: `{{ relativeDate "tomorrow" | ` +
+				`dateFormat "2006-01-02" }} ` +
+				`{{ relativeDate "yesterday" | ` +
+				`dateFormat "2006-01-02" }} ` +
+				`{{ now "2006-01-02 15:04" }}`,
+		},
+		{
+			name: "frontmatter, include, and cursor",
+			source: `<% tp.frontmatter.status %> ` +
+				`<% tp.frontmatter["due-date"] %> ` +
+				`<%- tp.file.include("[[Footer|f]]") -%>` +
+				"<% tp.file.cursor(1) %>",
+			wantBody: `{{ .status }} {{ index . "due-date" }} ` +
+				`{{- template "Footer" . -}}`,
+		},
+		{
+			name:     "text that looks like an action is escaped",
+			source:   "{{ not an action }}",
+			wantBody: `{{"{{"}} not an action }}`,
+		},
+		{
+			name: "prompts",
+			source: `<% tp.system.prompt("Name") %> ` +
+				`<% tp.system.prompt('Status', "draft") %>`,
+			wantBody: `{{ prompt "Name" }} {{ prompt "Status" "draft" }}`,
+		},
+		{
+			name: "unconvertible constructs are kept and reported",
+			source: "<%* let x = 1 %>\n" +
+				"<% tp.system.clipboard() %>\n" +
+				"<% tp.date.now(\"Do MMMM\") %>\n" +
+				"<% tp.system.prompt(\"Name\", \"\", true) %>",
+			wantBody: "<%* let x = 1 %>\n" +
+				"<% tp.system.clipboard() %>\n" +
+				"<% tp.date.now(\"Do MMMM\") %>\n" +
+				"<% tp.system.prompt(\"Name\", \"\", true) %>",
+			wantIssues: []string{
+				"note.md:1:1: JavaScript execution blocks cannot be converted",
+				`note.md:2:1: cannot convert "tp.system.clipboard()": ` +
+					"tp.system.clipboard has no lithos equivalent",
+				`note.md:3:1: cannot convert "tp.date.now(\"Do MMMM\")": ` +
+					`moment.js token "D" has no Go layout`,
+				`note.md:4:1: cannot convert ` +
+					`"tp.system.prompt(\"Name\", \"\", true)": ` +
+					"arguments must be string or integer literals",
+			},
+		},
+		{
+			name:       "unclosed tag",
+			source:     "a <% tp.file.title",
+			wantBody:   "a <% tp.file.title",
+			wantIssues: []string{"note.md:1:3: unclosed Templater tag"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ImportTemplater("note.md", tt.source)
+
+			_, body, found := strings.Cut(result.Content, "*/ -}}\n")
+			if !found {
+				t.Fatalf("content %q has no header", result.Content)
+			}
+			if body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+
+			var issues []string
+			for _, issue := range result.Issues {
+				issues = append(issues, issue.String())
+			}
+			if strings.Join(issues, "\n") != strings.Join(tt.wantIssues, "\n") {
+				t.Errorf("issues = %q, want %q", issues, tt.wantIssues)
+			}
+		})
+	}
+}
+
+func TestMomentLayout(t *testing.T) {
+	tests := []struct {
+		format  string
+		want    string
+		wantErr string
+	}{
+		{format: "YYYY-MM-DD", want: "2006-01-02"},
+		{format: "YY/M/D h:mm a", want: "06/1/2 3:04 pm"},
+		{format: "ddd, MMM DD HH:mm:ss.SSS ZZ",
+			want: "Mon, Jan 02 15:04:05.000 -0700"},
+		{format: "YYYY-MM-DDTHH:mm", want: "2006-01-02T15:04"},
+		{format: "DDDD", want: "002"},
+		{format: "[Week of] YYYY", want: "Week of 2006"},
+		{format: "gggg-[W]ww", wantErr: `token "gggg"`},
+		{format: "Do", wantErr: `token "D"`},
+		{format: "[Q1] YYYY", wantErr: "contains digits"},
+		{format: "[Monthly] YYYY", wantErr: `contains "Mon"`},
+		{format: "[oops", wantErr: "unclosed ["},
+		{format: "YYYY 1", wantErr: "must be escaped"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			got, err := momentLayout(tt.format)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("momentLayout() error = %v, want %q",
+						err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("momentLayout() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("momentLayout() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@
 // dependencies.
 package domain
 
+import "sort"
+
 // Schema defines metadata class structure with property constraints and
 // inheritance. Governs validation rules for notes of a given `fileClass`.
 // Schemas are loaded from JSON files
@@ -81,3 +83,18 @@ func (s *Schema) GetResolvedProperties() []Property {
 	}
 	return s.Properties
 }
+
+// OrderedProperties returns a copy of the resolved properties with required
+// properties first and each group sorted by name. Resolved properties come
+// from a map and have no order of their own, so anything shown to users or
+// written out from them uses this order to stay the same between runs.
+func (s *Schema) OrderedProperties() []Property {
+	properties := append([]Property(nil), s.GetResolvedProperties()...)
+	sort.SliceStable(properties, func(i, j int) bool {
+		if properties[i].Required != properties[j].Required {
+			return properties[i].Required
+		}
+		return properties[i].Name < properties[j].Name
+	})
+	return properties
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -186,3 +187,24 @@ func TestSchemaConstructorBehavior(t *testing.T) {
 		})
 	}
 }
+
+func TestSchemaOrderedProperties(t *testing.T) {
+	schema := NewSchema(testSchemaName, []Property{
+		NewProperty("tags", false, true, StringPropertySpec{}),
+		NewProperty("title", true, false, StringPropertySpec{}),
+		NewProperty("due", false, false, DatePropertySpec{}),
+		NewProperty("status", true, false, StringPropertySpec{}),
+	})
+
+	var got []string
+	for _, property := range schema.OrderedProperties() {
+		got = append(got, property.Name)
+	}
+	want := []string{"status", "title", "due", "tags"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("OrderedProperties() = %v, want %v", got, want)
+	}
+	if schema.Properties[0].Name != "tags" {
+		t.Error("OrderedProperties() should not reorder the schema")
+	}
+}
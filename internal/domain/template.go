@@ -2,13 +2,224 @@ package domain
 
 import (
 	"text/template"
+	"time"
 )
 
 // Template represents a template file used by the TemplateEngine for rendering.
 // It contains both the raw template content and optional cached parsed AST.
 type Template struct {
+	ID       string             // Stable identifier, e.g. "work/meeting"
 	FilePath string             // Absolute path to template file
 	Name     string             // Human-readable display name
 	Content  string             // Raw template text with Go template syntax
 	Parsed   *template.Template // Optional cached AST
+	Header   TemplateHeader     // Metadata declared in the template header
+	Partials []Partial          // Partials parsed into the template namespace
+}
+
+// TemplateHeader holds metadata a template declares about itself in a leading
+// `{{/* lithos ... */}}` comment block. The header is a Go template comment,
+// so it never appears in rendered output.
+type TemplateHeader struct {
+	// Description is a short human-readable summary of the template.
+	Description string
+
+	// Output is an optional Go template pattern for the generated note path,
+	// e.g. `projects/{{.title | slug}}.md`. Relative results are resolved
+	// against the vault root. Empty means the caller chooses the path.
+	Output string
+
+	// Schema optionally names the schema of the notes the template creates.
+	// Field references such as {{.title}} are checked against its properties
+	// by "lithos templates check".
+	Schema string
+
+	// Layout optionally names a partial that wraps the template. When set,
+	// rendering starts at the layout and the template body fills its
+	// "content" block; other blocks can be overridden with {{define}}.
+	Layout string
+
+	// Params declares the inputs the template expects, in declaration order.
+	// Templates without declared params accept any data unchecked.
+	Params []TemplateParam
+
+	// Bundle lists further notes created together with the template's own
+	// note, in order. Empty means the template creates a single note.
+	Bundle []BundleEntry
+}
+
+// BundleEntry declares a note that a template creates alongside its own. The
+// entry's template is rendered with the same data as the declaring template,
+// merged with Data, plus a "parent" value describing the declaring note so
+// the child can link back to it.
+type BundleEntry struct {
+	// Template is the ID or path of the child's template, resolved the same
+	// way as the template argument of "lithos new".
+	Template string
+
+	// Output optionally overrides the output path pattern declared by the
+	// child's template.
+	Output string
+
+	// Data holds values merged over the declaring template's data for this
+	// child only.
+	Data map[string]interface{}
+}
+
+// Template parameter types accepted in a template header. Date params
+// accept YYYY-MM-DD or RFC 3339 text and reach the template as time values,
+// so templates print them with dateFormat, e.g.
+// {{ .due | dateFormat "2006-01-02" }}.
+const (
+	ParamTypeString  = "string"
+	ParamTypeNumber  = "number"
+	ParamTypeInteger = "integer"
+	ParamTypeBool    = "bool"
+	ParamTypeDate    = "date"
+	ParamTypeList    = "list"
+)
+
+// TemplateParam declares a single input a template expects. Supplied values
+// are checked against Type before rendering; Default is used when the value
+// is not supplied.
+type TemplateParam struct {
+	// Name is the data key the template reads, e.g. "title" for {{.title}}.
+	Name string
+
+	// Type is one of the ParamType constants. Defaults to ParamTypeString.
+	Type string
+
+	// Required indicates the value must be supplied. Required params cannot
+	// declare a default.
+	Required bool
+
+	// Default is the value used when the param is not supplied, or nil when
+	// there is no default.
+	Default interface{}
+
+	// Description is a short human-readable explanation of the param.
+	Description string
+}
+
+// Partial is a named template fragment shared by every template. Partials are
+// parsed into each template's namespace, so `{{template "header" .}}` can
+// refer to a partial named "header", and {{block}}s they declare can be
+// overridden by the including template.
+type Partial struct {
+	Name    string // Name used with {{template}}, e.g. "header"
+	Path    string // Path of the partial file
+	Content string // Raw template text
+}
+
+// Keys of the structured values in the root object of a render. They take
+// precedence over user data of the same name.
+const (
+	RenderKeyData     = "Data"
+	RenderKeyNow      = "Now"
+	RenderKeyTarget   = "Target"
+	RenderKeyTemplate = "Template"
+	RenderKeyVault    = "Vault"
+)
+
+// RenderContext carries the values a template is executed against.
+// Data holds user-supplied input merged from every configured data source
+// (data files, stdin, and key=value pairs). The remaining fields describe
+// the render itself and are filled in by whoever knows them: the template
+// engine sets Now and Template, the caller sets Target and Vault.
+//
+// Templates see the context through Root: user data stays at the top level,
+// so `{{.title}}` resolves to Data["title"], next to `{{.Data}}`, `{{.Now}}`,
+// `{{.Target.Basename}}`, `{{.Template.ID}}`, and `{{.Vault.Root}}`.
+type RenderContext struct {
+	Data     map[string]interface{} // User-supplied template input
+	Now      time.Time              // Render time, set by the engine
+	Target   TargetInfo             // The note being written, if any
+	Template TemplateInfo           // The template being rendered
+	Vault    VaultInfo              // The vault the note belongs to
+}
+
+// TargetInfo describes the file a render is written to. Every field is
+// empty when the output does not go to a file, e.g. for "lithos render".
+type TargetInfo struct {
+	// Path is the target's path relative to the vault root with forward
+	// slashes, e.g. "projects/launch.md", or the path as given when the
+	// target lies outside the vault.
+	Path string
+
+	// Name is the file name with its extension, e.g. "launch.md".
+	Name string
+
+	// Basename is the file name without its extension, e.g. "launch", as
+	// shown by Obsidian.
+	Basename string
+
+	// Folder is the directory of Path, or "" for the vault root.
+	Folder string
+}
+
+// TemplateInfo identifies the template being rendered.
+type TemplateInfo struct {
+	ID   string // Template ID, e.g. "meeting" or "work/meeting"
+	Path string // Path of the template file, or "" for inline templates
+	Hash string // Hex SHA-256 of the template and its partials
+}
+
+// VaultInfo describes the vault a render belongs to.
+type VaultInfo struct {
+	// Root is the path of the vault root directory.
+	Root string
+
+	// Config holds the configuration values keyed by their names in the
+	// config file, e.g. "templatesDir" or "timezone".
+	Config map[string]interface{}
+}
+
+// NewRenderContext creates a RenderContext with an empty data map.
+func NewRenderContext() RenderContext {
+	return RenderContext{
+		Data: make(map[string]interface{}),
+	}
+}
+
+// Merge copies values into the context data, replacing existing keys.
+// Sources merged later therefore take precedence over earlier ones.
+func (rc *RenderContext) Merge(values map[string]interface{}) {
+	if rc.Data == nil {
+		rc.Data = make(map[string]interface{}, len(values))
+	}
+	for key, value := range values {
+		rc.Data[key] = value
+	}
+}
+
+// Root returns the root object templates are executed against: every data
+// value under its own key, plus the structured values under the RenderKey
+// names, which shadow data values of the same name.
+func (rc RenderContext) Root() map[string]interface{} {
+	root := make(map[string]interface{}, len(rc.Data)+5)
+	for key, value := range rc.Data {
+		root[key] = value
+	}
+	data := rc.Data
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	root[RenderKeyData] = data
+	root[RenderKeyNow] = rc.Now
+	root[RenderKeyTarget] = rc.Target
+	root[RenderKeyTemplate] = rc.Template
+	root[RenderKeyVault] = rc.Vault
+	return root
+}
+
+// IsRenderKey reports whether key names one of the structured values of the
+// render root rather than user data.
+func IsRenderKey(key string) bool {
+	switch key {
+	case RenderKeyData, RenderKeyNow, RenderKeyTarget, RenderKeyTemplate,
+		RenderKeyVault:
+		return true
+	default:
+		return false
+	}
 }
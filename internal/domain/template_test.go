@@ -160,3 +160,105 @@ Author: {{.author}}
 		t.Error("Template content is empty")
 	}
 }
+
+func TestRenderContextMerge(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources []map[string]interface{}
+		want    map[string]interface{}
+	}{
+		{
+			name:    "no sources",
+			sources: nil,
+			want:    map[string]interface{}{},
+		},
+		{
+			name: "single source",
+			sources: []map[string]interface{}{
+				{"title": "Weekly Sync"},
+			},
+			want: map[string]interface{}{"title": "Weekly Sync"},
+		},
+		{
+			name: "later sources override earlier ones",
+			sources: []map[string]interface{}{
+				{"title": "From File", "author": "Ada"},
+				{"title": "From Flag"},
+			},
+			want: map[string]interface{}{
+				"title":  "From Flag",
+				"author": "Ada",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := NewRenderContext()
+			for _, source := range tt.sources {
+				rc.Merge(source)
+			}
+
+			if len(rc.Data) != len(tt.want) {
+				t.Fatalf("Data = %v, want %v", rc.Data, tt.want)
+			}
+			for key, want := range tt.want {
+				if rc.Data[key] != want {
+					t.Errorf("Data[%q] = %v, want %v", key, rc.Data[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderContextMergeZeroValue(t *testing.T) {
+	var rc RenderContext
+	rc.Merge(map[string]interface{}{"title": "Zero"})
+
+	if rc.Data["title"] != "Zero" {
+		t.Errorf("Data[title] = %v, want %q", rc.Data["title"], "Zero")
+	}
+}
+
+func TestRenderContextRoot(t *testing.T) {
+	rc := NewRenderContext()
+	rc.Merge(map[string]interface{}{"title": "Plan", "Target": "shadowed"})
+	rc.Target = TargetInfo{Basename: "plan"}
+
+	root := rc.Root()
+
+	if root["title"] != "Plan" {
+		t.Errorf("root[title] = %v, want %q", root["title"], "Plan")
+	}
+	data, ok := root[RenderKeyData].(map[string]interface{})
+	if !ok || data["title"] != "Plan" {
+		t.Errorf("root[Data] = %v, want the data map", root[RenderKeyData])
+	}
+	target, ok := root[RenderKeyTarget].(TargetInfo)
+	if !ok || target.Basename != "plan" {
+		t.Errorf("root[Target] = %v, want the target", root[RenderKeyTarget])
+	}
+}
+
+func TestIsRenderKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{key: RenderKeyData, want: true},
+		{key: RenderKeyNow, want: true},
+		{key: RenderKeyTarget, want: true},
+		{key: RenderKeyTemplate, want: true},
+		{key: RenderKeyVault, want: true},
+		{key: "target", want: false},
+		{key: "title", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := IsRenderKey(tt.key); got != tt.want {
+				t.Errorf("IsRenderKey(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
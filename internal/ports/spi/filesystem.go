@@ -7,6 +7,16 @@
 // that allow the domain to remain independent of external dependencies.
 package spi
 
+import "time"
+
+// FileInfo describes a file or directory returned by FileSystemPort.Stat.
+type FileInfo struct {
+	Path    string    // Path that was inspected
+	IsDir   bool      // True when the path is a directory
+	Size    int64     // Size in bytes (0 for directories)
+	ModTime time.Time // Last modification time
+}
+
 // WalkFunc is the callback function type used by the Walk method.
 // It receives the file path and file info for each file/directory encountered.
 // Return an error to stop walking and propagate the error up.
@@ -35,4 +45,37 @@ type FileSystemPort interface {
 	// and a boolean indicating if it's a directory.
 	// If fn returns an error, walking stops and the error is returned.
 	Walk(root string, fn WalkFunc) error
+
+	// Stat returns metadata for the file or directory at path.
+	// When nothing exists at path, the returned error wraps fs.ErrNotExist so
+	// callers can distinguish "missing" from other failures with errors.Is.
+	Stat(path string) (FileInfo, error)
+}
+
+// PendingWrite describes a write recorded, but not performed, in dry-run
+// mode.
+type PendingWrite struct {
+	Path    string // Path that would be written
+	Existed bool   // True when the write would replace an existing file
+	Before  []byte // Content the write would replace (nil for new files)
+	After   []byte // Content that would be written
+}
+
+// DryRunFileSystemPort is a FileSystemPort that can be switched into dry-run
+// mode. In dry-run mode writes are recorded instead of performed, and later
+// reads see the recorded content, so commands behave as they would for real
+// without touching the vault.
+type DryRunFileSystemPort interface {
+	FileSystemPort
+
+	// EnableDryRun switches the port into dry-run mode for the rest of its
+	// lifetime.
+	EnableDryRun()
+
+	// DryRunEnabled reports whether writes are being recorded.
+	DryRunEnabled() bool
+
+	// PendingWrites returns the recorded writes in the order their paths were
+	// first written. Repeated writes to a path keep its original Before.
+	PendingWrites() []PendingWrite
 }
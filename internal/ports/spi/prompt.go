@@ -0,0 +1,77 @@
+// Package spi defines service provider interface ports for interactive input.
+package spi
+
+import "context"
+
+// PromptConfig describes a question asked by the prompt template function.
+type PromptConfig struct {
+	// Label is the question shown to the user, e.g. "Title". It also keys
+	// the answer when answers are scripted.
+	Label string
+
+	// Default is the answer used when the user enters nothing. Empty means
+	// an empty answer is accepted as it is.
+	Default string
+
+	// Help optionally describes the expected answer, e.g. "a date in the
+	// format 2006-01-02", and is shown alongside the label.
+	Help string
+
+	// Validate optionally checks an answer, after the default is applied.
+	// Adapters ask again when a typed answer fails, and fail when a scripted
+	// one does. Nil accepts every answer.
+	Validate func(answer string) error
+}
+
+// SuggesterOption is one of the choices offered by a suggester.
+type SuggesterOption struct {
+	Label string // Text shown to the user
+	Value string // Value the template receives when the option is chosen
+}
+
+// SuggesterConfig describes a choice asked by the suggester template
+// function.
+type SuggesterConfig struct {
+	// Label is the question shown to the user, e.g. "Pick a status". It also
+	// keys the answer when answers are scripted.
+	Label string
+
+	// Options are the choices, in the order they are offered.
+	Options []SuggesterOption
+}
+
+// ConfirmConfig describes a yes/no question.
+type ConfirmConfig struct {
+	// Label is the question shown to the user, e.g. "Archived". It also keys
+	// the answer when answers are scripted.
+	Label string
+
+	// Default is the answer used when the user enters nothing.
+	Default bool
+}
+
+// PromptPort asks the user for the values of the prompt and suggester
+// template functions and of the "lithos new --interactive" form. Adapters
+// decide where the answers come from: a terminal, a file of scripted
+// answers, or both.
+type PromptPort interface {
+	// Prompt asks the question described by cfg and returns the answer.
+	Prompt(ctx context.Context, cfg PromptConfig) (string, error)
+
+	// Suggester asks the user to choose one of cfg.Options and returns the
+	// Value of the chosen option.
+	Suggester(ctx context.Context, cfg SuggesterConfig) (string, error)
+
+	// Confirm asks the yes/no question described by cfg.
+	Confirm(ctx context.Context, cfg ConfirmConfig) (bool, error)
+}
+
+// ScriptedPromptPort is a PromptPort that can be given answers in advance,
+// so that templates with prompts render without anyone at a terminal.
+type ScriptedPromptPort interface {
+	PromptPort
+
+	// AddAnswers records answers keyed by question label. Later answers for
+	// a label replace earlier ones.
+	AddAnswers(answers map[string]string)
+}
@@ -11,6 +11,7 @@ import (
 	"context"
 
 	"github.com/JackMatanky/lithos/internal/domain"
+	"github.com/JackMatanky/lithos/internal/shared/errors"
 )
 
 // SchemaLoaderPort provides schema loading operations for domain services.
@@ -69,3 +70,14 @@ type SchemaRegistryPort interface {
 	// found in the registry.
 	Get(name string) (domain.Schema, bool)
 }
+
+// SchemaRegistryLoaderPort is a SchemaRegistryPort whose schemas are loaded on
+// demand. Services that need schemas call Initialize before Get, so commands
+// that never look at schemas do not require a valid schemas directory.
+type SchemaRegistryLoaderPort interface {
+	SchemaRegistryPort
+
+	// Initialize loads schemas and the property bank, resolves inheritance,
+	// and populates the registry. Calling it again reloads the registry.
+	Initialize(ctx context.Context) errors.Result[struct{}]
+}
@@ -10,11 +10,14 @@ import (
 )
 
 // TemplateMetadata provides information about an available template.
+// ID is the template path relative to the templates directory without its
+// extension (e.g. "meetings/weekly"), using forward slashes on every platform.
 type TemplateMetadata struct {
-	ID       string
-	Name     string
-	FilePath string
-	Content  string
+	ID          string
+	Name        string
+	FilePath    string
+	Content     string
+	Description string
 }
 
 // TemplateRepositoryPort provides access to template storage and enumeration.
@@ -24,8 +27,9 @@ type TemplateRepositoryPort interface {
 	// List returns metadata for all available templates.
 	List(ctx context.Context) ([]TemplateMetadata, error)
 
-	// Get retrieves a specific template by ID.
-	// Returns an error if the template is not found.
+	// Get retrieves a specific template by ID. A bare name matches the
+	// template with that base name when it is unique.
+	// Returns a TemplateNotFoundError if the template is not found.
 	Get(ctx context.Context, id string) (*domain.Template, error)
 
 	// GetByPath loads a template from a specific file path.
@@ -35,6 +39,10 @@ type TemplateRepositoryPort interface {
 		ctx context.Context,
 		path string,
 	) (*domain.Template, error)
+
+	// ListPartials returns the partials shared by every template, sorted by
+	// name.
+	ListPartials(ctx context.Context) ([]domain.Partial, error)
 }
 
 // TemplateParser defines the interface for parsing template content.
@@ -47,6 +55,16 @@ type TemplateParser interface {
 		ctx context.Context,
 		templateContent string,
 	) errors.Result[*template.Template]
+
+	// ParseWithPartials parses the template content into a namespace that
+	// already contains the given partials. Definitions in the template
+	// content take precedence over blocks declared by partials.
+	// Returns an error if the template or any partial has syntax errors.
+	ParseWithPartials(
+		ctx context.Context,
+		templateContent string,
+		partials []domain.Partial,
+	) errors.Result[*template.Template]
 }
 
 // TemplateExecutor defines the interface for executing parsed templates.
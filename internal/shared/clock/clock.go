@@ -0,0 +1,125 @@
+// Package clock provides the time source used wherever Lithos needs the
+// current time, so a render can be pinned to a fixed moment for golden tests,
+// CI snapshots, and back-dated notes.
+package clock
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Clock supplies the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// inputLayouts lists the textual forms accepted by Parse, most specific
+// first.
+var inputLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// systemClock reads the system time.
+type systemClock struct{}
+
+// Now returns time.Now().
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// System returns a Clock backed by the system time.
+func System() Clock {
+	return systemClock{}
+}
+
+// fixedClock always returns the same instant.
+type fixedClock struct {
+	t time.Time
+}
+
+// Now returns the fixed instant.
+func (c fixedClock) Now() time.Time {
+	return c.t
+}
+
+// Fixed returns a Clock that always reports t.
+func Fixed(t time.Time) Clock {
+	return fixedClock{t: t}
+}
+
+// Pinnable is a Clock that follows a base clock until it is pinned to a fixed
+// instant. It lets the composition root hand one clock to every consumer
+// while the CLI decides, after parsing flags, whether time is pinned.
+// Pinnable is safe for concurrent use.
+type Pinnable struct {
+	mu     sync.RWMutex
+	base   Clock
+	pinned *time.Time
+}
+
+// NewPinnable creates a Pinnable following base, or the system clock when
+// base is nil.
+func NewPinnable(base Clock) *Pinnable {
+	if base == nil {
+		base = System()
+	}
+	return &Pinnable{base: base}
+}
+
+// Now returns the pinned instant, or the base clock's time when unpinned.
+func (c *Pinnable) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.pinned != nil {
+		return *c.pinned
+	}
+	return c.base.Now()
+}
+
+// Pin fixes the time reported by Now to t.
+func (c *Pinnable) Pin(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pinned = &t
+}
+
+// Pinned reports the pinned instant and whether the clock is pinned.
+func (c *Pinnable) Pinned() (time.Time, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.pinned == nil {
+		return time.Time{}, false
+	}
+	return *c.pinned, true
+}
+
+// Parse parses a pinned time such as "2025-03-14", "2025-03-14 09:30", or
+// "2025-03-14T09:30:00+01:00". Times without an offset are interpreted in
+// loc, or in time.Local when loc is nil; a bare date means midnight.
+func Parse(text string, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	trimmed := strings.TrimSpace(text)
+	for _, layout := range inputLayouts {
+		if t, err := time.ParseInLocation(layout, trimmed, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf(
+		"invalid time %q (expected e.g. 2025-03-14, 2025-03-14 09:30, "+
+			"or RFC 3339)",
+		text,
+	)
+}
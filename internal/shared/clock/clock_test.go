@@ -0,0 +1,84 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPinnable(t *testing.T) {
+	base := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	c := NewPinnable(Fixed(base))
+
+	if got := c.Now(); !got.Equal(base) {
+		t.Errorf("Now() before Pin = %v, want %v", got, base)
+	}
+	if _, ok := c.Pinned(); ok {
+		t.Error("Pinned() before Pin should report false")
+	}
+
+	pinned := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	c.Pin(pinned)
+
+	if got := c.Now(); !got.Equal(pinned) {
+		t.Errorf("Now() after Pin = %v, want %v", got, pinned)
+	}
+	if got, ok := c.Pinned(); !ok || !got.Equal(pinned) {
+		t.Errorf("Pinned() = %v, %v, want %v, true", got, ok, pinned)
+	}
+}
+
+func TestNewPinnable_DefaultsToSystem(t *testing.T) {
+	before := time.Now()
+	got := NewPinnable(nil).Now()
+	if got.Before(before) || got.After(time.Now()) {
+		t.Errorf("Now() = %v, want the system time", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name:  "date",
+			input: "2025-03-14",
+			want:  time.Date(2025, 3, 14, 0, 0, 0, 0, berlin),
+		},
+		{
+			name:  "date and minutes",
+			input: "2025-03-14 09:30",
+			want:  time.Date(2025, 3, 14, 9, 30, 0, 0, berlin),
+		},
+		{
+			name:  "date and seconds with T",
+			input: " 2025-03-14T09:30:15 ",
+			want:  time.Date(2025, 3, 14, 9, 30, 15, 0, berlin),
+		},
+		{
+			name:  "RFC 3339 keeps its offset",
+			input: "2025-03-14T09:30:00Z",
+			want:  time.Date(2025, 3, 14, 9, 30, 0, 0, time.UTC),
+		},
+		{name: "invalid", input: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input, berlin)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+// Package diff renders line-based unified diffs, used to preview how a write
+// would change an existing file.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change.
+const contextLines = 3
+
+// noNewline marks a final line that lacks a trailing newline.
+const noNewline = "\\ No newline at end of file\n"
+
+// op is one line of an edit script: kept (' '), removed ('-'), or added ('+').
+type op struct {
+	kind byte
+	line string
+}
+
+// Unified returns the unified diff that turns oldText into newText, labelled
+// with oldName and newName. It returns "" when the texts are equal.
+func Unified(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	ops := editScript(splitLines(oldText), splitLines(newText))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+	for _, h := range hunks(ops) {
+		writeHunk(&out, ops, h)
+	}
+	return out.String()
+}
+
+// splitLines splits text into lines, each keeping its trailing newline.
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// editScript computes a minimal edit script from a to b using the longest
+// common subsequence of their lines.
+func editScript(a, b []string) []op {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]op, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, op{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, op{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, op{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, op{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, op{'+', b[j]})
+	}
+	return ops
+}
+
+// hunk is a half-open range of ops rendered together.
+type hunk struct {
+	start, end int
+}
+
+// hunks groups the changes in ops into hunks with surrounding context,
+// merging changes whose context would overlap.
+func hunks(ops []op) []hunk {
+	var result []hunk
+	for i, o := range ops {
+		if o.kind == ' ' {
+			continue
+		}
+		start := max(0, i-contextLines)
+		end := min(len(ops), i+contextLines+1)
+		if n := len(result); n > 0 && start <= result[n-1].end {
+			result[n-1].end = end
+			continue
+		}
+		result = append(result, hunk{start, end})
+	}
+	return result
+}
+
+// writeHunk writes the header and lines of h.
+func writeHunk(out *strings.Builder, ops []op, h hunk) {
+	oldStart, newStart := 1, 1
+	for _, o := range ops[:h.start] {
+		if o.kind != '+' {
+			oldStart++
+		}
+		if o.kind != '-' {
+			newStart++
+		}
+	}
+
+	oldCount, newCount := 0, 0
+	for _, o := range ops[h.start:h.end] {
+		if o.kind != '+' {
+			oldCount++
+		}
+		if o.kind != '-' {
+			newCount++
+		}
+	}
+
+	fmt.Fprintf(
+		out,
+		"@@ -%s +%s @@\n",
+		hunkRange(oldStart, oldCount),
+		hunkRange(newStart, newCount),
+	)
+	for _, o := range ops[h.start:h.end] {
+		out.WriteByte(o.kind)
+		out.WriteString(o.line)
+		if !strings.HasSuffix(o.line, "\n") {
+			out.WriteString("\n" + noNewline)
+		}
+	}
+}
+
+// hunkRange formats the "start,count" part of a hunk header. An empty range
+// refers to the line before it, as in GNU diff.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	default:
+		return fmt.Sprintf("%d,%d", start, count)
+	}
+}
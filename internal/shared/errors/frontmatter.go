@@ -204,3 +204,45 @@ func (vr *ValidationResult) AddError(fieldErr FieldValidationError) {
 func (vr ValidationResult) IsValid() bool {
 	return len(vr.Errors) == 0
 }
+
+// FrontmatterValidationError reports every field of a frontmatter block that
+// does not satisfy its schema.
+type FrontmatterValidationError struct {
+	BaseError
+	schema string
+	fields []FieldValidationError
+}
+
+// NewFrontmatterValidationError creates an error for the invalid fields
+// recorded in result when validating against schema.
+func NewFrontmatterValidationError(
+	schema string,
+	result ValidationResult,
+) FrontmatterValidationError {
+	message := fmt.Sprintf(
+		"frontmatter does not match schema '%s': %d invalid field(s)",
+		schema,
+		len(result.Errors),
+	)
+	return FrontmatterValidationError{
+		BaseError: NewBaseError(message, nil),
+		schema:    schema,
+		fields:    result.Errors,
+	}
+}
+
+// Schema returns the name of the schema the frontmatter was validated
+// against.
+func (e FrontmatterValidationError) Schema() string {
+	return e.schema
+}
+
+// Fields returns the validation error of each invalid field.
+func (e FrontmatterValidationError) Fields() []FieldValidationError {
+	return e.fields
+}
+
+// Domain identifies the frontmatter validation domain.
+func (e FrontmatterValidationError) Domain() string {
+	return domainFrontmatter
+}
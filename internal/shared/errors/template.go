@@ -1,37 +1,108 @@
 package errors
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
+
+// Render limits named by TemplateError.Limit.
+const (
+	// TemplateLimitTimeout is the maximum time a render may take.
+	TemplateLimitTimeout = "render timeout"
+
+	// TemplateLimitOutputSize is the maximum size of rendered output.
+	TemplateLimitOutputSize = "max output size"
+)
 
 // TemplateError captures problems encountered while parsing or executing
-// templates. Only the template identifier, optional line number, and cause are
-// retained to keep the type lean.
+// templates. Besides the template identifier and cause it keeps the position
+// of the problem when known, together with the offending source line so
+// callers can show an excerpt. Errors caused by a render limit name it.
 type TemplateError struct {
 	BaseError
-	template string
-	line     int
+	template   string
+	line       int
+	column     int
+	sourceLine string
+	reason     string
+	causeShown bool
+	limit      string
 }
 
 // NewTemplateError creates a TemplateError for the provided template name.
+// The cause, when given, is appended to the message.
 func NewTemplateError(
 	template string,
 	line int,
 	reason string,
 	cause error,
 ) TemplateError {
-	context := fmt.Sprintf("template '%s'", template)
+	return newTemplateError(template, line, 0, "", reason, cause, true)
+}
+
+// NewTemplateErrorAt creates a TemplateError pinpointing a position in the
+// template source: a 1-based line, a 1-based column (0 when only the line is
+// known), and the text of that line. Unlike NewTemplateError, reason is
+// expected to describe the cause already, so the cause is retained for
+// errors.Is/As but not repeated in the message.
+func NewTemplateErrorAt(
+	template string,
+	line, column int,
+	sourceLine, reason string,
+	cause error,
+) TemplateError {
+	return newTemplateError(
+		template, line, column, sourceLine, reason, cause, false,
+	)
+}
+
+// NewTemplateLimitError creates a TemplateError for a render that was stopped
+// because it hit limit, one of the TemplateLimit constants. The cause is
+// retained for errors.Is/As but not repeated in the message.
+func NewTemplateLimitError(
+	template string,
+	limit string,
+	reason string,
+	cause error,
+) TemplateError {
+	err := newTemplateError(template, 0, 0, "", reason, cause, false)
+	err.limit = limit
+	return err
+}
+
+// newTemplateError builds the message shared by the TemplateError
+// constructors.
+func newTemplateError(
+	template string,
+	line, column int,
+	sourceLine, reason string,
+	cause error,
+	showCause bool,
+) TemplateError {
+	context := "template"
+	if template != "" {
+		context = fmt.Sprintf("template '%s'", template)
+	}
 	if line > 0 {
 		context = fmt.Sprintf("%s line %d", context, line)
+		if column > 0 {
+			context = fmt.Sprintf("%s column %d", context, column)
+		}
 	}
 
 	message := fmt.Sprintf("%s: %s", context, reason)
-	if cause != nil {
+	if showCause && cause != nil {
 		message = fmt.Sprintf("%s: %v", message, cause)
 	}
 
 	return TemplateError{
-		BaseError: NewBaseError(message, cause),
-		template:  template,
-		line:      line,
+		BaseError:  NewBaseError(message, cause),
+		template:   template,
+		line:       line,
+		column:     column,
+		sourceLine: sourceLine,
+		reason:     reason,
+		causeShown: showCause,
 	}
 }
 
@@ -44,3 +115,135 @@ func (e TemplateError) Template() string {
 func (e TemplateError) Line() int {
 	return e.line
 }
+
+// Column returns the 1-based column (in bytes) when available, or 0 when
+// unspecified.
+func (e TemplateError) Column() int {
+	return e.column
+}
+
+// Limit returns the render limit that stopped the template, or "" when the
+// error was not caused by a limit.
+func (e TemplateError) Limit() string {
+	return e.limit
+}
+
+// WithTemplate returns a copy of the error attributed to template. Parsers
+// that do not know the name of the template they parse leave it empty for
+// the caller to fill in.
+func (e TemplateError) WithTemplate(template string) TemplateError {
+	named := newTemplateError(
+		template,
+		e.line,
+		e.column,
+		e.sourceLine,
+		e.reason,
+		e.Cause(),
+		e.causeShown,
+	)
+	named.limit = e.limit
+	return named
+}
+
+// Excerpt returns the offending source line prefixed with its line number,
+// with a caret under the column when known:
+//
+//	12 | {{ .title | nope }}
+//	   |             ^
+//
+// It returns an empty string when the source line is unknown.
+func (e TemplateError) Excerpt() string {
+	if e.line <= 0 || e.sourceLine == "" {
+		return ""
+	}
+
+	gutter := fmt.Sprintf("%4d | ", e.line)
+	excerpt := gutter + e.sourceLine
+	if e.column <= 0 {
+		return excerpt
+	}
+
+	// Pad with the same whitespace as the source so tabs line up.
+	prefix := e.sourceLine
+	if e.column-1 < len(prefix) {
+		prefix = prefix[:e.column-1]
+	}
+	var padding strings.Builder
+	for _, r := range prefix {
+		if r == '\t' {
+			padding.WriteRune('\t')
+		} else {
+			padding.WriteRune(' ')
+		}
+	}
+
+	blankGutter := strings.Repeat(" ", len(gutter)-2) + "| "
+	return excerpt + "\n" + blankGutter + padding.String() + "^"
+}
+
+// TemplateNotFoundError indicates a template lookup by identifier failed.
+type TemplateNotFoundError struct {
+	BaseError
+	template string
+}
+
+// NewTemplateNotFoundError constructs a not found error for templateID.
+func NewTemplateNotFoundError(templateID string) TemplateNotFoundError {
+	message := fmt.Sprintf("template '%s' not found", templateID)
+
+	return TemplateNotFoundError{
+		BaseError: NewBaseError(message, nil),
+		template:  templateID,
+	}
+}
+
+// Template returns the missing template identifier.
+func (e TemplateNotFoundError) Template() string {
+	return e.template
+}
+
+// TemplateParamsError reports every supplied input that failed to satisfy the
+// parameters a template declares. Each problem is a ValidationError naming the
+// offending parameter.
+type TemplateParamsError struct {
+	BaseError
+	template string
+	problems []ValidationError
+}
+
+// NewTemplateParamsError constructs a parameter error for template listing
+// each problem on its own line.
+func NewTemplateParamsError(
+	template string,
+	problems []ValidationError,
+) TemplateParamsError {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "template '%s': invalid parameters", template)
+	for i := range problems {
+		fmt.Fprintf(
+			&builder,
+			"\n  - %s: %s",
+			problems[i].Property(),
+			problems[i].Reason(),
+		)
+		if value := problems[i].Value(); value != nil {
+			fmt.Fprintf(&builder, " (got %v)", value)
+		}
+	}
+
+	return TemplateParamsError{
+		BaseError: NewBaseError(builder.String(), nil),
+		template:  template,
+		problems:  problems,
+	}
+}
+
+// Template returns the template identifier.
+func (e TemplateParamsError) Template() string {
+	return e.template
+}
+
+// Problems returns the individual parameter validation failures.
+func (e TemplateParamsError) Problems() []ValidationError {
+	return e.problems
+}
@@ -148,6 +148,27 @@ func TestFrontmatterErrors(t *testing.T) {
 	}
 }
 
+func TestFrontmatterValidationError(t *testing.T) {
+	result := NewValidationResult()
+	result.AddError(NewRequiredFieldError(testPropertyTitle))
+	result.AddError(NewArrayConstraintError("tags", "foo", "array"))
+
+	err := NewFrontmatterValidationError("note", result)
+	expected := "frontmatter does not match schema 'note': 2 invalid field(s)"
+	if err.Error() != expected {
+		t.Fatalf("unexpected error string: %s", err.Error())
+	}
+	if err.Schema() != "note" || len(err.Fields()) != 2 {
+		t.Fatalf("frontmatter validation metadata incorrect")
+	}
+	if err.Fields()[1].Field() != "tags" {
+		t.Fatalf("field errors not preserved in order")
+	}
+	if err.Domain() != domainFrontmatter {
+		t.Fatalf("expected frontmatter domain")
+	}
+}
+
 func TestTemplateError(t *testing.T) {
 	err := NewTemplateError("header.tmpl", 5, "undefined placeholder", nil)
 	if err.Template() != "header.tmpl" || err.Line() != 5 {
@@ -158,3 +179,87 @@ func TestTemplateError(t *testing.T) {
 		t.Fatalf("unexpected template error string: %s", err.Error())
 	}
 }
+
+func TestTemplateErrorAt(t *testing.T) {
+	cause := errors.New("raw parser error")
+	err := NewTemplateErrorAt(
+		"",
+		12,
+		9,
+		"title: {{ .title | nope }}",
+		`function "nope" not defined`,
+		cause,
+	)
+
+	if err.Error() != `template line 12 column 9: function "nope" not defined` {
+		t.Fatalf("unexpected unnamed message: %s", err.Error())
+	}
+	if !errors.Is(err, cause) {
+		t.Fatalf("positioned error should wrap its cause")
+	}
+
+	named := err.WithTemplate("meeting")
+	expected := "template 'meeting' line 12 column 9: " +
+		`function "nope" not defined`
+	if named.Error() != expected || named.Column() != 9 {
+		t.Fatalf("unexpected named error: %s", named.Error())
+	}
+
+	excerpt := "  12 | title: {{ .title | nope }}\n" +
+		"     |         ^"
+	if named.Excerpt() != excerpt {
+		t.Fatalf("unexpected excerpt:\n%s", named.Excerpt())
+	}
+
+	if NewTemplateError("x", 3, "boom", nil).Excerpt() != "" {
+		t.Fatalf("errors without a source line should have no excerpt")
+	}
+}
+
+func TestTemplateLimitError(t *testing.T) {
+	err := NewTemplateLimitError(
+		"daily",
+		TemplateLimitOutputSize,
+		"output exceeds the maximum size of 10 bytes",
+		nil,
+	)
+	expected := "template 'daily': output exceeds the maximum size of 10 bytes"
+	if err.Error() != expected {
+		t.Fatalf("unexpected limit error string: %s", err.Error())
+	}
+	if err.Limit() != TemplateLimitOutputSize {
+		t.Fatalf("limit not preserved: %q", err.Limit())
+	}
+	if err.WithTemplate("weekly").Limit() != TemplateLimitOutputSize {
+		t.Fatalf("WithTemplate should keep the limit")
+	}
+	if NewTemplateError("x", 1, "boom", nil).Limit() != "" {
+		t.Fatalf("ordinary template errors should name no limit")
+	}
+}
+
+func TestTemplateNotFoundError(t *testing.T) {
+	err := NewTemplateNotFoundError("meeting")
+	if err.Template() != "meeting" {
+		t.Fatalf("template not found error missing template metadata")
+	}
+	if err.Error() != "template 'meeting' not found" {
+		t.Fatalf("unexpected template not found message: %s", err.Error())
+	}
+}
+
+func TestTemplateParamsError(t *testing.T) {
+	err := NewTemplateParamsError("meeting", []ValidationError{
+		NewValidationError("title", "is required but missing", nil),
+		NewValidationError("count", "must be an integer", "many"),
+	})
+	if err.Template() != "meeting" || len(err.Problems()) != 2 {
+		t.Fatalf("template params error metadata incorrect")
+	}
+	expected := "template 'meeting': invalid parameters\n" +
+		"  - title: is required but missing\n" +
+		"  - count: must be an integer (got many)"
+	if err.Error() != expected {
+		t.Fatalf("unexpected template params message: %s", err.Error())
+	}
+}
@@ -7,13 +7,20 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/JackMatanky/lithos/internal/adapters/api/cli"
 	"github.com/JackMatanky/lithos/internal/adapters/spi/filesystem"
 	templaterepo "github.com/JackMatanky/lithos/internal/adapters/spi/template"
+	"github.com/JackMatanky/lithos/internal/app/note"
 	templatedomain "github.com/JackMatanky/lithos/internal/app/template"
+	"github.com/JackMatanky/lithos/internal/shared/clock"
+	testutils "github.com/JackMatanky/lithos/tests/utils"
 )
 
+// pinnedRenderTime is the render time the integration tests pin the clock to.
+var pinnedRenderTime = time.Date(2025, 10, 19, 9, 30, 0, 0, time.UTC)
+
 // findProjectRoot finds the project root directory by looking for go.mod.
 func findProjectRoot(t *testing.T) string {
 	_, filename, _, ok := runtime.Caller(0)
@@ -111,8 +118,14 @@ func TestNewCommand_Integration_CompleteFlow(t *testing.T) {
 	// Create real filesystem adapter
 	fsAdapter := filesystem.NewLocalFileSystemAdapter()
 
+	// Pin the render clock so date output is deterministic
+	renderClock := clock.NewPinnable(nil)
+	renderClock.Pin(pinnedRenderTime)
+
 	// Create template parser and executor from domain services
-	templateParser := templatedomain.NewStaticTemplateParser()
+	templateParser := templatedomain.NewStaticTemplateParserWithOptions(
+		templatedomain.FuncMapOptions{Location: time.UTC, Clock: renderClock},
+	)
 	templateExecutor := templatedomain.NewGoTemplateExecutor()
 
 	// Create template engine and repository
@@ -120,10 +133,21 @@ func TestNewCommand_Integration_CompleteFlow(t *testing.T) {
 		templateParser,
 		templateExecutor,
 	)
-	templateRepo := templaterepo.NewFSAdapter(fsAdapter, templateParser)
+	templateRepo := templaterepo.NewFSAdapter(
+		fsAdapter,
+		templateParser,
+		testutils.NewMockConfigPort(tempDir),
+	)
 
 	// Create CLI adapter with injected dependencies
-	adapter := cli.NewCobraCLIAdapter(templateEngine, templateRepo, fsAdapter)
+	adapter := cli.NewCobraCLIAdapter(cli.AdapterOptions{
+		TemplateEngine: templateEngine,
+		TemplateRepo:   templateRepo,
+		FileSystemPort: fsAdapter,
+		ConfigPort:     testutils.NewMockConfigPort(tempDir),
+		NoteWriter:     note.NewWriterWithClock(fsAdapter, renderClock),
+		Clock:          renderClock,
+	})
 
 	// Execute the new command with testdata template
 	exitCode := adapter.Execute([]string{"new", templatePath})
@@ -151,9 +175,8 @@ func TestNewCommand_Integration_CompleteFlow(t *testing.T) {
 		t.Error("toUpper function was not applied correctly")
 	}
 
-	// Check that date function was applied
-	if !strings.Contains(outputStr, "2025-") ||
-		!strings.Contains(outputStr, "-10-") {
+	// Check that date function used the pinned clock
+	if !strings.Contains(outputStr, "2025-10-19") {
 		t.Error("now function was not applied correctly")
 	}
 }
@@ -187,8 +210,14 @@ func TestNewCommand_Integration_AtomicWrite(t *testing.T) {
 	// Create real filesystem adapter
 	fsAdapter := filesystem.NewLocalFileSystemAdapter()
 
+	// Pin the render clock so date output is deterministic
+	renderClock := clock.NewPinnable(nil)
+	renderClock.Pin(pinnedRenderTime)
+
 	// Create template parser and executor from domain services
-	templateParser := templatedomain.NewStaticTemplateParser()
+	templateParser := templatedomain.NewStaticTemplateParserWithOptions(
+		templatedomain.FuncMapOptions{Location: time.UTC, Clock: renderClock},
+	)
 	templateExecutor := templatedomain.NewGoTemplateExecutor()
 
 	// Create template engine and repository
@@ -196,10 +225,21 @@ func TestNewCommand_Integration_AtomicWrite(t *testing.T) {
 		templateParser,
 		templateExecutor,
 	)
-	templateRepo := templaterepo.NewFSAdapter(fsAdapter, templateParser)
+	templateRepo := templaterepo.NewFSAdapter(
+		fsAdapter,
+		templateParser,
+		testutils.NewMockConfigPort(tempDir),
+	)
 
 	// Create CLI adapter with injected dependencies
-	adapter := cli.NewCobraCLIAdapter(templateEngine, templateRepo, fsAdapter)
+	adapter := cli.NewCobraCLIAdapter(cli.AdapterOptions{
+		TemplateEngine: templateEngine,
+		TemplateRepo:   templateRepo,
+		FileSystemPort: fsAdapter,
+		ConfigPort:     testutils.NewMockConfigPort(tempDir),
+		NoteWriter:     note.NewWriterWithClock(fsAdapter, renderClock),
+		Clock:          renderClock,
+	})
 
 	// Execute the new command with testdata template
 	exitCode := adapter.Execute([]string{"new", templatePath})
@@ -227,9 +267,8 @@ func TestNewCommand_Integration_AtomicWrite(t *testing.T) {
 		t.Error("toLower function was not applied correctly")
 	}
 
-	// Check that date function was applied
-	if !strings.Contains(outputStr, "2025-") ||
-		!strings.Contains(outputStr, "-10-") {
+	// Check that date function used the pinned clock
+	if !strings.Contains(outputStr, "2025-10-19") {
 		t.Error("now function was not applied correctly")
 	}
 }
@@ -2,15 +2,20 @@ package integration
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/JackMatanky/lithos/internal/adapters/spi/filesystem"
 	templaterepo "github.com/JackMatanky/lithos/internal/adapters/spi/template"
 	templatedomain "github.com/JackMatanky/lithos/internal/app/template"
+	"github.com/JackMatanky/lithos/internal/domain"
 	"github.com/JackMatanky/lithos/internal/ports/spi"
+	"github.com/JackMatanky/lithos/internal/shared/clock"
+	testutils "github.com/JackMatanky/lithos/tests/utils"
 )
 
 // TestTemplatePipelineIntegration tests the complete template processing
@@ -71,6 +76,7 @@ func TestTemplatePipelineIntegration(t *testing.T) {
 			fsAdapter,
 			templatePath,
 			expectedOutputFile,
+			pinnedRenderTime,
 		)
 		if err != nil {
 			t.Fatalf("Command execution failed: %v", err)
@@ -83,7 +89,7 @@ func TestTemplatePipelineIntegration(t *testing.T) {
 		}
 		actualOutput := string(actualBytes)
 
-		// Compare outputs (allowing for date differences in golden file)
+		// Compare outputs; the pinned clock makes dates deterministic
 		if !compareTemplateOutputs(expectedOutput, actualOutput) {
 			t.Errorf(
 				"Template output mismatch:\nExpected:\n%s\nActual:\n%s",
@@ -92,16 +98,111 @@ func TestTemplatePipelineIntegration(t *testing.T) {
 			)
 		}
 	})
+
+	// Test data-driven template against the basic-note golden file
+	t.Run("basic_note_with_input_params", func(t *testing.T) {
+		projectRoot := findProjectRoot(t)
+		templatePath := filepath.Join(
+			projectRoot,
+			"testdata",
+			"templates",
+			"basic-note.md",
+		)
+		paramsPath := filepath.Join(
+			projectRoot,
+			"testdata",
+			"golden",
+			"basic-note-input-params.json",
+		)
+		expectedOutputPath := filepath.Join(
+			projectRoot,
+			"testdata",
+			"golden",
+			"basic-note-expected.md",
+		)
+
+		expectedBytes, err := os.ReadFile(expectedOutputPath)
+		if err != nil {
+			t.Fatalf("Failed to read expected output: %v", err)
+		}
+
+		rc := loadGoldenParams(t, paramsPath)
+
+		// The golden file was rendered on 2024-01-15
+		renderedOn := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+		templateParser := templatedomain.NewStaticTemplateParserWithOptions(
+			templatedomain.FuncMapOptions{
+				Location: time.UTC,
+				Clock:    clock.Fixed(renderedOn),
+			},
+		)
+		templateEngine := templatedomain.NewTemplateEngine(
+			templateParser,
+			templatedomain.NewGoTemplateExecutor(),
+		)
+		templateRepo := templaterepo.NewFSAdapter(
+			fsAdapter,
+			templateParser,
+			testutils.NewMockConfigPort(filepath.Join(projectRoot, "testdata")),
+		)
+
+		tmpl, err := templateRepo.GetByPath(ctx, templatePath)
+		if err != nil {
+			t.Fatalf("Failed to load template: %v", err)
+		}
+
+		actualOutput, err := templateEngine.ExecuteParsedTemplate(ctx, tmpl, rc)
+		if err != nil {
+			t.Fatalf("Template execution failed: %v", err)
+		}
+
+		if !compareTemplateOutputs(string(expectedBytes), actualOutput) {
+			t.Errorf(
+				"Template output mismatch:\nExpected:\n%s\nActual:\n%s",
+				string(expectedBytes),
+				actualOutput,
+			)
+		}
+	})
 }
 
-// executeNewCommand executes the new command logic directly for testing.
+// loadGoldenParams reads the "parameters" object of a golden input params
+// file into a render context.
+func loadGoldenParams(t *testing.T, path string) domain.RenderContext {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read input params: %v", err)
+	}
+
+	var params struct {
+		Parameters map[string]interface{} `json:"parameters"`
+	}
+	if err = json.Unmarshal(content, &params); err != nil {
+		t.Fatalf("Failed to decode input params: %v", err)
+	}
+
+	rc := domain.NewRenderContext()
+	rc.Merge(params.Parameters)
+	return rc
+}
+
+// executeNewCommand executes the new command logic directly for testing,
+// with the render clock pinned to now.
 func executeNewCommand(
 	ctx context.Context,
 	fs spi.FileSystemPort,
 	templatePath, outputPath string,
+	now time.Time,
 ) error {
 	// Create template parser and executor from domain services
-	templateParser := templatedomain.NewStaticTemplateParser()
+	templateParser := templatedomain.NewStaticTemplateParserWithOptions(
+		templatedomain.FuncMapOptions{
+			Location: time.UTC,
+			Clock:    clock.Fixed(now),
+		},
+	)
 	templateExecutor := templatedomain.NewGoTemplateExecutor()
 
 	// Create template engine and repository
@@ -109,7 +210,11 @@ func executeNewCommand(
 		templateParser,
 		templateExecutor,
 	)
-	templateRepo := templaterepo.NewFSAdapter(fs, templateParser)
+	templateRepo := templaterepo.NewFSAdapter(
+		fs,
+		templateParser,
+		testutils.NewMockConfigPort(filepath.Dir(filepath.Dir(templatePath))),
+	)
 
 	// Get parsed template from repository
 	tmpl, err := templateRepo.GetByPath(ctx, templatePath)
@@ -118,7 +223,11 @@ func executeNewCommand(
 	}
 
 	// Execute parsed template
-	renderedContent, err := templateEngine.ExecuteParsedTemplate(ctx, tmpl)
+	renderedContent, err := templateEngine.ExecuteParsedTemplate(
+		ctx,
+		tmpl,
+		domain.NewRenderContext(),
+	)
 	if err != nil {
 		return err
 	}
@@ -127,30 +236,9 @@ func executeNewCommand(
 	return fs.WriteFileAtomic(outputPath, []byte(renderedContent))
 }
 
-// compareTemplateOutputs compares template outputs, allowing for dynamic
-// content like dates.
+// compareTemplateOutputs compares template outputs exactly, ignoring only
+// leading and trailing whitespace. Renders pin the clock, so dates in golden
+// files are compared like any other content.
 func compareTemplateOutputs(expected, actual string) bool {
-	expectedLines := strings.Split(strings.TrimSpace(expected), "\n")
-	actualLines := strings.Split(strings.TrimSpace(actual), "\n")
-
-	if len(expectedLines) != len(actualLines) {
-		return false
-	}
-
-	for i, expectedLine := range expectedLines {
-		actualLine := actualLines[i]
-
-		// Allow date lines to differ (for now function)
-		if strings.HasPrefix(expectedLine, "Created: ") &&
-			strings.HasPrefix(actualLine, "Created: ") {
-			continue
-		}
-
-		// Exact match for other lines
-		if expectedLine != actualLine {
-			return false
-		}
-	}
-
-	return true
+	return strings.TrimSpace(expected) == strings.TrimSpace(actual)
 }
@@ -4,6 +4,9 @@ package testutils
 
 import (
 	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
 	"strings"
 
 	"github.com/JackMatanky/lithos/internal/adapters/spi/config"
@@ -19,6 +22,7 @@ type MockFileSystemPort struct {
 	readError     error
 	readFileFunc  func(path string) ([]byte, error)
 	writeFileFunc func(path string, data []byte) error
+	statFunc      func(path string) (spi.FileInfo, error)
 }
 
 // NewMockFileSystemPort creates a new mock filesystem port.
@@ -30,6 +34,7 @@ func NewMockFileSystemPort() *MockFileSystemPort {
 		readError:     nil,
 		readFileFunc:  nil,
 		writeFileFunc: nil,
+		statFunc:      nil,
 	}
 }
 
@@ -71,8 +76,7 @@ func (m *MockFileSystemPort) Walk(root string, fn spi.WalkFunc) error {
 	}
 
 	for _, path := range m.walkPaths {
-		isDir := !strings.HasSuffix(path, ".json") &&
-			!strings.HasSuffix(path, ".md")
+		isDir := filepath.Ext(path) == ""
 		if err := fn(path, isDir); err != nil {
 			return err
 		}
@@ -80,6 +84,28 @@ func (m *MockFileSystemPort) Walk(root string, fn spi.WalkFunc) error {
 	return nil
 }
 
+// Stat implements spi.FileSystemPort.Stat.
+// Files added or written to the mock exist, as do directories containing them;
+// every other path reports an error wrapping fs.ErrNotExist.
+func (m *MockFileSystemPort) Stat(path string) (spi.FileInfo, error) {
+	if m.statFunc != nil {
+		return m.statFunc(path)
+	}
+
+	if data, exists := m.files[path]; exists {
+		return spi.FileInfo{Path: path, Size: int64(len(data))}, nil
+	}
+
+	prefix := strings.TrimSuffix(path, string(filepath.Separator)) +
+		string(filepath.Separator)
+	for filePath := range m.files {
+		if strings.HasPrefix(filePath, prefix) {
+			return spi.FileInfo{Path: path, IsDir: true}, nil
+		}
+	}
+	return spi.FileInfo{}, fmt.Errorf("stat %s: %w", path, fs.ErrNotExist)
+}
+
 // AddFile adds a file to the mock filesystem.
 func (m *MockFileSystemPort) AddFile(path string, content []byte) {
 	m.files[path] = content
@@ -119,6 +145,13 @@ func (m *MockFileSystemPort) SetWriteFileFunc(
 	m.writeFileFunc = fn
 }
 
+// SetStatFunc sets a custom function for Stat operations.
+func (m *MockFileSystemPort) SetStatFunc(
+	fn func(path string) (spi.FileInfo, error),
+) {
+	m.statFunc = fn
+}
+
 // MockConfigPort implements spi.ConfigPort for testing.
 type MockConfigPort struct {
 	cfg *config.Config